@@ -0,0 +1,248 @@
+// Package address encodes and decodes bitcoin addresses of derived keys.
+package address
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/bip44"
+)
+
+// Type is the output script type an address pays to.
+type Type uint8
+
+const (
+	P2PKH Type = iota
+	P2SH
+	P2WPKH
+	P2WSH
+	P2TR
+	// WitnessUnknown is a segwit output of a future witness version.
+	WitnessUnknown
+)
+
+const (
+	opDup         = 0x76
+	opHash160     = 0xa9
+	opEqual       = 0x87
+	opEqualVerify = 0x88
+	opCheckSig    = 0xac
+	op1           = 0x51
+)
+
+var (
+	ErrInvalidPublicKey       = errors.New("public key must be 33 bytes compressed")
+	ErrInvalidAddress         = errors.New("invalid address")
+	ErrWrongNetwork           = errors.New("address is for another network")
+	ErrInvalidWitnessVersion  = errors.New("witness version must be in [0, 16]")
+	ErrInvalidWitnessProgram  = errors.New("invalid witness program length")
+	ErrUnsupportedPurpose     = errors.New("no address type for the purpose")
+	ErrUnsupportedAddressType = errors.New("unsupported address type")
+)
+
+// Address is a decoded bitcoin address.
+type Address struct {
+	Type   Type
+	Params *Params
+	// Hash is the public key hash, script hash or witness program.
+	Hash           []byte
+	WitnessVersion byte
+}
+
+// NewP2PKH returns the legacy Base58Check address of the compressed public key.
+func NewP2PKH(pubKey []byte, params *Params) (*Address, error) {
+	hash, err := pubKeyHash(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{Type: P2PKH, Params: params, Hash: hash}, nil
+}
+
+// NewP2SH returns the Base58Check address paying to the redeem script.
+func NewP2SH(redeemScript []byte, params *Params) (*Address, error) {
+	hash, err := bip32.Hash160(redeemScript)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{Type: P2SH, Params: params, Hash: hash}, nil
+}
+
+// NewP2SHP2WPKH returns the nested segwit address, a P2SH address of the P2WPKH script.
+func NewP2SHP2WPKH(pubKey []byte, params *Params) (*Address, error) {
+	witness, err := NewP2WPKH(pubKey, params)
+	if err != nil {
+		return nil, err
+	}
+	return NewP2SH(witness.ScriptPubKey(), params)
+}
+
+// NewP2WPKH returns the native segwit v0 bech32 address of the compressed public key.
+func NewP2WPKH(pubKey []byte, params *Params) (*Address, error) {
+	hash, err := pubKeyHash(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{Type: P2WPKH, Params: params, Hash: hash}, nil
+}
+
+// NewP2WSH returns the native segwit v0 bech32 address of the sha256 of the witness script.
+func NewP2WSH(scriptHash []byte, params *Params) (*Address, error) {
+	if len(scriptHash) != 32 {
+		return nil, ErrInvalidWitnessProgram
+	}
+	return &Address{Type: P2WSH, Params: params, Hash: append([]byte{}, scriptHash...)}, nil
+}
+
+// NewP2TR returns the segwit v1 bech32m address of the BIP-86 key path only output key
+// of the compressed internal public key.
+func NewP2TR(pubKey []byte, params *Params) (*Address, error) {
+	if len(pubKey) != 33 {
+		return nil, ErrInvalidPublicKey
+	}
+	outputKey, err := TaprootOutputKey(pubKey[1:], nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Address{Type: P2TR, Params: params, Hash: outputKey, WitnessVersion: 1}, nil
+}
+
+// FromPublicKey returns the address of the derived key for the bip44 purpose:
+// P2PKH for BIP-44, P2SH-P2WPKH for BIP-49, P2WPKH for BIP-84 and P2TR for BIP-86.
+func FromPublicKey(key *bip32.PublicKey, purpose bip44.Purpose, params *Params) (*Address, error) {
+	switch purpose {
+	case bip44.BIP44Purpose:
+		return NewP2PKH(key.Data, params)
+	case bip44.BIP49Purpose:
+		return NewP2SHP2WPKH(key.Data, params)
+	case bip44.BIP84Purpose:
+		return NewP2WPKH(key.Data, params)
+	case bip44.BIP86Purpose:
+		return NewP2TR(key.Data, params)
+	}
+	return nil, ErrUnsupportedPurpose
+}
+
+// String encodes the address.
+func (a *Address) String() string {
+	switch a.Type {
+	case P2PKH:
+		encoded, _ := bip32.Base58CheckEncode(append([]byte{a.Params.PubKeyHashID}, a.Hash...))
+		return encoded
+	case P2SH:
+		encoded, _ := bip32.Base58CheckEncode(append([]byte{a.Params.ScriptHashID}, a.Hash...))
+		return encoded
+	default:
+		encoded, _ := encodeSegwitAddress(a.Params.Bech32HRP, a.WitnessVersion, a.Hash)
+		return encoded
+	}
+}
+
+// ScriptPubKey returns the output script paying to the address.
+func (a *Address) ScriptPubKey() []byte {
+	switch a.Type {
+	case P2PKH:
+		script := []byte{opDup, opHash160, byte(len(a.Hash))}
+		script = append(script, a.Hash...)
+		return append(script, opEqualVerify, opCheckSig)
+	case P2SH:
+		script := []byte{opHash160, byte(len(a.Hash))}
+		script = append(script, a.Hash...)
+		return append(script, opEqual)
+	default:
+		version := byte(0)
+		if a.WitnessVersion > 0 {
+			version = op1 + a.WitnessVersion - 1
+		}
+		return append([]byte{version, byte(len(a.Hash))}, a.Hash...)
+	}
+}
+
+// Decode decodes a mainnet, testnet or regtest address.
+func Decode(addr string) (*Address, error) {
+	for _, params := range networks {
+		decoded, err := DecodeForNetwork(addr, params)
+		if err != ErrWrongNetwork {
+			return decoded, err
+		}
+	}
+	return nil, ErrInvalidAddress
+}
+
+// DecodeForNetwork decodes an address, ErrWrongNetwork is returned when it belongs to
+// another network.
+func DecodeForNetwork(addr string, params *Params) (*Address, error) {
+	if sep := strings.LastIndexByte(addr, '1'); sep > 0 && strings.EqualFold(addr[:sep], params.Bech32HRP) {
+		version, program, err := decodeSegwitAddress(params.Bech32HRP, addr)
+		if err != nil {
+			return nil, err
+		}
+		return newWitnessAddress(version, program, params), nil
+	}
+
+	decoded, err := bip32.Base58CheckDecode(addr)
+	if err != nil {
+		// a bech32 address of another network is not base58
+		if _, _, _, bech32Err := Bech32Decode(addr); bech32Err == nil {
+			return nil, ErrWrongNetwork
+		}
+		return nil, ErrInvalidAddress
+	}
+	if len(decoded) != 21 {
+		return nil, ErrInvalidAddress
+	}
+
+	switch decoded[0] {
+	case params.PubKeyHashID:
+		return &Address{Type: P2PKH, Params: params, Hash: decoded[1:]}, nil
+	case params.ScriptHashID:
+		return &Address{Type: P2SH, Params: params, Hash: decoded[1:]}, nil
+	}
+	return nil, ErrWrongNetwork
+}
+
+// Validate checks the address is valid for the network.
+func Validate(addr string, params *Params) error {
+	_, err := DecodeForNetwork(addr, params)
+	return err
+}
+
+// IsForPublicKey reports whether the address pays to the compressed public key.
+func (a *Address) IsForPublicKey(pubKey []byte) bool {
+	var expected *Address
+	var err error
+	switch a.Type {
+	case P2PKH:
+		expected, err = NewP2PKH(pubKey, a.Params)
+	case P2SH:
+		expected, err = NewP2SHP2WPKH(pubKey, a.Params)
+	case P2WPKH:
+		expected, err = NewP2WPKH(pubKey, a.Params)
+	case P2TR:
+		expected, err = NewP2TR(pubKey, a.Params)
+	default:
+		return false
+	}
+	return err == nil && bytes.Equal(expected.Hash, a.Hash)
+}
+
+func newWitnessAddress(version byte, program []byte, params *Params) *Address {
+	addrType := WitnessUnknown
+	switch {
+	case version == 0 && len(program) == 20:
+		addrType = P2WPKH
+	case version == 0 && len(program) == 32:
+		addrType = P2WSH
+	case version == 1 && len(program) == 32:
+		addrType = P2TR
+	}
+	return &Address{Type: addrType, Params: params, Hash: program, WitnessVersion: version}
+}
+
+func pubKeyHash(pubKey []byte) ([]byte, error) {
+	if len(pubKey) != 33 || (pubKey[0] != 0x02 && pubKey[0] != 0x03) {
+		return nil, ErrInvalidPublicKey
+	}
+	return bip32.Hash160(pubKey)
+}
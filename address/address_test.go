@@ -0,0 +1,144 @@
+package address
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/bip44"
+)
+
+// seed of the mnemonic "abandon abandon ... about" used by the BIP-49/84/86 test vectors
+const testSeed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+func TestFromPublicKey(t *testing.T) {
+	seed, _ := hex.DecodeString(testSeed)
+	tests := []struct {
+		name    string
+		network bip44.Network
+		purpose bip44.Purpose
+		params  *Params
+		change  bip44.Change
+		index   uint32
+		want    string
+		script  string
+	}{
+		{"bip44", bip44.Network{Coin: bip44.BitcoinCoinType}, bip44.BIP44Purpose, &MainNetParams, bip44.ExternalChain, 0,
+			"1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", "76a914d986ed01b7a22225a70edbf2ba7cfb63a15cb3aa88ac"},
+		{"bip49 testnet", bip44.Network{SegwitEnabled: true, Coin: bip44.TestnetCoinType}, bip44.BIP49Purpose, &TestNetParams, bip44.ExternalChain, 0,
+			"2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2", "a914336caa13e08b96080a32b5d818d59b4ab3b3674287"},
+		{"bip84", bip44.Network{SegwitEnabled: true, Coin: bip44.BitcoinCoinType}, bip44.BIP84Purpose, &MainNetParams, bip44.ExternalChain, 0,
+			"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "0014c0cebcd6c3d3ca8c75dc5ec62ebe55330ef910e2"},
+		{"bip84 second", bip44.Network{SegwitEnabled: true, Coin: bip44.BitcoinCoinType}, bip44.BIP84Purpose, &MainNetParams, bip44.ExternalChain, 1,
+			"bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", ""},
+		{"bip84 change", bip44.Network{SegwitEnabled: true, Coin: bip44.BitcoinCoinType}, bip44.BIP84Purpose, &MainNetParams, bip44.InternalChain, 0,
+			"bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el", ""},
+		{"bip84 testnet", bip44.Network{SegwitEnabled: true, Coin: bip44.TestnetCoinType}, bip44.BIP84Purpose, &TestNetParams, bip44.ExternalChain, 0,
+			"tb1q6rz28mcfaxtmd6v789l9rrlrusdprr9pqcpvkl", ""},
+		{"bip84 regtest", bip44.Network{SegwitEnabled: true, Coin: bip44.TestnetCoinType, RegTest: true}, bip44.BIP84Purpose, &RegTestParams, bip44.ExternalChain, 0,
+			"bcrt1q6rz28mcfaxtmd6v789l9rrlrusdprr9pz3cppk", ""},
+		{"bip86", bip44.Network{SegwitEnabled: true, Coin: bip44.BitcoinCoinType}, bip44.BIP86Purpose, &MainNetParams, bip44.ExternalChain, 0,
+			"bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr", "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"},
+		{"bip86 second", bip44.Network{SegwitEnabled: true, Coin: bip44.BitcoinCoinType}, bip44.BIP86Purpose, &MainNetParams, bip44.ExternalChain, 1,
+			"bc1p4qhjn9zdvkux4e44uhx8tc55attvtyu358kutcqkudyccelu0was9fqzwh", ""},
+		{"bip86 change", bip44.Network{SegwitEnabled: true, Coin: bip44.BitcoinCoinType}, bip44.BIP86Purpose, &MainNetParams, bip44.InternalChain, 0,
+			"bc1p3qkhfews2uk44qtvauqyr2ttdsw7svhkl9nkm9s9c3x4ax5h60wqwruhk7", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParamsForNetwork(tt.network); got != tt.params {
+				t.Errorf("ParamsForNetwork() = %s, want %s", got.Name, tt.params.Name)
+			}
+			account, err := bip44.DeriveAccount(seed, tt.network, tt.purpose, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := account.AddressPublicKey(tt.change, tt.index)
+			if err != nil {
+				t.Fatal(err)
+			}
+			addr, err := FromPublicKey(key, tt.purpose, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr.String() != tt.want {
+				t.Errorf("String() = %s, want %s", addr, tt.want)
+			}
+			if tt.script != "" && hex.EncodeToString(addr.ScriptPubKey()) != tt.script {
+				t.Errorf("ScriptPubKey() = %x, want %s", addr.ScriptPubKey(), tt.script)
+			}
+			if !addr.IsForPublicKey(key.Data) {
+				t.Errorf("IsForPublicKey() = false")
+			}
+
+			decoded, err := DecodeForNetwork(tt.want, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if decoded.Type != addr.Type || !bytes.Equal(decoded.Hash, addr.Hash) {
+				t.Errorf("DecodeForNetwork() = %+v, want %+v", decoded, addr)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		addr     string
+		addrType Type
+		network  string
+		script   string
+		err      error
+	}{
+		{"1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", P2PKH, "mainnet", "76a914d986ed01b7a22225a70edbf2ba7cfb63a15cb3aa88ac", nil},
+		{"2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2", P2SH, "testnet", "a914336caa13e08b96080a32b5d818d59b4ab3b3674287", nil},
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", P2WPKH, "mainnet", "0014751e76e8199196d454941c45d1b3a323f1433bd6", nil},
+		{"tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7", P2WSH, "testnet", "00201863143c14c5166804bd19203356da136c985678cd4d27a1b8c6329604903262", nil},
+		{"bcrt1q6rz28mcfaxtmd6v789l9rrlrusdprr9pz3cppk", P2WPKH, "regtest", "", nil},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", P2TR, "mainnet", "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798", nil},
+		{"bc1pw508d6qejxtdg4y5r3zarvary0c5xw7kw508d6qejxtdg4y5r3zarvary0c5xw7kt5nd6y", WitnessUnknown, "mainnet", "5128751e76e8199196d454941c45d1b3a323f1433bd6751e76e8199196d454941c45d1b3a323f1433bd6", nil},
+		{"1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabB", 0, "", "", ErrInvalidAddress},
+		// bech32 checksum of a v1 program
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", 0, "", "", ErrBech32Checksum},
+		{"bc1pw5dgrnzv", 0, "", "", ErrInvalidWitnessProgram},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			addr, err := Decode(tt.addr)
+			if err != tt.err {
+				t.Fatalf("Decode() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if addr.Type != tt.addrType {
+				t.Errorf("Type = %d, want %d", addr.Type, tt.addrType)
+			}
+			if addr.Params.Name != tt.network {
+				t.Errorf("Params = %s, want %s", addr.Params.Name, tt.network)
+			}
+			if tt.script != "" && hex.EncodeToString(addr.ScriptPubKey()) != tt.script {
+				t.Errorf("ScriptPubKey() = %x, want %s", addr.ScriptPubKey(), tt.script)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		addr   string
+		params *Params
+		err    error
+	}{
+		{"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", &MainNetParams, nil},
+		{"bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", &TestNetParams, ErrWrongNetwork},
+		{"tb1q6rz28mcfaxtmd6v789l9rrlrusdprr9pqcpvkl", &RegTestParams, ErrWrongNetwork},
+		{"1LqBGSKuX5yYUonjxT5qGfpUsXKYYWeabA", &TestNetParams, ErrWrongNetwork},
+		{"2Mww8dCYPUpKHofjgcXcBCEGmniw9CoaiD2", &RegTestParams, nil},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.addr, tt.params); err != tt.err {
+			t.Errorf("Validate(%s, %s) = %v, want %v", tt.addr, tt.params.Name, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package address
+
+import (
+	"errors"
+	"strings"
+)
+
+// Bech32 (BIP-173) and Bech32m (BIP-350) encoding of segwit addresses.
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// Bech32Variant selects the checksum constant.
+type Bech32Variant int
+
+const (
+	Bech32  Bech32Variant = 1
+	Bech32m Bech32Variant = 0x2bc830a3
+)
+
+var (
+	ErrInvalidBech32      = errors.New("invalid bech32 string")
+	ErrBech32MixedCase    = errors.New("bech32 string must not mix upper and lower case")
+	ErrBech32Checksum     = errors.New("invalid bech32 checksum")
+	ErrBech32InvalidPad   = errors.New("invalid bech32 padding")
+	ErrBech32StringLength = errors.New("bech32 string must be at most 90 characters")
+)
+
+var bech32Generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (b>>uint(i))&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+func bech32Checksum(hrp string, data []byte, variant Bech32Variant) []byte {
+	values := append(bech32HRPExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ uint32(variant)
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte((polymod >> uint(5*(5-i))) & 31)
+	}
+	return checksum
+}
+
+// Bech32Encode encodes the 5-bit data with the human readable part.
+func Bech32Encode(hrp string, data []byte, variant Bech32Variant) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	combined := append(append([]byte{}, data...), bech32Checksum(hrp, data, variant)...)
+	for _, d := range combined {
+		if d > 31 {
+			return "", ErrInvalidBech32
+		}
+		sb.WriteByte(bech32Charset[d])
+	}
+	return sb.String(), nil
+}
+
+// Bech32Decode decodes a bech32 or bech32m string into its lower case human readable part,
+// the 5-bit data without checksum, and the checksum variant.
+func Bech32Decode(s string) (string, []byte, Bech32Variant, error) {
+	if len(s) > 90 {
+		return "", nil, 0, ErrBech32StringLength
+	}
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, 0, ErrBech32MixedCase
+	}
+
+	pos := strings.LastIndexByte(lower, '1')
+	if pos < 1 || pos+7 > len(lower) {
+		return "", nil, 0, ErrInvalidBech32
+	}
+	hrp := lower[:pos]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, ErrInvalidBech32
+		}
+	}
+
+	data := make([]byte, 0, len(lower)-pos-1)
+	for i := pos + 1; i < len(lower); i++ {
+		d := strings.IndexByte(bech32Charset, lower[i])
+		if d < 0 {
+			return "", nil, 0, ErrInvalidBech32
+		}
+		data = append(data, byte(d))
+	}
+
+	variant := Bech32Variant(bech32Polymod(append(bech32HRPExpand(hrp), data...)))
+	if variant != Bech32 && variant != Bech32m {
+		return "", nil, 0, ErrBech32Checksum
+	}
+	return hrp, data[:len(data)-6], variant, nil
+}
+
+// convertBits regroups the bits of data from fromBits to toBits per element.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+	maxValue := uint32(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+	for _, value := range data {
+		if uint32(value)>>fromBits != 0 {
+			return nil, ErrInvalidBech32
+		}
+		acc = acc<<fromBits | uint32(value)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, ErrBech32InvalidPad
+	}
+	return result, nil
+}
+
+// encodeSegwitAddress encodes a witness program, bech32 for version 0 and bech32m otherwise.
+func encodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if err := validateWitnessProgram(version, program); err != nil {
+		return "", err
+	}
+	data, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	variant := Bech32m
+	if version == 0 {
+		variant = Bech32
+	}
+	return Bech32Encode(hrp, append([]byte{version}, data...), variant)
+}
+
+// decodeSegwitAddress decodes a segwit address of the human readable part.
+func decodeSegwitAddress(hrp, addr string) (byte, []byte, error) {
+	decodedHRP, data, variant, err := Bech32Decode(addr)
+	if err != nil {
+		return 0, nil, err
+	}
+	if decodedHRP != hrp {
+		return 0, nil, ErrWrongNetwork
+	}
+	if len(data) < 1 {
+		return 0, nil, ErrInvalidWitnessProgram
+	}
+
+	version := data[0]
+	if (version == 0 && variant != Bech32) || (version != 0 && variant != Bech32m) {
+		return 0, nil, ErrBech32Checksum
+	}
+	program, err := convertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := validateWitnessProgram(version, program); err != nil {
+		return 0, nil, err
+	}
+	return version, program, nil
+}
+
+// validateWitnessProgram checks the version and program length rules of BIP-141.
+func validateWitnessProgram(version byte, program []byte) error {
+	if version > 16 {
+		return ErrInvalidWitnessVersion
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return ErrInvalidWitnessProgram
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return ErrInvalidWitnessProgram
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package address
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBech32Decode(t *testing.T) {
+	tests := []struct {
+		str     string
+		variant Bech32Variant
+		valid   bool
+	}{
+		// BIP-173
+		{"A12UEL5L", Bech32, true},
+		{"a12uel5l", Bech32, true},
+		{"an83characterlonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1tt5tgs", Bech32, true},
+		{"abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw", Bech32, true},
+		{"split1checkupstagehandshakeupstreamerranterredcaperred2y9e3w", Bech32, true},
+		{"?1ezyfcl", Bech32, true},
+		{"\x201nwldj5", 0, false},
+		{"an84characterslonghumanreadablepartthatcontainsthenumber1andtheexcludedcharactersbio1569pvx", 0, false},
+		{"pzry9x0s0muk", 0, false},
+		{"1pzry9x0s0muk", 0, false},
+		{"x1b4n0q5v", 0, false},
+		{"li1dgmt3", 0, false},
+		{"A1G7SGD8", 0, false},
+		{"10a06t8", 0, false},
+		{"1qzzfhee", 0, false},
+		{"A12Uel5l", 0, false},
+		// BIP-350
+		{"A1LQFN3A", Bech32m, true},
+		{"a1lqfn3a", Bech32m, true},
+		{"abcdef1l7aum6echk45nj3s0wdvt2fg8x9yrzpqzd3ryx", Bech32m, true},
+		{"split1checkupstagehandshakeupstreamerranterredcaperredlc445v", Bech32m, true},
+		{"?1v759aa", Bech32m, true},
+		{"qyrz8wqd2c9m", 0, false},
+		{"M1VUXWEZ", 0, false},
+		{"16plkw9", 0, false},
+		{"1p2gdwpf", 0, false},
+	}
+	for _, tt := range tests {
+		hrp, data, variant, err := Bech32Decode(tt.str)
+		if (err == nil) != tt.valid {
+			t.Errorf("Bech32Decode(%q) error = %v, want valid %v", tt.str, err, tt.valid)
+			continue
+		}
+		if !tt.valid {
+			continue
+		}
+		if variant != tt.variant {
+			t.Errorf("Bech32Decode(%q) variant = %x, want %x", tt.str, variant, tt.variant)
+		}
+		encoded, err := Bech32Encode(hrp, data, variant)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoded != strings.ToLower(tt.str) {
+			t.Errorf("Bech32Encode() = %s, want %s", encoded, strings.ToLower(tt.str))
+		}
+	}
+}
+
+func TestDecodeSegwitAddressInvalid(t *testing.T) {
+	tests := []struct {
+		hrp  string
+		addr string
+	}{
+		{"tb", "tc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut"},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd"},
+		{"tb", "tb1z0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqglt7rf"},
+		{"bc", "BC1S0XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ54WELL"},
+		{"bc", "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh"},
+		{"tb", "tb1q0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq24jc47"},
+		{"bc", "bc1p38j9r5y49hruaue7wxjce0updqjuyyx0kh56v8s25huc6995vvpql3jow4"},
+		{"bc", "BC130XLXVLHEMJA6C4DQV22UAPCTQUPFHLXM9H8Z3K2E72Q4K9HCZ7VQ7ZWS8R"},
+		{"bc", "bc1pw5dgrnzv"},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v8n0nx0muaewav253zgeav"},
+		{"bc", "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P"},
+		{"tb", "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq47Zagq"},
+		{"bc", "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7v07qwwzcrf"},
+		{"tb", "tb1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vpggkg4j"},
+		{"bc", "bc1gmk9yu"},
+	}
+	for _, tt := range tests {
+		if _, _, err := decodeSegwitAddress(tt.hrp, tt.addr); err == nil {
+			t.Errorf("decodeSegwitAddress(%s) should fail", tt.addr)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+module github.com/dubuqingfeng/signer/address
+
+go 1.18
+
+require (
+	github.com/dubuqingfeng/signer/bip32 v0.0.0
+	github.com/dubuqingfeng/signer/bip44 v0.0.0
+	github.com/dubuqingfeng/signer/secp256k1-go v0.0.0
+	github.com/mndrix/btcutil v0.0.0-20130527213604-d3a63a5752ec
+)
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/crypto v0.9.0 // indirect
+)
+
+replace (
+	github.com/dubuqingfeng/signer/bip32 => ../bip32
+	github.com/dubuqingfeng/signer/bip44 => ../bip44
+	github.com/dubuqingfeng/signer/secp256k1-go => ../secp256k1-go
+)
@@ -0,0 +1,37 @@
+package address
+
+import (
+	"github.com/dubuqingfeng/signer/bip44"
+)
+
+// Params are the address prefixes of a bitcoin network.
+type Params struct {
+	Name         string
+	PubKeyHashID byte   // P2PKH version byte
+	ScriptHashID byte   // P2SH version byte
+	Bech32HRP    string // segwit human readable part
+}
+
+var (
+	// MainNetParams are the bitcoin main network prefixes: 1..., 3..., bc1...
+	MainNetParams = Params{Name: "mainnet", PubKeyHashID: 0x00, ScriptHashID: 0x05, Bech32HRP: "bc"}
+	// TestNetParams are the bitcoin test network prefixes: m/n..., 2..., tb1...
+	TestNetParams = Params{Name: "testnet", PubKeyHashID: 0x6f, ScriptHashID: 0xc4, Bech32HRP: "tb"}
+	// RegTestParams are the bitcoin regression test network prefixes: m/n..., 2..., bcrt1...
+	RegTestParams = Params{Name: "regtest", PubKeyHashID: 0x6f, ScriptHashID: 0xc4, Bech32HRP: "bcrt"}
+)
+
+// networks are the params tried by Decode, in order.
+var networks = []*Params{&MainNetParams, &TestNetParams, &RegTestParams}
+
+// ParamsForNetwork returns the params of a bip44 network, the regression
+// test ones for a testnet coin type network with RegTest set.
+func ParamsForNetwork(network bip44.Network) *Params {
+	switch {
+	case network.IsTestnet() && network.RegTest:
+		return &RegTestParams
+	case network.IsTestnet():
+		return &TestNetParams
+	}
+	return &MainNetParams
+}
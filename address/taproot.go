@@ -0,0 +1,67 @@
+package address
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+	"github.com/mndrix/btcutil"
+)
+
+var (
+	ErrInvalidXOnlyKey = errors.New("x-only public key is not on the curve")
+	ErrInvalidTweak    = errors.New("taproot tweak is out of range")
+)
+
+var curve = btcutil.Secp256k1()
+
+// TaprootOutputKey returns the x-only output key Q = P + int(hashTapTweak(P || merkleRoot))G
+// of the x-only internal key P, see BIP-341. A nil merkle root is the BIP-86 key path only output.
+func TaprootOutputKey(internalKey []byte, merkleRoot []byte) ([]byte, error) {
+	px, py, err := liftX(internalKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tweak := new(big.Int).SetBytes(secp256k1.TaggedHash("TapTweak", internalKey, merkleRoot))
+	if tweak.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidTweak
+	}
+	tx, ty := curve.ScalarBaseMult(tweak.Bytes())
+	if tx.Cmp(px) == 0 {
+		// Q would be the point at infinity or require doubling, neither happens for a valid tweak.
+		return nil, ErrInvalidTweak
+	}
+	qx, _ := curve.Add(px, py, tx, ty)
+
+	outputKey := make([]byte, 32)
+	qx.FillBytes(outputKey)
+	return outputKey, nil
+}
+
+// liftX returns the point with the x coordinate and an even y, see BIP-340.
+func liftX(xOnly []byte) (*big.Int, *big.Int, error) {
+	if len(xOnly) != 32 {
+		return nil, nil, ErrInvalidXOnlyKey
+	}
+	params := curve.Params()
+	x := new(big.Int).SetBytes(xOnly)
+	if x.Cmp(params.P) >= 0 {
+		return nil, nil, ErrInvalidXOnlyKey
+	}
+
+	// y² = x³ + 7, y = (y²)^((p+1)/4)
+	ySquared := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	ySquared.Add(ySquared, params.B)
+	ySquared.Mod(ySquared, params.P)
+	exp := new(big.Int).Add(params.P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(ySquared, exp, params.P)
+	if new(big.Int).Exp(y, big.NewInt(2), params.P).Cmp(ySquared) != 0 {
+		return nil, nil, ErrInvalidXOnlyKey
+	}
+	if y.Bit(0) == 1 {
+		y.Sub(params.P, y)
+	}
+	return x, y, nil
+}
@@ -0,0 +1,94 @@
+package bip32
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// ErrInvalidBase58 is returned when a string contains characters outside the base58 alphabet.
+	ErrInvalidBase58 = errors.New("invalid base58 string")
+	// ErrInvalidChecksum is returned when the base58check checksum does not match.
+	ErrInvalidChecksum = errors.New("invalid checksum")
+)
+
+var (
+	bigRadix = big.NewInt(58)
+	bigZero  = big.NewInt(0)
+)
+
+// Base58Encode encodes a byte slice to a base58 string, leading zero bytes are encoded as '1'.
+func Base58Encode(data []byte) string {
+	x := new(big.Int).SetBytes(data)
+
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	mod := new(big.Int)
+	for x.Cmp(bigZero) > 0 {
+		x.DivMod(x, bigRadix, mod)
+		encoded = append(encoded, base58Alphabet[mod.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, base58Alphabet[0])
+	}
+
+	// reverse
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
+
+// Base58Decode decodes a base58 string to a byte slice.
+func Base58Decode(s string) ([]byte, error) {
+	x := new(big.Int)
+	for i := 0; i < len(s); i++ {
+		idx := bytes.IndexByte([]byte(base58Alphabet), s[i])
+		if idx < 0 {
+			return nil, ErrInvalidBase58
+		}
+		x.Mul(x, bigRadix)
+		x.Add(x, big.NewInt(int64(idx)))
+	}
+
+	var zeros int
+	for zeros < len(s) && s[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	return append(make([]byte, zeros), x.Bytes()...), nil
+}
+
+// Base58CheckEncode appends the double sha256 checksum and encodes the result to base58.
+func Base58CheckEncode(data []byte) (string, error) {
+	withChecksum, err := addChecksumToBytes(append([]byte{}, data...))
+	if err != nil {
+		return "", err
+	}
+	return Base58Encode(withChecksum), nil
+}
+
+// Base58CheckDecode decodes a base58 string and verifies its trailing 4 bytes checksum.
+func Base58CheckDecode(s string) ([]byte, error) {
+	decoded, err := Base58Decode(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(decoded) < 4 {
+		return nil, ErrInvalidChecksum
+	}
+
+	data := decoded[:len(decoded)-4]
+	expected, err := checksum(data)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expected, decoded[len(decoded)-4:]) {
+		return nil, ErrInvalidChecksum
+	}
+	return data, nil
+}
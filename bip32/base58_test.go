@@ -0,0 +1,65 @@
+package bip32
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_base58Encode(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{
+			name: "empty",
+			data: []byte{},
+			want: "",
+		},
+		{
+			name: "leading zeros",
+			data: []byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd},
+			want: "11233QC4",
+		},
+		{
+			name: "hello world",
+			data: []byte("Hello World!"),
+			want: "2NEpo7TZRRrLZSi2U",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Base58Encode(tt.data); got != tt.want {
+				t.Errorf("Base58Encode() = %v, want %v", got, tt.want)
+			}
+			got, err := Base58Decode(tt.want)
+			if err != nil {
+				t.Fatalf("Base58Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.data) {
+				t.Errorf("Base58Decode() = %v, want %v", got, tt.data)
+			}
+		})
+	}
+}
+
+func Test_base58CheckDecode(t *testing.T) {
+	encoded, err := Base58CheckEncode([]byte{0x00, 0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Base58CheckDecode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, []byte{0x00, 0x01, 0x02}) {
+		t.Errorf("Base58CheckDecode() = %v", got)
+	}
+
+	if _, err := Base58CheckDecode(encoded[:len(encoded)-1] + "z"); err != ErrInvalidChecksum {
+		t.Errorf("Base58CheckDecode() error = %v, want %v", err, ErrInvalidChecksum)
+	}
+	if _, err := Base58Decode("0OIl"); err != ErrInvalidBase58 {
+		t.Errorf("Base58Decode() error = %v, want %v", err, ErrInvalidBase58)
+	}
+}
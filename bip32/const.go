@@ -5,7 +5,13 @@ const (
 	PublicKeyPrefix = 0x0488b21e
 	// PrivateKeyPrefix is the type of private key
 	PrivateKeyPrefix = 0x0488ade4
-	ChildIndexLen    = 4
+	// TestnetPublicKeyPrefix is the type of testnet public key, tpub
+	TestnetPublicKeyPrefix = 0x043587cf
+	// TestnetPrivateKeyPrefix is the type of testnet private key, tprv
+	TestnetPrivateKeyPrefix = 0x04358394
+	ChildIndexLen           = 4
+	// SerializedKeyLen is the length of a serialized extended key without checksum
+	SerializedKeyLen = 78
 )
 
 // HardenedKeyZeroIndex 强化衍生起始索引
@@ -2,4 +2,7 @@ module github.com/dubuqingfeng/signer/bip32
 
 go 1.18
 
-require github.com/mndrix/btcutil v0.0.0-20130527213604-d3a63a5752ec // indirect
+require (
+	github.com/mndrix/btcutil v0.0.0-20130527213604-d3a63a5752ec
+	golang.org/x/crypto v0.9.0
+)
@@ -5,13 +5,10 @@ import (
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/binary"
-	"encoding/hex"
 	"errors"
 	"github.com/mndrix/btcutil"
 	"math"
 	"math/big"
-	"strconv"
-	"strings"
 )
 
 var (
@@ -20,12 +17,29 @@ var (
 	ErrHardenedKey          = errors.New("hardened key")
 	ErrDeriveBeyondMaxDepth = errors.New("cannot derive a key with more than 255 depth")
 	ErrInvalidPath          = errors.New("invalid path")
+	// ErrInvalidChild is returned when parse256(IL) >= n or the derived key is zero.
+	ErrInvalidChild = errors.New("the derived child key is invalid, proceed with the next index")
+	// ErrInvalidKeyLength is returned when a serialized extended key is not 78 bytes.
+	ErrInvalidKeyLength = errors.New("the serialized extended key length is invalid")
+	// ErrUnknownVersion is returned when the version bytes of an extended key are unknown.
+	ErrUnknownVersion = errors.New("unknown extended key version")
+	// ErrInvalidFingerprint is returned when a master key has a non-zero parent fingerprint.
+	ErrInvalidFingerprint = errors.New("zero depth with non-zero parent fingerprint")
+	// ErrInvalidChildIndex is returned when a master key has a non-zero child index.
+	ErrInvalidChildIndex = errors.New("zero depth with non-zero child index")
+	ErrNotPrivateKey     = errors.New("not a private extended key")
 )
 
 var (
 	curve = btcutil.Secp256k1()
 )
 
+// ExtendedKey is implemented by *PublicKey and *PrivateKey.
+type ExtendedKey interface {
+	Serialize() []byte
+	String() string
+}
+
 // PublicKey is the structure layout for an extended public key.
 type PublicKey struct {
 	ChainCode  []byte
@@ -62,15 +76,106 @@ func NewMasterKey(seed []byte) (*PrivateKey, error) {
 	return key, nil
 }
 
+// NewMasterKeyFromKeyAndChainCode creates a master key from the split HMAC result of the seed.
 func NewMasterKeyFromKeyAndChainCode(privateKey, chainCode []byte) (*PrivateKey, error) {
-	return &PrivateKey{PublicKey: PublicKey{ChainCode: chainCode}, Data: privateKey}, nil
+	// In case parse256(IL) is 0 or parse256(IL) >= n, the master key is invalid.
+	if !isValidPrivateKey(privateKey) {
+		return nil, ErrInvalidKey
+	}
+
+	return &PrivateKey{
+		PublicKey: PublicKey{
+			ChainCode: chainCode,
+			ParentFP:  []byte{0x0, 0x0, 0x0, 0x0},
+			Version:   uint32ToBytes(PublicKeyPrefix),
+		},
+		Data:    privateKey,
+		Version: uint32ToBytes(PrivateKeyPrefix),
+	}, nil
+}
+
+// NewMasterKeyFromExtendKey creates a private key from a Base58Check encoded xprv, yprv, zprv... string.
+func NewMasterKeyFromExtendKey(key string) (*PrivateKey, error) {
+	extendedKey, err := ParseExtendedKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := extendedKey.(*PrivateKey)
+	if !ok {
+		return nil, ErrNotPrivateKey
+	}
+	return privateKey, nil
 }
 
-func NewMasterKeyFromExtendKey(key *PublicKey) (*PrivateKey, error) {
-	return nil, nil
+// ParseExtendedKey decodes a Base58Check encoded extended key string of any SLIP-132 version,
+// e.g. xpub, yprv or Zpub, the result is a *PublicKey or a *PrivateKey depending on the version bytes.
+func ParseExtendedKey(key string) (ExtendedKey, error) {
+	data, err := Base58CheckDecode(key)
+	if err != nil {
+		return nil, err
+	}
+	return DeserializeExtendedKey(data)
+}
+
+// DeserializeExtendedKey decodes the 78 bytes BIP-32 serialization of an extended key.
+func DeserializeExtendedKey(data []byte) (ExtendedKey, error) {
+	if len(data) != SerializedKeyLen {
+		return nil, ErrInvalidKeyLength
+	}
+
+	version := data[:4]
+	level := data[4]
+	parentFP := data[5:9]
+	childIndex := binary.BigEndian.Uint32(data[9:13])
+	chainCode := data[13:45]
+	keyData := data[45:78]
+
+	if level == 0 {
+		if !bytes.Equal(parentFP, []byte{0x0, 0x0, 0x0, 0x0}) {
+			return nil, ErrInvalidFingerprint
+		}
+		if childIndex != 0 {
+			return nil, ErrInvalidChildIndex
+		}
+	}
+
+	publicKey := PublicKey{
+		ChainCode:  append([]byte{}, chainCode...),
+		ChildIndex: childIndex,
+		Level:      level,
+		ParentFP:   append([]byte{}, parentFP...),
+		Version:    append([]byte{}, version...),
+	}
+
+	keyVersion, isPrivate, err := LookupVersion(binary.BigEndian.Uint32(version))
+	if err != nil {
+		return nil, err
+	}
+
+	if isPrivate {
+		// A private key is prefixed with 0x00 and must be in the range [1, n-1].
+		if keyData[0] != 0x0 || !isValidPrivateKey(keyData[1:]) {
+			return nil, ErrInvalidKey
+		}
+		publicKey.Version = uint32ToBytes(keyVersion.Public)
+		return &PrivateKey{
+			PublicKey: publicKey,
+			Data:      append([]byte{}, keyData[1:]...),
+			Version:   append([]byte{}, version...),
+		}, nil
+	}
+
+	// A public key must be a compressed point on the curve.
+	if _, _, err := expandPublicKey(keyData); err != nil {
+		return nil, err
+	}
+	publicKey.Data = append([]byte{}, keyData...)
+	return &publicKey, nil
 }
 
-// Derive CKD priv derives a private key from a parent private key and a child index.
+// Derive is CKDpub: it derives a child public key from a parent public key and a child index.
+// Only non-hardened children can be derived from a public key.
 func (k *PublicKey) Derive(childIdx uint32) (*PublicKey, error) {
 	// HardenedKey
 	if childIdx >= HardenedKeyZeroIndex {
@@ -79,10 +184,10 @@ func (k *PublicKey) Derive(childIdx uint32) (*PublicKey, error) {
 	if k.Level == math.MaxUint8 {
 		return nil, ErrDeriveBeyondMaxDepth
 	}
-	// concatenate data and index
-	data := make([]byte, 4)
-	binary.BigEndian.PutUint32(data, childIdx)
-	data = append(k.Data, data...)
+	// serP(Kpar) || ser32(i)
+	data := make([]byte, 0, len(k.Data)+ChildIndexLen)
+	data = append(data, k.Data...)
+	data = append(data, uint32ToBytes(childIdx)...)
 
 	// calculate the new key
 	newKey, err := k.calculateChildKey(data)
@@ -90,108 +195,69 @@ func (k *PublicKey) Derive(childIdx uint32) (*PublicKey, error) {
 		return nil, err
 	}
 
-	// create the new public key
-	return &PublicKey{
-		ChainCode:  newKey.ChainCode,
-		ChildIndex: childIdx,
-		Data:       newKey.Data,
-		Level:      k.Level + 1,
-		ParentFP:   k.ParentFP,
-		Version:    k.Version,
-	}, nil
+	fingerprint, err := hash160(k.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	newKey.ChildIndex = childIdx
+	newKey.ParentFP = fingerprint[:4]
+	return newKey, nil
 }
 
-func (k *PublicKey) calculateChildKey(data []byte) (*PublicKey, error) {
-	// calculate the data
-	data = append(k.Version, data...)
-	data = append(k.ParentFP, data...)
+// DeriveNext derives the first valid child starting at childIdx, skipping the
+// indexes whose keys are invalid. The index of the returned key is its ChildIndex.
+func (k *PublicKey) DeriveNext(childIdx uint32) (*PublicKey, error) {
+	for {
+		child, err := k.Derive(childIdx)
+		if err != ErrInvalidChild {
+			return child, err
+		}
+		if childIdx == HardenedKeyZeroIndex-1 {
+			return nil, ErrInvalidChild
+		}
+		childIdx++
+	}
+}
 
+// calculateChildKey computes Ki = point(parse256(IL)) + Kpar, and the chain code IR.
+func (k *PublicKey) calculateChildKey(data []byte) (*PublicKey, error) {
 	// calculate the HMAC
 	hmacCode := hmac.New(sha512.New, k.ChainCode)
 	hmacCode.Write(data)
 	intermediary := hmacCode.Sum(nil)
 
-	// split the intermediary into the private key and chain code
-	privateKey := intermediary[:32]
+	// split the intermediary into the tweak and chain code
+	tweak := intermediary[:32]
 	chainCode := intermediary[32:]
 
+	// In case parse256(IL) >= n or Ki is the point at infinity, the resulting key is invalid.
+	childData, err := addPublicKeys(tweak, k.Data)
+	if err != nil {
+		return nil, err
+	}
+
 	// create the new public key
 	return &PublicKey{
-		ChainCode:  chainCode,
-		ChildIndex: 0,
-		Data:       privateKey,
-		Level:      k.Level + 1,
-		ParentFP:   k.ParentFP,
-		Version:    k.Version,
+		ChainCode: chainCode,
+		Data:      childData,
+		Level:     k.Level + 1,
+		Version:   k.Version,
 	}, nil
 }
 
-func (k *PublicKey) DeriveWithPath(path string) (*PublicKey, error) {
-	// parse the path
-	paths := strings.Split(path, "/")
-	if len(paths) == 0 {
-		return nil, ErrInvalidPath
-	}
-
-	// derive the key
-	for _, p := range paths {
-		// parse the path
-		if p == "" {
-			return nil, ErrInvalidPath
-		}
-		index, err := strconv.Atoi(p)
-		if err != nil {
-			return nil, err
-		}
-
-		// derive the key
-		k, err = k.Derive(uint32(index))
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return k, nil
-}
-
-func (k *PublicKey) Serialize() []byte {
-	return nil
-}
-
-func (k *PublicKey) String() string {
-	if 0 == len(k.Data) {
-		return "zeroed public key"
-	}
-
-	var childIndex [ChildIndexLen]byte
-	binary.BigEndian.PutUint32(childIndex[:], k.ChildIndex)
-
-	var serializedKeyLen = len(k.Version) + len(k.ParentFP) + len(k.Data) + len(childIndex)
-	// The serialized format is:
-	//   version (4) || depth (1) || parent fingerprint (4)) ||
-	//   child num (4) || chain code (32) || key data (33)
-	serialized := make([]byte, 0, serializedKeyLen)
-	serialized = append(serialized, k.Version...)
-	serialized = append(serialized, k.Level)
-	serialized = append(serialized, k.ParentFP...)
-	serialized = append(serialized, childIndex[:]...)
-	serialized = append(serialized, k.ChainCode...)
-	serialized = append(serialized, k.Data...)
-
-	return hex.EncodeToString(serialized)
-}
-
 func (k *PrivateKey) getIntermediary(childIdx uint32) ([]byte, error) {
 	// Create the data to be hashed.
-	data := make([]byte, 37)
-	// Prefix Version
-	copy(data[:4], k.Version)
+	var data []byte
 	if childIdx >= HardenedKeyZeroIndex {
+		// Hardened child: 0x00 || ser256(kpar) || ser32(i)
 		data = append([]byte{0x0}, k.Data...)
 	} else {
-		copy(data[4:34], k.Data)
+		// Normal child: serP(point(kpar)) || ser32(i)
+		data = publicKeyForPrivateKey(k.Data)
 	}
-	copy(data[34:37], uint32ToBytes(childIdx))
+	data = append(data, uint32ToBytes(childIdx)...)
+
 	// Create the HMAC.
 	hmacCode := hmac.New(sha512.New, k.ChainCode)
 	hmacCode.Write(data)
@@ -200,89 +266,57 @@ func (k *PrivateKey) getIntermediary(childIdx uint32) ([]byte, error) {
 	return intermediary, nil
 }
 
+// Derive is CKDpriv: it derives a child private key from a parent private key and a child index.
+// ErrInvalidChild is returned when the resulting key is invalid, use DeriveNext to
+// proceed with the next index as BIP-32 suggests.
 func (k *PrivateKey) Derive(childIdx uint32) (*PrivateKey, error) {
+	if k.Level == math.MaxUint8 {
+		return nil, ErrDeriveBeyondMaxDepth
+	}
+
 	intermediary, err := k.getIntermediary(childIdx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create child Key with data common to all both scenarios
-	childKey := &PrivateKey{
-		PublicKey: PublicKey{
-			ChainCode:  intermediary[32:],
-			ChildIndex: childIdx,
-			Level:      k.Level + 1,
-			//ParentFP:   k.PublicKey.ParentFP,
-			Version: k.PublicKey.Version,
-		},
-		Data:    intermediary[:32],
-		Version: k.Version,
+	// In case parse256(IL) >= n or ki = 0, the resulting key is invalid.
+	childData, err := addPrivateKeys(intermediary[:32], k.Data)
+	if err != nil {
+		return nil, err
 	}
 
-	// int to []byte
-	buf := new(bytes.Buffer)
-	binary.Write(buf, binary.LittleEndian, PrivateKeyPrefix)
-
-	childKey.Version = buf.Bytes()
 	fingerprint, err := hash160(publicKeyForPrivateKey(k.Data))
 	if err != nil {
 		return nil, err
 	}
-	childKey.ParentFP = fingerprint[:4]
-	childKey.Data = addPrivateKeys(intermediary[:32], k.Data)
-	return nil, nil
-}
 
-func (k *PrivateKey) DeriveWithPath(path string) (*PrivateKey, error) {
-	// parse the path
-	paths := strings.Split(path, "/")
-	if len(paths) == 0 {
-		return nil, ErrInvalidPath
-	}
+	return &PrivateKey{
+		PublicKey: PublicKey{
+			ChainCode:  intermediary[32:],
+			ChildIndex: childIdx,
+			Level:      k.Level + 1,
+			ParentFP:   fingerprint[:4],
+			Version:    k.PublicKey.Version,
+		},
+		Data:    childData,
+		Version: k.Version,
+	}, nil
+}
 
-	// derive the key
-	for _, p := range paths {
-		// parse the path
-		if p == "" {
-			return nil, ErrInvalidPath
+// DeriveNext derives the first valid child starting at childIdx, skipping the
+// indexes whose keys are invalid. The index of the returned key is its ChildIndex.
+func (k *PrivateKey) DeriveNext(childIdx uint32) (*PrivateKey, error) {
+	for {
+		child, err := k.Derive(childIdx)
+		if err != ErrInvalidChild {
+			return child, err
 		}
-		index, err := strconv.Atoi(p)
-		if err != nil {
-			return nil, err
-		}
-
-		// derive the key
-		k, err = k.Derive(uint32(index))
-		if err != nil {
-			return nil, err
+		// Never wrap around from the normal range into the hardened range or back.
+		if childIdx == HardenedKeyZeroIndex-1 || childIdx == math.MaxUint32 {
+			return nil, ErrInvalidChild
 		}
+		childIdx++
 	}
-
-	return k, nil
-}
-
-func (k *PrivateKey) Serialize() []byte {
-	keyBytes := k.Data
-	keyBytes = append([]byte{0x0}, keyBytes...)
-	// Write fields to buffer in order
-	buffer := new(bytes.Buffer)
-	buffer.WriteByte(PrivateKeyPrefix)
-	buffer.WriteByte(k.Level)
-	buffer.Write(k.ParentFP)
-	buffer.Write(uint32ToBytes(k.ChildIndex))
-	buffer.Write(k.ChainCode)
-	buffer.Write(keyBytes)
-
-	// Append the standard double sha256 checksum
-	serializedKey, err := addChecksumToBytes(buffer.Bytes())
-	if err != nil {
-		return nil
-	}
-	return serializedKey
-}
-
-func (k *PrivateKey) String() string {
-	return ""
 }
 
 func (k *PrivateKey) ToPublicKeyBytes() []byte {
@@ -292,27 +326,82 @@ func (k *PrivateKey) ToPublicKeyBytes() []byte {
 
 func (k *PrivateKey) ToPublicKey() *PublicKey {
 	return &PublicKey{
+		ChainCode:  k.ChainCode,
 		Data:       k.ToPublicKeyBytes(),
-		Version:    k.Version,
+		Version:    k.PublicKey.Version,
 		ChildIndex: k.ChildIndex,
 		Level:      k.Level,
 		ParentFP:   k.ParentFP,
 	}
 }
 
-// addPrivateKeys adds two private keys together.
-func addPrivateKeys(key1 []byte, key2 []byte) []byte {
-	var key1Int big.Int
-	var key2Int big.Int
-	key1Int.SetBytes(key1)
-	key2Int.SetBytes(key2)
+// Uncompressed returns the 65 bytes uncompressed serialization 0x04 || x || y of the public key.
+func (k *PublicKey) Uncompressed() ([]byte, error) {
+	x, y, err := expandPublicKey(k.Data)
+	if err != nil {
+		return nil, err
+	}
+	key := append([]byte{0x4}, paddedBytes(x, 32)...)
+	return append(key, paddedBytes(y, 32)...), nil
+}
+
+// addPrivateKeys adds the tweak to the private key modulo n.
+// ErrInvalidChild is returned when the tweak is not below n or the sum is zero.
+func addPrivateKeys(tweak []byte, key []byte) ([]byte, error) {
+	var tweakInt big.Int
+	var keyInt big.Int
+	tweakInt.SetBytes(tweak)
+	keyInt.SetBytes(key)
+	if tweakInt.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	keyInt.Add(&keyInt, &tweakInt)
+	keyInt.Mod(&keyInt, curve.Params().N)
+	if keyInt.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+
+	return paddedBytes(&keyInt, 32), nil
+}
+
+// addPublicKeys adds point(tweak) to the compressed public key.
+// ErrInvalidChild is returned when the tweak is not below n or the sum is the point at infinity.
+func addPublicKeys(tweak []byte, key []byte) ([]byte, error) {
+	var tweakInt big.Int
+	tweakInt.SetBytes(tweak)
+	if tweakInt.Cmp(curve.Params().N) >= 0 {
+		return nil, ErrInvalidChild
+	}
+
+	x1, y1 := curve.ScalarBaseMult(tweak)
+	x2, y2, err := expandPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// The curve addition does not handle doubling or inverse points.
+	if x1.Cmp(x2) == 0 {
+		if y1.Cmp(y2) != 0 {
+			return nil, ErrInvalidChild
+		}
+		return compressPublicKey(curve.Double(x1, y1)), nil
+	}
+	return compressPublicKey(curve.Add(x1, y1, x2, y2)), nil
+}
 
-	key1Int.Add(&key1Int, &key2Int)
-	key1Int.Mod(&key1Int, curve.Params().N)
+// isValidPrivateKey reports whether the key is in the range [1, n-1].
+func isValidPrivateKey(key []byte) bool {
+	var keyInt big.Int
+	keyInt.SetBytes(key)
+	return len(key) == 32 && keyInt.Sign() > 0 && keyInt.Cmp(curve.Params().N) < 0
+}
 
-	b := key1Int.Bytes()
-	if len(b) < 32 {
-		extra := make([]byte, 32-len(b))
+// paddedBytes returns the big-endian bytes of i left padded with zeros to size.
+func paddedBytes(i *big.Int, size int) []byte {
+	b := i.Bytes()
+	if len(b) < size {
+		extra := make([]byte, size-len(b))
 		b = append(extra, b...)
 	}
 	return b
@@ -336,6 +425,36 @@ func compressPublicKey(x *big.Int, y *big.Int) []byte {
 	return key.Bytes()
 }
 
+// expandPublicKey decompresses a 33 bytes public key into its coordinates.
+func expandPublicKey(key []byte) (*big.Int, *big.Int, error) {
+	if len(key) != 33 || (key[0] != 0x2 && key[0] != 0x3) {
+		return nil, nil, ErrInvalidKey
+	}
+
+	params := curve.Params()
+	x := new(big.Int).SetBytes(key[1:])
+	if x.Cmp(params.P) >= 0 {
+		return nil, nil, ErrInvalidKey
+	}
+
+	// y² = x³ + b, and since p = 3 mod 4, y = (y²)^((p+1)/4)
+	ySquared := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	ySquared.Add(ySquared, params.B)
+	ySquared.Mod(ySquared, params.P)
+
+	exp := new(big.Int).Add(params.P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(ySquared, exp, params.P)
+	if new(big.Int).Exp(y, big.NewInt(2), params.P).Cmp(ySquared) != 0 {
+		return nil, nil, ErrInvalidKey
+	}
+
+	if y.Bit(0) != uint(key[0]&0x1) {
+		y.Sub(params.P, y)
+	}
+	return x, y, nil
+}
+
 // checksum calculates the checksum for a key.
 func checksum(data []byte) ([]byte, error) {
 	hash, err := hashDoubleSha256(data)
@@ -1 +1,588 @@
 package bip32
+
+import (
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+type testVectorKey struct {
+	path       string
+	childIndex uint32
+	depth      uint8
+	parentFP   string
+	chainCode  string
+	privateKey string
+	publicKey  string
+	xpub       string
+	xprv       string
+}
+
+type testVector struct {
+	name string
+	seed string
+	keys []testVectorKey
+}
+
+// testVectors are the official BIP-32 test vectors 1-4, each key is derived from the previous one.
+// https://github.com/bitcoin/bips/blob/master/bip-0032.mediawiki#test-vectors
+var testVectors = []testVector{
+	{
+		name: "test vector 1",
+		seed: "000102030405060708090a0b0c0d0e0f",
+		keys: []testVectorKey{
+			{
+				path:       "m",
+				childIndex: 0,
+				depth:      0,
+				parentFP:   "00000000",
+				chainCode:  "873dff81c02f525623fd1fe5167eac3a55a049de3d314bb42ee227ffed37d508",
+				privateKey: "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35",
+				publicKey:  "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2",
+				xpub:       "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+				xprv:       "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			},
+			{
+				path:       "m/0H",
+				childIndex: HardenedKeyZeroIndex,
+				depth:      1,
+				parentFP:   "3442193e",
+				chainCode:  "47fdacbd0f1097043b78c63c20c34ef4ed9a111d980047ad16282c7ae6236141",
+				privateKey: "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea",
+				publicKey:  "035a784662a4a20a65bf6aab9ae98a6c068a81c52e4b032c0fb5400c706cfccc56",
+				xpub:       "xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+				xprv:       "xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			},
+			{
+				path:       "m/0H/1",
+				childIndex: 1,
+				depth:      2,
+				parentFP:   "5c1bd648",
+				chainCode:  "2a7857631386ba23dacac34180dd1983734e444fdbf774041578e9b6adb37c19",
+				privateKey: "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368",
+				publicKey:  "03501e454bf00751f24b1b489aa925215d66af2234e3891c3b21a52bedb3cd711c",
+				xpub:       "xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+				xprv:       "xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			},
+			{
+				path:       "m/0H/1/2H",
+				childIndex: HardenedKeyZeroIndex + 2,
+				depth:      3,
+				parentFP:   "bef5a2f9",
+				chainCode:  "04466b9cc8e161e966409ca52986c584f07e9dc81f735db683c3ff6ec7b1503f",
+				privateKey: "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca",
+				publicKey:  "0357bfe1e341d01c69fe5654309956cbea516822fba8a601743a012a7896ee8dc2",
+				xpub:       "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+				xprv:       "xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+			},
+			{
+				path:       "m/0H/1/2H/2",
+				childIndex: 2,
+				depth:      4,
+				parentFP:   "ee7ab90c",
+				chainCode:  "cfb71883f01676f587d023cc53a35bc7f88f724b1f8c2892ac1275ac822a3edd",
+				privateKey: "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4",
+				publicKey:  "02e8445082a72f29b75ca48748a914df60622a609cacfce8ed0e35804560741d29",
+				xpub:       "xpub6FHa3pjLCk84BayeJxFW2SP4XRrFd1JYnxeLeU8EqN3vDfZmbqBqaGJAyiLjTAwm6ZLRQUMv1ZACTj37sR62cfN7fe5JnJ7dh8zL4fiyLHV",
+				xprv:       "xprvA2JDeKCSNNZky6uBCviVfJSKyQ1mDYahRjijr5idH2WwLsEd4Hsb2Tyh8RfQMuPh7f7RtyzTtdrbdqqsunu5Mm3wDvUAKRHSC34sJ7in334",
+			},
+			{
+				path:       "m/0H/1/2H/2/1000000000",
+				childIndex: 1000000000,
+				depth:      5,
+				parentFP:   "d880d7d8",
+				chainCode:  "c783e67b921d2beb8f6b389cc646d7263b4145701dadd2161548a8b078e65e9e",
+				privateKey: "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8",
+				publicKey:  "022a471424da5e657499d1ff51cb43c47481a03b1e77f951fe64cec9f5a48f7011",
+				xpub:       "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy",
+				xprv:       "xprvA41z7zogVVwxVSgdKUHDy1SKmdb533PjDz7J6N6mV6uS3ze1ai8FHa8kmHScGpWmj4WggLyQjgPie1rFSruoUihUZREPSL39UNdE3BBDu76",
+			},
+		},
+	},
+	{
+		name: "test vector 2",
+		seed: "fffcf9f6f3f0edeae7e4e1dedbd8d5d2cfccc9c6c3c0bdbab7b4b1aeaba8a5a29f9c999693908d8a8784817e7b7875726f6c696663605d5a5754514e4b484542",
+		keys: []testVectorKey{
+			{
+				path:       "m",
+				childIndex: 0,
+				depth:      0,
+				parentFP:   "00000000",
+				chainCode:  "60499f801b896d83179a4374aeb7822aaeaceaa0db1f85ee3e904c4defbd9689",
+				privateKey: "4b03d6fc340455b363f51020ad3ecca4f0850280cf436c70c727923f6db46c3e",
+				publicKey:  "03cbcaa9c98c877a26977d00825c956a238e8dddfbd322cce4f74b0b5bd6ace4a7",
+				xpub:       "xpub661MyMwAqRbcFW31YEwpkMuc5THy2PSt5bDMsktWQcFF8syAmRUapSCGu8ED9W6oDMSgv6Zz8idoc4a6mr8BDzTJY47LJhkJ8UB7WEGuduB",
+				xprv:       "xprv9s21ZrQH143K31xYSDQpPDxsXRTUcvj2iNHm5NUtrGiGG5e2DtALGdso3pGz6ssrdK4PFmM8NSpSBHNqPqm55Qn3LqFtT2emdEXVYsCzC2U",
+			},
+			{
+				path:       "m/0",
+				childIndex: 0,
+				depth:      1,
+				parentFP:   "bd16bee5",
+				chainCode:  "f0909affaa7ee7abe5dd4e100598d4dc53cd709d5a5c2cac40e7412f232f7c9c",
+				privateKey: "abe74a98f6c7eabee0428f53798f0ab8aa1bd37873999041703c742f15ac7e1e",
+				publicKey:  "02fc9e5af0ac8d9b3cecfe2a888e2117ba3d089d8585886c9c826b6b22a98d12ea",
+				xpub:       "xpub69H7F5d8KSRgmmdJg2KhpAK8SR3DjMwAdkxj3ZuxV27CprR9LgpeyGmXUbC6wb7ERfvrnKZjXoUmmDznezpbZb7ap6r1D3tgFxHmwMkQTPH",
+				xprv:       "xprv9vHkqa6EV4sPZHYqZznhT2NPtPCjKuDKGY38FBWLvgaDx45zo9WQRUT3dKYnjwih2yJD9mkrocEZXo1ex8G81dwSM1fwqWpWkeS3v86pgKt",
+			},
+			{
+				path:       "m/0/2147483647H",
+				childIndex: HardenedKeyZeroIndex + 2147483647,
+				depth:      2,
+				parentFP:   "5a61ff8e",
+				chainCode:  "be17a268474a6bb9c61e1d720cf6215e2a88c5406c4aee7b38547f585c9a37d9",
+				privateKey: "877c779ad9687164e9c2f4f0f4ff0340814392330693ce95a58fe18fd52e6e93",
+				publicKey:  "03c01e7425647bdefa82b12d9bad5e3e6865bee0502694b94ca58b666abc0a5c3b",
+				xpub:       "xpub6ASAVgeehLbnwdqV6UKMHVzgqAG8Gr6riv3Fxxpj8ksbH9ebxaEyBLZ85ySDhKiLDBrQSARLq1uNRts8RuJiHjaDMBU4Zn9h8LZNnBC5y4a",
+				xprv:       "xprv9wSp6B7kry3Vj9m1zSnLvN3xH8RdsPP1Mh7fAaR7aRLcQMKTR2vidYEeEg2mUCTAwCd6vnxVrcjfy2kRgVsFawNzmjuHc2YmYRmagcEPdU9",
+			},
+			{
+				path:       "m/0/2147483647H/1",
+				childIndex: 1,
+				depth:      3,
+				parentFP:   "d8ab4937",
+				chainCode:  "f366f48f1ea9f2d1d3fe958c95ca84ea18e4c4ddb9366c336c927eb246fb38cb",
+				privateKey: "704addf544a06e5ee4bea37098463c23613da32020d604506da8c0518e1da4b7",
+				publicKey:  "03a7d1d856deb74c508e05031f9895dab54626251b3806e16b4bd12e781a7df5b9",
+				xpub:       "xpub6DF8uhdarytz3FWdA8TvFSvvAh8dP3283MY7p2V4SeE2wyWmG5mg5EwVvmdMVCQcoNJxGoWaU9DCWh89LojfZ537wTfunKau47EL2dhHKon",
+				xprv:       "xprv9zFnWC6h2cLgpmSA46vutJzBcfJ8yaJGg8cX1e5StJh45BBciYTRXSd25UEPVuesF9yog62tGAQtHjXajPPdbRCHuWS6T8XA2ECKADdw4Ef",
+			},
+			{
+				path:       "m/0/2147483647H/1/2147483646H",
+				childIndex: HardenedKeyZeroIndex + 2147483646,
+				depth:      4,
+				parentFP:   "78412e3a",
+				chainCode:  "637807030d55d01f9a0cb3a7839515d796bd07706386a6eddf06cc29a65a0e29",
+				privateKey: "f1c7c871a54a804afe328b4c83a1c33b8e5ff48f5087273f04efa83b247d6a2d",
+				publicKey:  "02d2b36900396c9282fa14628566582f206a5dd0bcc8d5e892611806cafb0301f0",
+				xpub:       "xpub6ERApfZwUNrhLCkDtcHTcxd75RbzS1ed54G1LkBUHQVHQKqhMkhgbmJbZRkrgZw4koxb5JaHWkY4ALHY2grBGRjaDMzQLcgJvLJuZZvRcEL",
+				xprv:       "xprvA1RpRA33e1JQ7ifknakTFpgNXPmW2YvmhqLQYMmrj4xJXXWYpDPS3xz7iAxn8L39njGVyuoseXzU6rcxFLJ8HFsTjSyQbLYnMpCqE2VbFWc",
+			},
+			{
+				path:       "m/0/2147483647H/1/2147483646H/2",
+				childIndex: 2,
+				depth:      5,
+				parentFP:   "31a507b8",
+				chainCode:  "9452b549be8cea3ecb7a84bec10dcfd94afe4d129ebfd3b3cb58eedf394ed271",
+				privateKey: "bb7d39bdb83ecf58f2fd82b6d918341cbef428661ef01ab97c28a4842125ac23",
+				publicKey:  "024d902e1a2fc7a8755ab5b694c575fce742c48d9ff192e63df5193e4c7afe1f9c",
+				xpub:       "xpub6FnCn6nSzZAw5Tw7cgR9bi15UV96gLZhjDstkXXxvCLsUXBGXPdSnLFbdpq8p9HmGsApME5hQTZ3emM2rnY5agb9rXpVGyy3bdW6EEgAtqt",
+				xprv:       "xprvA2nrNbFZABcdryreWet9Ea4LvTJcGsqrMzxHx98MMrotbir7yrKCEXw7nadnHM8Dq38EGfSh6dqA9QWTyefMLEcBYJUuekgW4BYPJcr9E7j",
+			},
+		},
+	},
+	{
+		name: "test vector 3",
+		seed: "4b381541583be4423346c643850da4b320e46a87ae3d2a4e6da11eba819cd4acba45d239319ac14f863b8d5ab5a0d0c64d2e8a1e7d1457df2e5a3c51c73235be",
+		keys: []testVectorKey{
+			{
+				path:       "m",
+				childIndex: 0,
+				depth:      0,
+				parentFP:   "00000000",
+				chainCode:  "01d28a3e53cffa419ec122c968b3259e16b65076495494d97cae10bbfec3c36f",
+				privateKey: "00ddb80b067e0d4993197fe10f2657a844a384589847602d56f0c629c81aae32",
+				publicKey:  "03683af1ba5743bdfc798cf814efeeab2735ec52d95eced528e692b8e34c4e5669",
+				xpub:       "xpub661MyMwAqRbcEZVB4dScxMAdx6d4nFc9nvyvH3v4gJL378CSRZiYmhRoP7mBy6gSPSCYk6SzXPTf3ND1cZAceL7SfJ1Z3GC8vBgp2epUt13",
+				xprv:       "xprv9s21ZrQH143K25QhxbucbDDuQ4naNntJRi4KUfWT7xo4EKsHt2QJDu7KXp1A3u7Bi1j8ph3EGsZ9Xvz9dGuVrtHHs7pXeTzjuxBrCmmhgC6",
+			},
+			{
+				path:       "m/0H",
+				childIndex: HardenedKeyZeroIndex,
+				depth:      1,
+				parentFP:   "41d63b50",
+				chainCode:  "e5fea12a97b927fc9dc3d2cb0d1ea1cf50aa5a1fdc1f933e8906bb38df3377bd",
+				privateKey: "491f7a2eebc7b57028e0d3faa0acda02e75c33b03c48fb288c41e2ea44e1daef",
+				publicKey:  "026557fdda1d5d43d79611f784780471f086d58e8126b8c40acb82272a7712e7f2",
+				xpub:       "xpub68NZiKmJWnxxS6aaHmn81bvJeTESw724CRDs6HbuccFQN9Ku14VQrADWgqbhhTHBaohPX4CjNLf9fq9MYo6oDaPPLPxSb7gwQN3ih19Zm4Y",
+				xprv:       "xprv9uPDJpEQgRQfDcW7BkF7eTya6RPxXeJCqCJGHuCJ4GiRVLzkTXBAJMu2qaMWPrS7AANYqdq6vcBcBUdJCVVFceUvJFjaPdGZ2y9WACViL4L",
+			},
+		},
+	},
+	{
+		name: "test vector 4",
+		seed: "3ddd5602285899a946114506157c7997e5444528f3003f6134712147db19b678",
+		keys: []testVectorKey{
+			{
+				path:       "m",
+				childIndex: 0,
+				depth:      0,
+				parentFP:   "00000000",
+				chainCode:  "d0c8a1f6edf2500798c3e0b54f1b56e45f6d03e6076abd36e5e2f54101e44ce6",
+				privateKey: "12c0d59c7aa3a10973dbd3f478b65f2516627e3fe61e00c345be9a477ad2e215",
+				publicKey:  "026f6fedc9240f61daa9c7144b682a430a3a1366576f840bf2d070101fcbc9a02d",
+				xpub:       "xpub661MyMwAqRbcGczjuMoRm6dXaLDEhW1u34gKenbeYqAix21mdUKJyuyu5F1rzYGVxyL6tmgBUAEPrEz92mBXjByMRiJdba9wpnN37RLLAXa",
+				xprv:       "xprv9s21ZrQH143K48vGoLGRPxgo2JNkJ3J3fqkirQC2zVdk5Dgd5w14S7fRDyHH4dWNHUgkvsvNDCkvAwcSHNAQwhwgNMgZhLtQC63zxwhQmRv",
+			},
+			{
+				path:       "m/0H",
+				childIndex: HardenedKeyZeroIndex,
+				depth:      1,
+				parentFP:   "ad85d955",
+				chainCode:  "cdc0f06456a14876c898790e0b3b1a41c531170aec69da44ff7b7265bfe7743b",
+				privateKey: "00d948e9261e41362a688b916f297121ba6bfb2274a3575ac0e456551dfd7f7e",
+				publicKey:  "039382d2b6003446792d2917f7ac4b3edf079a1a94dd4eb010dc25109dda680a9d",
+				xpub:       "xpub69AUMk3qDBi3uW1sXgjCmVjJ2G6WQoYSnNHyzkmdCHEhSZ4tBok37xfFEqHd2AddP56Tqp4o56AePAgCjYdvpW2PU2jbUPFKsav5ut6Ch1m",
+				xprv:       "xprv9vB7xEWwNp9kh1wQRfCCQMnZUEG21LpbR9NPCNN1dwhiZkjjeGRnaALmPXCX7SgjFTiCTT6bXes17boXtjq3xLpcDjzEuGLQBM5ohqkao9G",
+			},
+			{
+				path:       "m/0H/1H",
+				childIndex: HardenedKeyZeroIndex + 1,
+				depth:      2,
+				parentFP:   "cfa61281",
+				chainCode:  "a48ee6674c5264a237703fd383bccd9fad4d9378ac98ab05e6e7029b06360c0d",
+				privateKey: "3a2086edd7d9df86c3487a5905a1712a9aa664bce8cc268141e07549eaa8661d",
+				publicKey:  "032edaf9e591ee27f3c69c36221e3c54c38088ef34e93fbb9bb2d4d9b92364cbbd",
+				xpub:       "xpub6BJA1jSqiukeaesWfxe6sNK9CCGaujFFSJLomWHprUL9DePQ4JDkM5d88n49sMGJxrhpjazuXYWdMf17C9T5XnxkopaeS7jGk1GyyVziaMt",
+				xprv:       "xprv9xJocDuwtYCMNAo3Zw76WENQeAS6WGXQ55RCy7tDJ8oALr4FWkuVoHJeHVAcAqiZLE7Je3vZJHxspZdFHfnBEjHqU5hG1Jaj32dVoS6XLT1",
+			},
+		},
+	},
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("invalid hex %q: %v", s, err)
+	}
+	return b
+}
+
+func TestPrivateKeyDerive(t *testing.T) {
+	for _, tv := range testVectors {
+		t.Run(tv.name, func(t *testing.T) {
+			var key *PrivateKey
+			for i, want := range tv.keys {
+				var err error
+				if i == 0 {
+					key, err = NewMasterKey(mustDecodeHex(t, tv.seed))
+				} else {
+					key, err = key.Derive(want.childIndex)
+				}
+				if err != nil {
+					t.Fatalf("%s: derive error = %v", want.path, err)
+				}
+
+				if key.ChildIndex != want.childIndex {
+					t.Errorf("%s: ChildIndex = %d, want %d", want.path, key.ChildIndex, want.childIndex)
+				}
+				if key.Level != want.depth {
+					t.Errorf("%s: Level = %d, want %d", want.path, key.Level, want.depth)
+				}
+				if got := hex.EncodeToString(key.ParentFP); got != want.parentFP {
+					t.Errorf("%s: ParentFP = %s, want %s", want.path, got, want.parentFP)
+				}
+				if got := hex.EncodeToString(key.ChainCode); got != want.chainCode {
+					t.Errorf("%s: ChainCode = %s, want %s", want.path, got, want.chainCode)
+				}
+				if got := hex.EncodeToString(key.Data); got != want.privateKey {
+					t.Errorf("%s: private key = %s, want %s", want.path, got, want.privateKey)
+				}
+				if got := hex.EncodeToString(key.ToPublicKeyBytes()); got != want.publicKey {
+					t.Errorf("%s: public key = %s, want %s", want.path, got, want.publicKey)
+				}
+			}
+		})
+	}
+}
+
+func TestPrivateKeyDeriveBeyondMaxDepth(t *testing.T) {
+	key, err := NewMasterKey(mustDecodeHex(t, testVectors[0].seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key.Level = 255
+	if _, err := key.Derive(0); err != ErrDeriveBeyondMaxDepth {
+		t.Errorf("Derive() error = %v, want %v", err, ErrDeriveBeyondMaxDepth)
+	}
+}
+
+func Test_addPrivateKeys(t *testing.T) {
+	n := curve.Params().N
+	one := paddedBytes(big.NewInt(1), 32)
+	nMinusOne := paddedBytes(new(big.Int).Sub(n, big.NewInt(1)), 32)
+	tests := []struct {
+		name    string
+		tweak   []byte
+		key     []byte
+		wantErr error
+	}{
+		{
+			name:  "valid",
+			tweak: one,
+			key:   one,
+		},
+		{
+			name:    "tweak equal to n",
+			tweak:   paddedBytes(n, 32),
+			key:     one,
+			wantErr: ErrInvalidChild,
+		},
+		{
+			name:    "zero child key",
+			tweak:   nMinusOne,
+			key:     one,
+			wantErr: ErrInvalidChild,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := addPrivateKeys(tt.tweak, tt.key); err != tt.wantErr {
+				t.Errorf("addPrivateKeys() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMasterKeyFromKeyAndChainCode(t *testing.T) {
+	chainCode := make([]byte, 32)
+	if _, err := NewMasterKeyFromKeyAndChainCode(make([]byte, 32), chainCode); err != ErrInvalidKey {
+		t.Errorf("zero key error = %v, want %v", err, ErrInvalidKey)
+	}
+	if _, err := NewMasterKeyFromKeyAndChainCode(paddedBytes(curve.Params().N, 32), chainCode); err != ErrInvalidKey {
+		t.Errorf("key equal to n error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestPublicKeyDerive(t *testing.T) {
+	for _, tv := range testVectors {
+		t.Run(tv.name, func(t *testing.T) {
+			var key *PrivateKey
+			for i, want := range tv.keys {
+				var err error
+				if i == 0 {
+					key, err = NewMasterKey(mustDecodeHex(t, tv.seed))
+					if err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				parent := key.ToPublicKey()
+				key, err = key.Derive(want.childIndex)
+				if err != nil {
+					t.Fatalf("%s: derive error = %v", want.path, err)
+				}
+
+				child, err := parent.Derive(want.childIndex)
+				if want.childIndex >= HardenedKeyZeroIndex {
+					if err != ErrHardenedKey {
+						t.Errorf("%s: error = %v, want %v", want.path, err, ErrHardenedKey)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("%s: derive error = %v", want.path, err)
+				}
+
+				if !reflect.DeepEqual(child, key.ToPublicKey()) {
+					t.Errorf("%s: Derive() = %+v, want %+v", want.path, child, key.ToPublicKey())
+				}
+				if got := hex.EncodeToString(child.Data); got != want.publicKey {
+					t.Errorf("%s: public key = %s, want %s", want.path, got, want.publicKey)
+				}
+				if got := hex.EncodeToString(child.ParentFP); got != want.parentFP {
+					t.Errorf("%s: ParentFP = %s, want %s", want.path, got, want.parentFP)
+				}
+			}
+		})
+	}
+}
+
+func Test_expandPublicKey(t *testing.T) {
+	for _, tv := range testVectors {
+		for _, key := range tv.keys {
+			data := mustDecodeHex(t, key.publicKey)
+			x, y, err := expandPublicKey(data)
+			if err != nil {
+				t.Fatalf("%s: expandPublicKey() error = %v", key.path, err)
+			}
+			if !curve.IsOnCurve(x, y) {
+				t.Errorf("%s: point is not on the curve", key.path)
+			}
+			if got := compressPublicKey(x, y); !reflect.DeepEqual(got, data) {
+				t.Errorf("%s: compressPublicKey() = %x, want %x", key.path, got, data)
+			}
+		}
+	}
+
+	invalid := mustDecodeHex(t, "020000000000000000000000000000000000000000000000000000000000000007")
+	if _, _, err := expandPublicKey(invalid); err != ErrInvalidKey {
+		t.Errorf("expandPublicKey() error = %v, want %v", err, ErrInvalidKey)
+	}
+}
+
+func TestExtendedKeyString(t *testing.T) {
+	for _, tv := range testVectors {
+		t.Run(tv.name, func(t *testing.T) {
+			var key *PrivateKey
+			for i, want := range tv.keys {
+				var err error
+				if i == 0 {
+					key, err = NewMasterKey(mustDecodeHex(t, tv.seed))
+				} else {
+					key, err = key.Derive(want.childIndex)
+				}
+				if err != nil {
+					t.Fatalf("%s: derive error = %v", want.path, err)
+				}
+
+				if got := key.String(); got != want.xprv {
+					t.Errorf("%s: PrivateKey.String() = %s, want %s", want.path, got, want.xprv)
+				}
+				if got := key.ToPublicKey().String(); got != want.xpub {
+					t.Errorf("%s: PublicKey.String() = %s, want %s", want.path, got, want.xpub)
+				}
+				if got := len(key.Serialize()); got != SerializedKeyLen {
+					t.Errorf("%s: len(Serialize()) = %d, want %d", want.path, got, SerializedKeyLen)
+				}
+			}
+		})
+	}
+}
+
+func TestParseExtendedKey(t *testing.T) {
+	for _, tv := range testVectors {
+		for _, want := range tv.keys {
+			parsed, err := ParseExtendedKey(want.xprv)
+			if err != nil {
+				t.Fatalf("%s: ParseExtendedKey(xprv) error = %v", want.path, err)
+			}
+			privateKey, ok := parsed.(*PrivateKey)
+			if !ok {
+				t.Fatalf("%s: ParseExtendedKey(xprv) = %T, want *PrivateKey", want.path, parsed)
+			}
+			if got := hex.EncodeToString(privateKey.Data); got != want.privateKey {
+				t.Errorf("%s: private key = %s, want %s", want.path, got, want.privateKey)
+			}
+			if got := privateKey.ToPublicKey().String(); got != want.xpub {
+				t.Errorf("%s: ToPublicKey() = %s, want %s", want.path, got, want.xpub)
+			}
+
+			parsed, err = ParseExtendedKey(want.xpub)
+			if err != nil {
+				t.Fatalf("%s: ParseExtendedKey(xpub) error = %v", want.path, err)
+			}
+			publicKey, ok := parsed.(*PublicKey)
+			if !ok {
+				t.Fatalf("%s: ParseExtendedKey(xpub) = %T, want *PublicKey", want.path, parsed)
+			}
+			if !reflect.DeepEqual(publicKey, privateKey.ToPublicKey()) {
+				t.Errorf("%s: ParseExtendedKey(xpub) = %+v, want %+v", want.path, publicKey, privateKey.ToPublicKey())
+			}
+		}
+	}
+}
+
+// TestParseExtendedKeyInvalid covers the BIP-32 test vector 5.
+func TestParseExtendedKeyInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantErr error
+	}{
+		{
+			name:    "pubkey version / prvkey mismatch",
+			key:     "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6LBpB85b3D2yc8sfvZU521AAwdZafEz7mnzBBsz4wKY5fTtTQBm",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "prvkey version / pubkey mismatch",
+			key:     "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGTQQD3dC4H2D5GBj7vWvSQaaBv5cxi9gafk7NF3pnBju6dwKvH",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "invalid pubkey prefix 04",
+			key:     "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Txnt3siSujt9RCVYsx4qHZGc62TG4McvMGcAUjeuwZdduYEvFn",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "invalid prvkey prefix 04",
+			key:     "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFGpWnsj83BHtEy5Zt8CcDr1UiRXuWCmTQLxEK9vbz5gPstX92JQ",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "invalid pubkey prefix 01",
+			key:     "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6N8ZMMXctdiCjxTNq964yKkwrkBJJwpzZS4HS2fxvyYUA4q2Xe4",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "invalid prvkey prefix 01",
+			key:     "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD9y5gkZ6Eq3Rjuahrv17fEQ3Qen6J",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "zero depth with non-zero parent fingerprint (xprv)",
+			key:     "xprv9s2SPatNQ9Vc6GTbVMFPFo7jsaZySyzk7L8n2uqKXJen3KUmvQNTuLh3fhZMBoG3G4ZW1N2kZuHEPY53qmbZzCHshoQnNf4GvELZfqTUrcv",
+			wantErr: ErrInvalidFingerprint,
+		},
+		{
+			name:    "zero depth with non-zero parent fingerprint (xpub)",
+			key:     "xpub661no6RGEX3uJkY4bNnPcw4URcQTrSibUZ4NqJEw5eBkv7ovTwgiT91XX27VbEXGENhYRCf7hyEbWrR3FewATdCEebj6znwMfQkhRYHRLpJ",
+			wantErr: ErrInvalidFingerprint,
+		},
+		{
+			name:    "zero depth with non-zero index (xprv)",
+			key:     "xprv9s21ZrQH4r4TsiLvyLXqM9P7k1K3EYhA1kkD6xuquB5i39AU8KF42acDyL3qsDbU9NmZn6MsGSUYZEsuoePmjzsB3eFKSUEh3Gu1N3cqVUN",
+			wantErr: ErrInvalidChildIndex,
+		},
+		{
+			name:    "zero depth with non-zero index (xpub)",
+			key:     "xpub661MyMwAuDcm6CRQ5N4qiHKrJ39Xe1R1NyfouMKTTWcguwVcfrZJaNvhpebzGerh7gucBvzEQWRugZDuDXjNDRmXzSZe4c7mnTK97pTvGS8",
+			wantErr: ErrInvalidChildIndex,
+		},
+		{
+			name:    "unknown extended key version (private)",
+			key:     "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHGMQzT7ayAmfo4z3gY5KfbrZWZ6St24UVf2Qgo6oujFktLHdHY4",
+			wantErr: ErrUnknownVersion,
+		},
+		{
+			name:    "unknown extended key version (public)",
+			key:     "DMwo58pR1QLEFihHiXPVykYB6fJmsTeHvyTp7hRThAtCX8CvYzgPcn8XnmdfHPmHJiEDXkTiJTVV9rHEBUem2mwVbbNfvT2MTcAqj3nesx8uBf9",
+			wantErr: ErrUnknownVersion,
+		},
+		{
+			name:    "private key 0 not in 1..n-1",
+			key:     "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzF93Y5wvzdUayhgkkFoicQZcP3y52uPPxFnfoLZB21Teqt1VvEHx",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "private key n not in 1..n-1",
+			key:     "xprv9s21ZrQH143K24Mfq5zL5MhWK9hUhhGbd45hLXo2Pq2oqzMMo63oStZzFAzHGBP2UuGCqWLTAPLcMtD5SDKr24z3aiUvKr9bJpdrcLg1y3G",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "invalid pubkey 020000000000000000000000000000000000000000000000000000000000000007",
+			key:     "xpub661MyMwAqRbcEYS8w7XLSVeEsBXy79zSzH1J8vCdxAZningWLdN3zgtU6Q5JXayek4PRsn35jii4veMimro1xefsM58PgBMrvdYre8QyULY",
+			wantErr: ErrInvalidKey,
+		},
+		{
+			name:    "invalid checksum",
+			key:     "xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHL",
+			wantErr: ErrInvalidChecksum,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseExtendedKey(tt.key); err != tt.wantErr {
+				t.Errorf("ParseExtendedKey() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewMasterKeyFromExtendKey(t *testing.T) {
+	want := testVectors[0].keys[0]
+	key, err := NewMasterKeyFromExtendKey(want.xprv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := key.String(); got != want.xprv {
+		t.Errorf("String() = %s, want %s", got, want.xprv)
+	}
+	if _, err := NewMasterKeyFromExtendKey(want.xpub); err != ErrNotPrivateKey {
+		t.Errorf("NewMasterKeyFromExtendKey(xpub) error = %v, want %v", err, ErrNotPrivateKey)
+	}
+}
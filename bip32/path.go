@@ -0,0 +1,143 @@
+package bip32
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrAbsolutePath is returned when an m/ rooted path is derived from a non-master key.
+	ErrAbsolutePath = errors.New("cannot derive an absolute path from a non-master key")
+)
+
+// DerivationPath is a BIP-32 derivation path. An absolute path starts at the master key
+// (m/44'/0'/0'/0/0), a relative path starts at the key it is derived from (0/1').
+type DerivationPath struct {
+	Absolute bool
+	Indexes  []uint32
+}
+
+// NewDerivationPath returns the absolute derivation path of the child indexes.
+func NewDerivationPath(indexes ...uint32) DerivationPath {
+	return DerivationPath{Absolute: true, Indexes: indexes}
+}
+
+// ParseDerivationPath parses a derivation path, hardened indexes are marked with ', h or H.
+// e.g. m/44'/0'/0'/0/0, m/84h/0h/0h or 0/1 relative to the current key.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return DerivationPath{}, ErrInvalidPath
+	}
+
+	var result DerivationPath
+	elements := strings.Split(path, "/")
+	if elements[0] == "m" {
+		result.Absolute = true
+		elements = elements[1:]
+	}
+
+	result.Indexes = make([]uint32, 0, len(elements))
+	for _, element := range elements {
+		index, err := parsePathElement(element)
+		if err != nil {
+			return DerivationPath{}, err
+		}
+		result.Indexes = append(result.Indexes, index)
+	}
+	return result, nil
+}
+
+// parsePathElement parses a single path element into a child index.
+func parsePathElement(element string) (uint32, error) {
+	var offset uint32
+	if n := len(element); n > 0 && strings.ContainsAny(element[n-1:], "'hH") {
+		offset = HardenedKeyZeroIndex
+		element = element[:n-1]
+	}
+
+	// only plain decimal digits, no sign, no whitespace
+	if element == "" || strings.TrimLeft(element, "0123456789") != "" {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPath, element)
+	}
+	index, err := strconv.ParseUint(element, 10, 32)
+	if err != nil || index >= HardenedKeyZeroIndex {
+		return 0, fmt.Errorf("%w: index %s out of range", ErrInvalidPath, element)
+	}
+	return uint32(index) + offset, nil
+}
+
+// String formats the path with ' as the hardened marker.
+func (p DerivationPath) String() string {
+	elements := make([]string, 0, len(p.Indexes)+1)
+	if p.Absolute {
+		elements = append(elements, "m")
+	}
+	for _, index := range p.Indexes {
+		if index >= HardenedKeyZeroIndex {
+			elements = append(elements, strconv.FormatUint(uint64(index-HardenedKeyZeroIndex), 10)+"'")
+		} else {
+			elements = append(elements, strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return strings.Join(elements, "/")
+}
+
+// Child returns a copy of the path extended with the child index.
+func (p DerivationPath) Child(index uint32) DerivationPath {
+	indexes := make([]uint32, 0, len(p.Indexes)+1)
+	indexes = append(indexes, p.Indexes...)
+	return DerivationPath{Absolute: p.Absolute, Indexes: append(indexes, index)}
+}
+
+// DeriveWithPath derives the public key at the path, see ParseDerivationPath.
+func (k *PublicKey) DeriveWithPath(path string) (*PublicKey, error) {
+	p, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return k.DeriveWithDerivationPath(p)
+}
+
+// DeriveWithDerivationPath derives the public key at the path.
+func (k *PublicKey) DeriveWithDerivationPath(p DerivationPath) (*PublicKey, error) {
+	if p.Absolute && k.Level != 0 {
+		return nil, ErrAbsolutePath
+	}
+
+	var err error
+	for _, index := range p.Indexes {
+		k, err = k.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// DeriveWithPath derives the private key at the path, see ParseDerivationPath.
+func (k *PrivateKey) DeriveWithPath(path string) (*PrivateKey, error) {
+	p, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return k.DeriveWithDerivationPath(p)
+}
+
+// DeriveWithDerivationPath derives the private key at the path.
+func (k *PrivateKey) DeriveWithDerivationPath(p DerivationPath) (*PrivateKey, error) {
+	if p.Absolute && k.Level != 0 {
+		return nil, ErrAbsolutePath
+	}
+
+	var err error
+	for _, index := range p.Indexes {
+		k, err = k.Derive(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
@@ -0,0 +1,138 @@
+package bip32
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    DerivationPath
+		wantStr string
+		wantErr bool
+	}{
+		{
+			name:    "master",
+			path:    "m",
+			want:    DerivationPath{Absolute: true, Indexes: []uint32{}},
+			wantStr: "m",
+		},
+		{
+			name:    "bip44 apostrophe",
+			path:    "m/44'/0'/0'/0/0",
+			want:    NewDerivationPath(HardenedKeyZeroIndex+44, HardenedKeyZeroIndex, HardenedKeyZeroIndex, 0, 0),
+			wantStr: "m/44'/0'/0'/0/0",
+		},
+		{
+			name:    "h and H markers",
+			path:    "m/84h/1H/0'",
+			want:    NewDerivationPath(HardenedKeyZeroIndex+84, HardenedKeyZeroIndex+1, HardenedKeyZeroIndex),
+			wantStr: "m/84'/1'/0'",
+		},
+		{
+			name:    "max indexes",
+			path:    "m/2147483647/2147483647'",
+			want:    NewDerivationPath(HardenedKeyZeroIndex-1, 0xffffffff),
+			wantStr: "m/2147483647/2147483647'",
+		},
+		{
+			name:    "relative",
+			path:    "0/1'",
+			want:    DerivationPath{Indexes: []uint32{0, HardenedKeyZeroIndex + 1}},
+			wantStr: "0/1'",
+		},
+		{name: "empty", path: "", wantErr: true},
+		{name: "trailing slash", path: "m/0/", wantErr: true},
+		{name: "double slash", path: "m//0", wantErr: true},
+		{name: "negative", path: "m/-1", wantErr: true},
+		{name: "plus sign", path: "m/+1", wantErr: true},
+		{name: "out of range", path: "m/2147483648", wantErr: true},
+		{name: "hardened out of range", path: "m/2147483648'", wantErr: true},
+		{name: "overflow", path: "m/4294967296", wantErr: true},
+		{name: "double marker", path: "m/0''", wantErr: true},
+		{name: "nested m", path: "m/m/0", wantErr: true},
+		{name: "marker only", path: "m/'", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDerivationPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDerivationPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPath) {
+					t.Errorf("ParseDerivationPath() error = %v, want %v", err, ErrInvalidPath)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDerivationPath() = %v, want %v", got, tt.want)
+			}
+			if got.String() != tt.wantStr {
+				t.Errorf("String() = %v, want %v", got.String(), tt.wantStr)
+			}
+		})
+	}
+}
+
+func TestDeriveWithPath(t *testing.T) {
+	for _, tv := range testVectors {
+		master, err := NewMasterKey(mustDecodeHex(t, tv.seed))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tv.keys {
+			key, err := master.DeriveWithPath(want.path)
+			if err != nil {
+				t.Fatalf("%s: DeriveWithPath() error = %v", want.path, err)
+			}
+			if got := key.String(); got != want.xprv {
+				t.Errorf("%s: DeriveWithPath() = %s, want %s", want.path, got, want.xprv)
+			}
+		}
+	}
+}
+
+func TestDeriveWithRelativePath(t *testing.T) {
+	vector := testVectors[0]
+	master, err := NewMasterKey(mustDecodeHex(t, vector.seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// m/0H/1 then 2H/2 relative to it
+	account, err := master.DeriveWithPath("m/0H/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := account.DeriveWithPath("2H/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := key.String(), vector.keys[4].xprv; got != want {
+		t.Errorf("DeriveWithPath() = %s, want %s", got, want)
+	}
+
+	// m/0H/1/2H, then the non-hardened 2/1000000000 from the public key
+	parent, err := master.DeriveWithPath("m/0'/1/2'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := parent.ToPublicKey().DeriveWithPath("2/1000000000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := publicKey.String(), vector.keys[5].xpub; got != want {
+		t.Errorf("PublicKey.DeriveWithPath() = %s, want %s", got, want)
+	}
+
+	if _, err := account.DeriveWithPath("m/0"); err != ErrAbsolutePath {
+		t.Errorf("DeriveWithPath() error = %v, want %v", err, ErrAbsolutePath)
+	}
+	if _, err := parent.ToPublicKey().DeriveWithPath("0'"); err != ErrHardenedKey {
+		t.Errorf("PublicKey.DeriveWithPath() error = %v, want %v", err, ErrHardenedKey)
+	}
+}
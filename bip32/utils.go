@@ -64,3 +64,8 @@ func hash160(data []byte) ([]byte, error) {
 
 	return hash2, nil
 }
+
+// Hash160 returns RIPEMD160(SHA256(data)), the hash of key fingerprints and P2PKH/P2WPKH addresses.
+func Hash160(data []byte) ([]byte, error) {
+	return hash160(data)
+}
@@ -19,7 +19,7 @@ func Test_uint32ToBytes(t *testing.T) {
 			args: args{
 				i: 0x01020304,
 			},
-			want: []byte{0x04, 0x03, 0x02, 0x01},
+			want: []byte{0x01, 0x02, 0x03, 0x04},
 		},
 	}
 	for _, tt := range tests {
@@ -46,7 +46,7 @@ func Test_hashSha256(t *testing.T) {
 			args: args{
 				data: []byte{0x01, 0x02, 0x03, 0x04},
 			},
-			want:    []byte{},
+			want:    []byte{0x9f, 0x64, 0xa7, 0x47, 0xe1, 0xb9, 0x7f, 0x13, 0x1f, 0xab, 0xb6, 0xb4, 0x47, 0x29, 0x6c, 0x9b, 0x6f, 0x02, 0x01, 0xe7, 0x9f, 0xb3, 0xc5, 0x35, 0x6e, 0x6c, 0x77, 0xe8, 0x9b, 0x6a, 0x80, 0x6a},
 			wantErr: false,
 		},
 	}
@@ -79,7 +79,7 @@ func Test_hashDoubleSha256(t *testing.T) {
 			args: args{
 				data: []byte{0x01, 0x02, 0x03, 0x04},
 			},
-			want:    []byte{},
+			want:    []byte{0x8d, 0xe4, 0x72, 0xe2, 0x39, 0x96, 0x10, 0xba, 0xaa, 0x7f, 0x84, 0x84, 0x05, 0x47, 0xcd, 0x40, 0x94, 0x34, 0xe3, 0x1f, 0x5d, 0x3b, 0xd7, 0x1e, 0x4d, 0x94, 0x7f, 0x28, 0x38, 0x74, 0xf9, 0xc0},
 			wantErr: false,
 		},
 	}
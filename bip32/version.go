@@ -0,0 +1,144 @@
+package bip32
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// SLIP-132 registered HD version bytes, see https://github.com/satoshilabs/slips/blob/master/slip-0132.md
+
+// NetworkType is the network an extended key version belongs to.
+type NetworkType uint8
+
+const (
+	Mainnet NetworkType = iota
+	Testnet
+)
+
+// ScriptType is the script type an extended key version is meant to derive.
+type ScriptType uint8
+
+const (
+	// ScriptP2PKH is used by xpub/tpub, P2PKH or P2SH
+	ScriptP2PKH ScriptType = iota
+	// ScriptP2WPKHInP2SH is used by ypub/upub, nested segwit
+	ScriptP2WPKHInP2SH
+	// ScriptP2WPKH is used by zpub/vpub, native segwit
+	ScriptP2WPKH
+	// ScriptP2WSHInP2SH is used by Ypub/Upub, nested segwit multisig
+	ScriptP2WSHInP2SH
+	// ScriptP2WSH is used by Zpub/Vpub, native segwit multisig
+	ScriptP2WSH
+)
+
+var (
+	// ErrUnknownScriptType is returned when no version is registered for the network and script type.
+	ErrUnknownScriptType = errors.New("no extended key version for the network and script type")
+)
+
+// KeyVersion describes a pair of public and private extended key version bytes.
+type KeyVersion struct {
+	Public        uint32
+	Private       uint32
+	PublicPrefix  string
+	PrivatePrefix string
+	Network       NetworkType
+	Script        ScriptType
+}
+
+// keyVersions is the registry of the known extended key versions.
+var keyVersions = []KeyVersion{
+	{PublicKeyPrefix, PrivateKeyPrefix, "xpub", "xprv", Mainnet, ScriptP2PKH},
+	{0x049d7cb2, 0x049d7878, "ypub", "yprv", Mainnet, ScriptP2WPKHInP2SH},
+	{0x04b24746, 0x04b2430c, "zpub", "zprv", Mainnet, ScriptP2WPKH},
+	{0x0295b43f, 0x0295b005, "Ypub", "Yprv", Mainnet, ScriptP2WSHInP2SH},
+	{0x02aa7ed3, 0x02aa7a99, "Zpub", "Zprv", Mainnet, ScriptP2WSH},
+	{TestnetPublicKeyPrefix, TestnetPrivateKeyPrefix, "tpub", "tprv", Testnet, ScriptP2PKH},
+	{0x044a5262, 0x044a4e28, "upub", "uprv", Testnet, ScriptP2WPKHInP2SH},
+	{0x045f1cf6, 0x045f18bc, "vpub", "vprv", Testnet, ScriptP2WPKH},
+	{0x024289ef, 0x024285b5, "Upub", "Uprv", Testnet, ScriptP2WSHInP2SH},
+	{0x02575483, 0x02575048, "Vpub", "Vprv", Testnet, ScriptP2WSH},
+}
+
+// LookupVersion returns the registered key version for the public or private version bytes,
+// and whether the version bytes belong to a private key.
+func LookupVersion(version uint32) (KeyVersion, bool, error) {
+	for _, v := range keyVersions {
+		if v.Public == version {
+			return v, false, nil
+		}
+		if v.Private == version {
+			return v, true, nil
+		}
+	}
+	return KeyVersion{}, false, ErrUnknownVersion
+}
+
+// VersionFor returns the registered key version for the network and script type.
+func VersionFor(network NetworkType, script ScriptType) (KeyVersion, error) {
+	for _, v := range keyVersions {
+		if v.Network == network && v.Script == script {
+			return v, nil
+		}
+	}
+	return KeyVersion{}, ErrUnknownScriptType
+}
+
+// KeyVersion returns the registered version of the extended public key.
+func (k *PublicKey) KeyVersion() (KeyVersion, error) {
+	if len(k.Version) != 4 {
+		return KeyVersion{}, ErrUnknownVersion
+	}
+	v, _, err := LookupVersion(binary.BigEndian.Uint32(k.Version))
+	return v, err
+}
+
+// WithVersion returns a copy of the extended public key using the version bytes of v.
+func (k *PublicKey) WithVersion(v KeyVersion) *PublicKey {
+	key := *k
+	key.Version = uint32ToBytes(v.Public)
+	return &key
+}
+
+// KeyVersion returns the registered version of the extended private key.
+func (k *PrivateKey) KeyVersion() (KeyVersion, error) {
+	if len(k.Version) != 4 {
+		return KeyVersion{}, ErrUnknownVersion
+	}
+	v, _, err := LookupVersion(binary.BigEndian.Uint32(k.Version))
+	return v, err
+}
+
+// WithVersion returns a copy of the extended private key using the version bytes of v.
+func (k *PrivateKey) WithVersion(v KeyVersion) *PrivateKey {
+	key := *k
+	key.Version = uint32ToBytes(v.Private)
+	key.PublicKey.Version = uint32ToBytes(v.Public)
+	return &key
+}
+
+// ConvertExtendedKey re-encodes an extended key string with the version bytes of v,
+// e.g. converting a zpub exported by a hardware wallet to an xpub.
+func ConvertExtendedKey(key string, v KeyVersion) (string, error) {
+	extendedKey, err := ParseExtendedKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch k := extendedKey.(type) {
+	case *PrivateKey:
+		return k.WithVersion(v).String(), nil
+	case *PublicKey:
+		return k.WithVersion(v).String(), nil
+	}
+	return "", ErrUnknownVersion
+}
+
+// NewMasterKeyWithVersion creates a new master key serialized with the version bytes of v.
+func NewMasterKeyWithVersion(seed []byte, v KeyVersion) (*PrivateKey, error) {
+	key, err := NewMasterKey(seed)
+	if err != nil {
+		return nil, err
+	}
+	return key.WithVersion(v), nil
+}
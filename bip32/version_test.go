@@ -0,0 +1,104 @@
+package bip32
+
+import (
+	"testing"
+)
+
+// bip84Seed is the seed of "abandon abandon ... about" used by the BIP-49 and BIP-84 test vectors.
+const bip84Seed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+func TestNewMasterKeyWithVersion(t *testing.T) {
+	v, err := VersionFor(Mainnet, ScriptP2WPKH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := NewMasterKeyWithVersion(mustDecodeHex(t, bip84Seed), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := master.String(), "zprvAWgYBBk7JR8Gjrh4UJQ2uJdG1r3WNRRfURiABBE3RvMXYSrRJL62XuezvGdPvG6GFBZduosCc1YP5wixPox7zhZLfiUm8aunE96BBa4Kei5"; got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+
+	// m/84'/0'/0'
+	account := master
+	for _, idx := range []uint32{HardenedKeyZeroIndex + 84, HardenedKeyZeroIndex, HardenedKeyZeroIndex} {
+		account, err = account.Derive(idx)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := account.String(), "zprvAdG4iTXWBoARxkkzNpNh8r6Qag3irQB8PzEMkAFeTRXxHpbF9z4QgEvBRmfvqWvGp42t42nvgGpNgYSJA9iefm1yYNZKEm7z6qUWCroSQnE"; got != want {
+		t.Errorf("account String() = %s, want %s", got, want)
+	}
+	if got, want := account.ToPublicKey().String(), "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs"; got != want {
+		t.Errorf("account ToPublicKey() = %s, want %s", got, want)
+	}
+}
+
+func TestConvertExtendedKey(t *testing.T) {
+	const (
+		tpub = "tpubDD7tXK8KeQ3YY83yWq755fHY2JW8Ha8Q765tknUM5rSvjPcGWfUppDFMpQ1ScziKfW3ZNtZvAD7M3u7bSs7HofjTD3KP3YxPK7X6hwV8Rk2"
+		upub = "upub5EFU65HtV5TeiSHmZZm7FUffBGy8UKeqp7vw43jYbvZPpoVsgU93oac7Wk3u6moKegAEWtGNF8DehrnHtv21XXEMYRUocHqguyjknFHYfgY"
+	)
+
+	v, err := VersionFor(Testnet, ScriptP2WPKHInP2SH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ConvertExtendedKey(tpub, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != upub {
+		t.Errorf("ConvertExtendedKey() = %s, want %s", got, upub)
+	}
+
+	parsed, err := ParseExtendedKey(upub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyVersion, err := parsed.(*PublicKey).KeyVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyVersion.Network != Testnet || keyVersion.Script != ScriptP2WPKHInP2SH || keyVersion.PublicPrefix != "upub" {
+		t.Errorf("KeyVersion() = %+v", keyVersion)
+	}
+
+	v, err = VersionFor(Testnet, ScriptP2PKH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ConvertExtendedKey(upub, v); got != tpub {
+		t.Errorf("ConvertExtendedKey() = %s, want %s", got, tpub)
+	}
+}
+
+func TestKeyVersionPrefixes(t *testing.T) {
+	master, err := NewMasterKey(mustDecodeHex(t, bip84Seed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range keyVersions {
+		key := master.WithVersion(v)
+		if got := key.String()[:4]; got != v.PrivatePrefix {
+			t.Errorf("private prefix = %s, want %s", got, v.PrivatePrefix)
+		}
+		if got := key.ToPublicKey().String()[:4]; got != v.PublicPrefix {
+			t.Errorf("public prefix = %s, want %s", got, v.PublicPrefix)
+		}
+
+		parsed, err := ParseExtendedKey(key.String())
+		if err != nil {
+			t.Fatalf("%s: ParseExtendedKey() error = %v", v.PrivatePrefix, err)
+		}
+		if got, _ := parsed.(*PrivateKey).KeyVersion(); got != v {
+			t.Errorf("KeyVersion() = %+v, want %+v", got, v)
+		}
+	}
+
+	if _, err := VersionFor(NetworkType(9), ScriptP2PKH); err != ErrUnknownScriptType {
+		t.Errorf("VersionFor() error = %v, want %v", err, ErrUnknownScriptType)
+	}
+}
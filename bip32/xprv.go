@@ -1 +1,33 @@
 package bip32
+
+import (
+	"bytes"
+)
+
+// Serialize returns the 78 bytes BIP-32 serialization of the extended private key.
+func (k *PrivateKey) Serialize() []byte {
+	// The private key is prefixed with 0x00 to match the length of the public key data.
+	buffer := new(bytes.Buffer)
+	buffer.Grow(SerializedKeyLen)
+	buffer.Write(k.Version)
+	buffer.WriteByte(k.Level)
+	buffer.Write(k.ParentFP)
+	buffer.Write(uint32ToBytes(k.ChildIndex))
+	buffer.Write(k.ChainCode)
+	buffer.WriteByte(0x0)
+	buffer.Write(k.Data)
+	return buffer.Bytes()
+}
+
+// String returns the Base58Check encoding of the extended private key, e.g. xprv...
+func (k *PrivateKey) String() string {
+	if 0 == len(k.Data) {
+		return "zeroed private key"
+	}
+
+	encoded, err := Base58CheckEncode(k.Serialize())
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
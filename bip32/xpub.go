@@ -1 +1,34 @@
 package bip32
+
+import (
+	"bytes"
+)
+
+// Serialize returns the 78 bytes BIP-32 serialization of the extended public key.
+func (k *PublicKey) Serialize() []byte {
+	// The serialized format is:
+	//   version (4) || depth (1) || parent fingerprint (4)) ||
+	//   child num (4) || chain code (32) || key data (33)
+	buffer := new(bytes.Buffer)
+	buffer.Grow(SerializedKeyLen)
+	buffer.Write(k.Version)
+	buffer.WriteByte(k.Level)
+	buffer.Write(k.ParentFP)
+	buffer.Write(uint32ToBytes(k.ChildIndex))
+	buffer.Write(k.ChainCode)
+	buffer.Write(k.Data)
+	return buffer.Bytes()
+}
+
+// String returns the Base58Check encoding of the extended public key, e.g. xpub...
+func (k *PublicKey) String() string {
+	if 0 == len(k.Data) {
+		return "zeroed public key"
+	}
+
+	encoded, err := Base58CheckEncode(k.Serialize())
+	if err != nil {
+		return ""
+	}
+	return encoded
+}
@@ -2,27 +2,170 @@ package bip39
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
 )
 
-var ErrInvalidEntropyLength = errors.New("entropy length must be [128, 256] bits")
+var (
+	ErrInvalidEntropyLength = errors.New("entropy length must be [128, 256] bits")
+	ErrInvalidDiceRoll      = errors.New("dice rolls must be 1 to 6")
+	ErrInvalidCoinFlip      = errors.New("coin flips must be 0 or 1, or H or T")
+	ErrNotEnoughRolls       = errors.New("not enough rolls for the entropy length")
+	ErrBiasedRolls          = errors.New("rolls are too unevenly distributed, the dice or coin may be biased")
+)
+
+// randReader is the system randomness, replaced in tests.
+var randReader io.Reader = rand.Reader
+
+// Critical values of the chi-squared test at a significance of 0.001, for
+// 5 degrees of freedom (a die) and 1 (a coin). A fair die or coin fails
+// the test once in a thousand tries.
+const (
+	diceChiSquaredLimit = 20.515
+	coinChiSquaredLimit = 10.828
+)
 
 type Entropy struct {
 	bits []byte
 }
 
+// EntropyOptions tunes the entropy built from dice rolls or coin flips.
+type EntropyOptions struct {
+	// MixRandom XORs the entropy with system randomness, so that it is
+	// no weaker than either source if the other is compromised.
+	MixRandom bool
+}
+
 // NewEntropy returns a new Entropy instance.
-func NewEntropy(bitSize int) *Entropy {
+func NewEntropy(bitSize int) (*Entropy, error) {
+	return NewEntropyFromReader(randReader, bitSize)
+}
+
+// NewEntropyFromReader reads bitSize bits of entropy from r, such as a
+// hardware random number generator.
+func NewEntropyFromReader(r io.Reader, bitSize int) (*Entropy, error) {
 	// 校验长度
-	err := validateEntropyBitLen(bitSize)
-	if err != nil {
-		return nil
+	if err := validateEntropyBitLen(bitSize); err != nil {
+		return nil, fmt.Errorf("%w: got %d", err, bitSize)
 	}
 
 	// 生成随机熵
 	entropy := make([]byte, bitSize/8)
-	_, err = rand.Read(entropy)
-	return &Entropy{entropy}
+	if _, err := io.ReadFull(r, entropy); err != nil {
+		return nil, fmt.Errorf("reading entropy: %w", err)
+	}
+	return &Entropy{entropy}, nil
+}
+
+// NewEntropyFromBytes wraps entropy generated elsewhere.
+func NewEntropyFromBytes(b []byte) (*Entropy, error) {
+	if err := validateEntropyBitLen(len(b) * 8); err != nil {
+		return nil, fmt.Errorf("%w: got %d", err, len(b)*8)
+	}
+	return &Entropy{append([]byte(nil), b...)}, nil
+}
+
+// Bytes returns a copy of the entropy.
+func (e *Entropy) Bytes() []byte {
+	return append([]byte(nil), e.bits...)
+}
+
+// NewEntropyFromDice builds bitSize bits of entropy from base-6 dice rolls,
+// written as the digits 1 to 6; white space is ignored. A roll carries
+// log2(6) bits, so 128 bits take at least 50 rolls and 256 bits 100. The
+// entropy is the SHA-256 of the rolls, truncated to bitSize, which anyone
+// can recompute from the rolls by hand.
+func NewEntropyFromDice(rolls string, bitSize int, opts *EntropyOptions) (*Entropy, error) {
+	rolls = stripSpace(rolls)
+	counts := make([]int, 6)
+	// 位置按字符计数，而不是 range 给出的字节下标
+	roll := 0
+	for _, r := range rolls {
+		roll++
+		if r < '1' || r > '6' {
+			return nil, fmt.Errorf("%w: %q at roll %d", ErrInvalidDiceRoll, r, roll)
+		}
+		counts[r-'1']++
+	}
+	return entropyFromRolls(rolls, counts, bitSize, diceChiSquaredLimit, opts)
+}
+
+// NewEntropyFromCoinFlips builds bitSize bits of entropy from coin flips,
+// written as 0 and 1 or as H and T (heads is 1); white space is ignored.
+// At least bitSize flips are needed. The entropy is the SHA-256 of the
+// flips written as 0 and 1, truncated to bitSize.
+func NewEntropyFromCoinFlips(flips string, bitSize int, opts *EntropyOptions) (*Entropy, error) {
+	flips = stripSpace(flips)
+	var normalized strings.Builder
+	counts := make([]int, 2)
+	flip := 0
+	for _, r := range flips {
+		flip++
+		switch r {
+		case '0', 'T', 't':
+			normalized.WriteByte('0')
+			counts[0]++
+		case '1', 'H', 'h':
+			normalized.WriteByte('1')
+			counts[1]++
+		default:
+			return nil, fmt.Errorf("%w: %q at flip %d", ErrInvalidCoinFlip, r, flip)
+		}
+	}
+	return entropyFromRolls(normalized.String(), counts, bitSize, coinChiSquaredLimit, opts)
+}
+
+// entropyFromRolls checks that there are enough rolls of evenly
+// distributed faces, and hashes them into entropy.
+func entropyFromRolls(rolls string, counts []int, bitSize int, chiSquaredLimit float64, opts *EntropyOptions) (*Entropy, error) {
+	if err := validateEntropyBitLen(bitSize); err != nil {
+		return nil, fmt.Errorf("%w: got %d", err, bitSize)
+	}
+	if opts == nil {
+		opts = &EntropyOptions{}
+	}
+
+	// 每次投掷提供 log2(面数) 位熵
+	minRolls := int(math.Ceil(float64(bitSize) / math.Log2(float64(len(counts)))))
+	if len(rolls) < minRolls {
+		return nil, fmt.Errorf("%w: got %d, want at least %d", ErrNotEnoughRolls, len(rolls), minRolls)
+	}
+	if stat := chiSquared(counts, len(rolls)); stat > chiSquaredLimit {
+		return nil, fmt.Errorf("%w: chi-squared %.1f exceeds %.1f", ErrBiasedRolls, stat, chiSquaredLimit)
+	}
+
+	hash := sha256.Sum256([]byte(rolls))
+	entropy := hash[:bitSize/8]
+	if opts.MixRandom {
+		random := make([]byte, len(entropy))
+		if _, err := io.ReadFull(randReader, random); err != nil {
+			return nil, fmt.Errorf("reading entropy: %w", err)
+		}
+		for i := range entropy {
+			entropy[i] ^= random[i]
+		}
+	}
+	return &Entropy{entropy}, nil
+}
+
+// chiSquared is the chi-squared statistic of face counts against a uniform
+// distribution over n rolls.
+func chiSquared(counts []int, n int) float64 {
+	expected := float64(n) / float64(len(counts))
+	var stat float64
+	for _, c := range counts {
+		d := float64(c) - expected
+		stat += d * d / expected
+	}
+	return stat
+}
+
+func stripSpace(s string) string {
+	return strings.Join(strings.Fields(s), "")
 }
 
 // validateEntropyBitLen returns an error if bitSize is not a valid entropy length. 常见的有 128, 160, 192, 224, 256
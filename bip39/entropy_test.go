@@ -1,6 +1,13 @@
 package bip39
 
-import "testing"
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
 
 func TestValidateEntropyBitLen(t *testing.T) {
 	length := 128
@@ -12,8 +19,129 @@ func TestValidateEntropyBitLen(t *testing.T) {
 
 func TestNewEntropy(t *testing.T) {
 	length := 128
-	entropy := NewEntropy(length)
+	entropy, err := NewEntropy(length)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if len(entropy.bits) != length/8 {
 		t.Errorf("Entropy length error")
 	}
+
+	if _, err := NewEntropy(100); !errors.Is(err, ErrInvalidEntropyLength) {
+		t.Errorf("NewEntropy(100) error = %v, want %v", err, ErrInvalidEntropyLength)
+	}
+	if _, err := NewEntropyFromReader(strings.NewReader("short"), 128); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("NewEntropyFromReader() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestNewEntropyFromBytes(t *testing.T) {
+	b := bytes.Repeat([]byte{0x7f}, 32)
+	entropy, err := NewEntropyFromBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[0] = 0
+	if got := entropy.Bytes(); !bytes.Equal(got, bytes.Repeat([]byte{0x7f}, 32)) {
+		t.Errorf("Bytes() = %x, want the bytes at construction", got)
+	}
+	if _, err := NewEntropyFromBytes(b[:15]); !errors.Is(err, ErrInvalidEntropyLength) {
+		t.Errorf("NewEntropyFromBytes() error = %v, want %v", err, ErrInvalidEntropyLength)
+	}
+}
+
+func TestNewEntropyFromDice(t *testing.T) {
+	tests := []struct {
+		name    string
+		rolls   string
+		bitSize int
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "sha256 of the rolls",
+			rolls:   strings.Repeat("16254", 10),
+			bitSize: 128,
+			want:    "760226922a204b88cc43eef930cb177b",
+		},
+		{
+			name:    "white space",
+			rolls:   strings.Repeat("1625 4\n", 10),
+			bitSize: 128,
+			want:    "760226922a204b88cc43eef930cb177b",
+		},
+		{name: "not enough rolls", rolls: strings.Repeat("16254", 10), bitSize: 256, wantErr: ErrNotEnoughRolls},
+		{name: "not a face", rolls: strings.Repeat("16250", 10), bitSize: 128, wantErr: ErrInvalidDiceRoll},
+		{name: "biased", rolls: strings.Repeat("6", 100), bitSize: 128, wantErr: ErrBiasedRolls},
+		{name: "entropy length", rolls: strings.Repeat("16254", 30), bitSize: 512, wantErr: ErrInvalidEntropyLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entropy, err := NewEntropyFromDice(tt.rolls, tt.bitSize, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewEntropyFromDice() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && hex.EncodeToString(entropy.bits) != tt.want {
+				t.Errorf("NewEntropyFromDice() = %x, want %v", entropy.bits, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEntropyFromCoinFlips(t *testing.T) {
+	want := "c7181103b1e2cc71112a1517e1fe6c11"
+	for _, flips := range []string{strings.Repeat("01", 64), strings.Repeat("TH ", 64), strings.Repeat("th", 64)} {
+		entropy, err := NewEntropyFromCoinFlips(flips, 128, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(entropy.bits); got != want {
+			t.Errorf("NewEntropyFromCoinFlips(%q) = %v, want %v", flips[:6], got, want)
+		}
+	}
+
+	if _, err := NewEntropyFromCoinFlips(strings.Repeat("01", 63), 128, nil); !errors.Is(err, ErrNotEnoughRolls) {
+		t.Errorf("NewEntropyFromCoinFlips() of 126 flips error = %v, want %v", err, ErrNotEnoughRolls)
+	}
+	if _, err := NewEntropyFromCoinFlips(strings.Repeat("110", 64), 128, nil); !errors.Is(err, ErrBiasedRolls) {
+		t.Errorf("NewEntropyFromCoinFlips() of two heads to a tail error = %v, want %v", err, ErrBiasedRolls)
+	}
+	if _, err := NewEntropyFromCoinFlips(strings.Repeat("012", 64), 128, nil); !errors.Is(err, ErrInvalidCoinFlip) {
+		t.Errorf("NewEntropyFromCoinFlips() error = %v, want %v", err, ErrInvalidCoinFlip)
+	}
+}
+
+func TestInvalidRollPosition(t *testing.T) {
+	// The position counts characters, not the bytes of a multibyte one
+	_, err := NewEntropyFromDice("12 €", 128, nil)
+	if want := `dice rolls must be 1 to 6: '€' at roll 3`; err == nil || err.Error() != want {
+		t.Errorf("NewEntropyFromDice() error = %v, want %v", err, want)
+	}
+	_, err = NewEntropyFromCoinFlips("HT €", 128, nil)
+	if want := `coin flips must be 0 or 1, or H or T: '€' at flip 3`; err == nil || err.Error() != want {
+		t.Errorf("NewEntropyFromCoinFlips() error = %v, want %v", err, want)
+	}
+}
+
+func TestEntropyMixRandom(t *testing.T) {
+	defer func(r io.Reader) { randReader = r }(randReader)
+	rolls := strings.Repeat("16254", 10)
+
+	randReader = bytes.NewReader(bytes.Repeat([]byte{0xff}, 16))
+	entropy, err := NewEntropyFromDice(rolls, 128, &EntropyOptions{MixRandom: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(entropy.bits), "89fdd96dd5dfb47733bc1106cf34e884"; got != want {
+		t.Errorf("NewEntropyFromDice() mixed = %v, want %v", got, want)
+	}
+
+	// A failing system source is an error, not weaker entropy
+	randReader = strings.NewReader("")
+	if _, err := NewEntropyFromDice(rolls, 128, &EntropyOptions{MixRandom: true}); !errors.Is(err, io.EOF) {
+		t.Errorf("NewEntropyFromDice() error = %v, want %v", err, io.EOF)
+	}
+	if _, err := NewEntropy(128); !errors.Is(err, io.EOF) {
+		t.Errorf("NewEntropy() error = %v, want %v", err, io.EOF)
+	}
 }
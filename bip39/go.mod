@@ -1,3 +1,8 @@
 module github.com/dubuqingfeng/signer/bip39
 
 go 1.18
+
+require (
+	golang.org/x/crypto v0.9.0
+	golang.org/x/text v0.13.0
+)
@@ -0,0 +1,75 @@
+package bip39
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrUnknownLanguage   = errors.New("mnemonic words do not belong to any single wordlist")
+	ErrAmbiguousLanguage = errors.New("mnemonic words belong to several wordlists")
+)
+
+// ideographicSpace separates the words of a Japanese mnemonic.
+const ideographicSpace = "　"
+
+// wordSeparator returns the separator between the words of a mnemonic in
+// the language.
+func wordSeparator(lang Language) string {
+	if lang == Japanese {
+		return ideographicSpace
+	}
+	return " "
+}
+
+// mnemonicWords splits a mnemonic into its NFKD normalized words. Any white
+// space separates words, including the ideographic space.
+func mnemonicWords(words string) []string {
+	return strings.Fields(norm.NFKD.String(words))
+}
+
+// DetectLanguage returns the language whose wordlist contains every word of
+// the mnemonic. Some words are shared between wordlists, such as English
+// and French or the two Chinese lists; a mnemonic made only of shared words
+// is resolved by its checksum, and returns ErrAmbiguousLanguage if that
+// still leaves several languages.
+func DetectLanguage(words string) (Language, error) {
+	mnemonic := mnemonicWords(words)
+	if len(mnemonic) == 0 {
+		return "", ErrUnknownLanguage
+	}
+	var found []Language
+	for _, lang := range Languages {
+		index, _ := wordIndex(lang)
+		all := true
+		for _, word := range mnemonic {
+			if _, ok := index[word]; !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			found = append(found, lang)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", ErrUnknownLanguage
+	case 1:
+		return found[0], nil
+	}
+
+	var valid []Language
+	for _, lang := range found {
+		if _, err := EntropyFromMnemonic(words, lang); err == nil {
+			valid = append(valid, lang)
+		}
+	}
+	if len(valid) == 1 {
+		return valid[0], nil
+	}
+	return "", fmt.Errorf("%w: %v", ErrAmbiguousLanguage, found)
+}
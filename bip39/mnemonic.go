@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"crypto/sha512"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
@@ -18,13 +20,39 @@ const (
 	Pbkdf2SeedLen = 64
 )
 
-// NewMnemonicFromEntropy generates a new mnemonic from a byte slice
-func NewMnemonicFromEntropy(entropy Entropy) (string, error) {
+var (
+	ErrInvalidWordCount = errors.New("mnemonic must be 12, 15, 18, 21 or 24 words")
+	ErrUnknownWord      = errors.New("mnemonic word is not in the wordlist")
+	ErrChecksumMismatch = errors.New("mnemonic checksum does not match")
+)
+
+// UnknownWordError reports a mnemonic word missing from the wordlist. Index
+// is the position of the word in the mnemonic, starting at 0.
+type UnknownWordError struct {
+	Index    int
+	Word     string
+	Language Language
+}
+
+func (e *UnknownWordError) Error() string {
+	return fmt.Sprintf("word %d %q is not in the %s wordlist", e.Index+1, e.Word, e.Language)
+}
+
+func (e *UnknownWordError) Unwrap() error {
+	return ErrUnknownWord
+}
+
+// NewMnemonicFromEntropy generates a new mnemonic in the language from a byte slice
+func NewMnemonicFromEntropy(entropy Entropy, lang Language) (string, error) {
 	// 先校验 Entropy 的长度是否符合要求
 	err := validateEntropyBitLen(len(entropy.bits) * 8)
 	if err != nil {
 		return "", err
 	}
+	list, err := wordList(lang)
+	if err != nil {
+		return "", err
+	}
 
 	// 将 Entropy 转换为字节数组
 	var entropyBuff bytes.Buffer
@@ -42,49 +70,73 @@ func NewMnemonicFromEntropy(entropy Entropy) (string, error) {
 	for i := 0; i < mnemonicLen; i++ {
 		wordStrBin := mnemonicBinStr[i*wordBitLen : (i+1)*wordBitLen]
 		wordIdx, _ := strconv.ParseInt(wordStrBin, 2, 16)
-		mnemonic = append(mnemonic, english[wordIdx])
+		mnemonic = append(mnemonic, list[wordIdx])
 	}
 
-	return strings.Join(mnemonic, " "), nil
+	return strings.Join(mnemonic, wordSeparator(lang)), nil
 }
 
-// NewSeedFromMnemonic generates a seed from a mnemonic, and a password
-func NewSeedFromMnemonic(words, passphrase string) ([]byte, error) {
-	// 先校验 mnemonic 的长度是否符合要求, 并且是否含有空格， 以及是否在 words 中
-	err := validateMnemonic(words)
+// NewSeedFromMnemonic generates a seed from a mnemonic in the language, and a password
+func NewSeedFromMnemonic(words, passphrase string, lang Language) ([]byte, error) {
+	// 先校验 mnemonic 的长度是否符合要求, 以及是否在 words 中
+	err := validateMnemonic(words, lang)
 	if err != nil {
 		return nil, err
 	}
+	// BIP-39 hashes the NFKD form of the mnemonic and of the salt. The words
+	// are joined by a single space, which is also the NFKD form of the
+	// ideographic space used in Japanese.
+	mnemonic := strings.Join(mnemonicWords(words), " ")
 	// Get salt
-	salt := "mnemonic" + passphrase
+	salt := norm.NFKD.String("mnemonic" + passphrase)
 	// Generate seed
-	return pbkdf2.Key([]byte(words), []byte(salt), Pbkdf2Rounds, Pbkdf2SeedLen, sha512.New), nil
+	return pbkdf2.Key([]byte(mnemonic), []byte(salt), Pbkdf2Rounds, Pbkdf2SeedLen, sha512.New), nil
+}
+
+// validateMnemonic checks if a mnemonic in the language is valid
+func validateMnemonic(words string, lang Language) error {
+	_, err := EntropyFromMnemonic(words, lang)
+	return err
 }
 
-// validateMnemonic checks if a mnemonic is valid
-func validateMnemonic(words string) error {
+// EntropyFromMnemonic returns the entropy encoded by a mnemonic in the
+// language, after verifying its checksum. The error is ErrInvalidWordCount,
+// an *UnknownWordError or ErrChecksumMismatch, so that callers can tell the
+// user what to fix.
+func EntropyFromMnemonic(words string, lang Language) ([]byte, error) {
+	index, err := wordIndex(lang)
+	if err != nil {
+		return nil, err
+	}
 	// 先校验 mnemonic 的长度是否符合要求
-	wordsLen := len(strings.Split(words, " "))
-	if wordsLen < 12 || wordsLen > 24 {
-		return fmt.Errorf("mnemonic must be 12-24 words")
+	mnemonic := mnemonicWords(words)
+	if len(mnemonic) < 12 || len(mnemonic) > 24 || len(mnemonic)%3 != 0 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidWordCount, len(mnemonic))
 	}
-	// 再校验 mnemonic 中是否含有空格
-	if strings.Contains(words, " ") {
-		return fmt.Errorf("mnemonic must not contain spaces")
+
+	// 每个单词 11 位，拼接成 entropy + checksum
+	var mnemonicBuff bytes.Buffer
+	for i, word := range mnemonic {
+		wordIdx, ok := index[word]
+		if !ok {
+			return nil, &UnknownWordError{Index: i, Word: word, Language: lang}
+		}
+		mnemonicBuff.WriteString(fmt.Sprintf("%.11b", wordIdx))
 	}
-	// 临时遍历到 map
-	var englishWordsMap map[string]int
-	englishWordsMap = make(map[string]int)
-	for i, word := range english {
-		englishWordsMap[word] = i
+	mnemonicBinStr := mnemonicBuff.String()
+
+	// checksum 占 entropy 长度的 1/32, 即总长度的 1/33
+	checkSumBitLen := len(mnemonicBinStr) / 33
+	entropyBinStr := mnemonicBinStr[:len(mnemonicBinStr)-checkSumBitLen]
+	entropy := make([]byte, len(entropyBinStr)/8)
+	for i := range entropy {
+		b, _ := strconv.ParseUint(entropyBinStr[i*8:(i+1)*8], 2, 8)
+		entropy[i] = byte(b)
 	}
-	// 是否在 words 中
-	for _, word := range strings.Split(words, " ") {
-		if _, ok := englishWordsMap[word]; !ok {
-			return fmt.Errorf("mnemonic must contain only words in the english dictionary")
-		}
+	if entropyCheckSumBinStr(entropy) != mnemonicBinStr[len(entropyBinStr):] {
+		return nil, ErrChecksumMismatch
 	}
-	return nil
+	return entropy, nil
 }
 
 // entropyCheckSumBinStr will return the checksum for the given entropy bits
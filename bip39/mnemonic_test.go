@@ -1,6 +1,17 @@
 package bip39
 
-import "testing"
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
 
 func Test_entropyChecksumBinStr(t *testing.T) {
 	type args struct {
@@ -27,3 +38,273 @@ func Test_entropyChecksumBinStr(t *testing.T) {
 		})
 	}
 }
+
+type languageVectors struct {
+	Passphrase string      `json:"passphrase"`
+	Vectors    [][3]string `json:"vectors"`
+}
+
+// loadVectors reads testdata/vectors.json: the Trezor vectors for English,
+// the bip32JP vectors for Japanese and, for the other languages, vectors of
+// Trezor entropies computed with the algorithm of python-mnemonic, as
+// [entropy, mnemonic, seed].
+func loadVectors(t *testing.T) map[Language]languageVectors {
+	t.Helper()
+	b, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors map[Language]languageVectors
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func TestMnemonicVectors(t *testing.T) {
+	all := loadVectors(t)
+	for _, lang := range Languages {
+		if len(all[lang].Vectors) == 0 {
+			t.Errorf("%s: no vectors", lang)
+		}
+	}
+	for lang, vectors := range all {
+		for i, v := range vectors.Vectors {
+			entropy, err := hex.DecodeString(v[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			mnemonic, err := NewMnemonicFromEntropy(Entropy{entropy}, lang)
+			if err != nil {
+				t.Fatalf("%s %d: NewMnemonicFromEntropy() error = %v", lang, i, err)
+			}
+			// The vectors are not NFKD normalized
+			if norm.NFKD.String(mnemonic) != norm.NFKD.String(v[1]) {
+				t.Errorf("%s %d: NewMnemonicFromEntropy() = %v, want %v", lang, i, mnemonic, v[1])
+			}
+			seed, err := NewSeedFromMnemonic(v[1], vectors.Passphrase, lang)
+			if err != nil {
+				t.Fatalf("%s %d: NewSeedFromMnemonic() error = %v", lang, i, err)
+			}
+			if got := hex.EncodeToString(seed); got != v[2] {
+				t.Errorf("%s %d: NewSeedFromMnemonic() = %v, want %v", lang, i, got, v[2])
+			}
+			if got, err := DetectLanguage(v[1]); err != nil || got != lang {
+				t.Errorf("%s %d: DetectLanguage() = %v, %v, want %v", lang, i, got, err, lang)
+			}
+		}
+	}
+}
+
+func TestMnemonicLanguages(t *testing.T) {
+	entropy := Entropy{bytes.Repeat([]byte{0x5a}, 16)}
+	for _, lang := range Languages {
+		t.Run(string(lang), func(t *testing.T) {
+			mnemonic, err := NewMnemonicFromEntropy(entropy, lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sep := wordSeparator(lang); len(strings.Split(mnemonic, sep)) != 12 {
+				t.Errorf("NewMnemonicFromEntropy() = %q, want 12 words separated by %q", mnemonic, sep)
+			}
+			if _, err := NewSeedFromMnemonic(mnemonic, "", lang); err != nil {
+				t.Errorf("NewSeedFromMnemonic() error = %v", err)
+			}
+			if got, err := DetectLanguage(mnemonic); err != nil || got != lang {
+				t.Errorf("DetectLanguage() = %v, %v, want %v", got, err, lang)
+			}
+		})
+	}
+}
+
+func TestNewSeedFromMnemonicNFKD(t *testing.T) {
+	// "ábaco" and "né" in composed (NFC) and decomposed (NFKD) forms
+	composed := strings.Repeat("\u00e1baco ", 11) + "abierto"
+	decomposed := strings.Repeat("a\u0301baco ", 11) + "abierto"
+	want, err := NewSeedFromMnemonic(decomposed, "ne\u0301", Spanish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewSeedFromMnemonic(composed, "n\u00e9", Spanish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("NewSeedFromMnemonic() of the NFC form = %x, want %x", got, want)
+	}
+
+	if _, err := NewSeedFromMnemonic(composed, "", English); err == nil {
+		t.Error("NewSeedFromMnemonic() of Spanish words as English error = nil")
+	}
+	if _, err := NewSeedFromMnemonic(composed, "", Language("klingon")); !errors.Is(err, ErrLanguageNotFound) {
+		t.Errorf("NewSeedFromMnemonic() error = %v, want %v", err, ErrLanguageNotFound)
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		want     Language
+		wantErr  error
+	}{
+		{
+			name:     "french",
+			mnemonic: "abaisser abaisser abaisser abaisser abaisser abaisser abaisser abaisser abaisser abaisser abaisser abeille",
+			want:     French,
+		},
+		{
+			name:     "shared by english and french",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			wantErr:  ErrAmbiguousLanguage,
+		},
+		{
+			name:     "shared words resolved by the checksum",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon angle",
+			want:     English,
+		},
+		{
+			name:     "mixed",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon ábaco",
+			wantErr:  ErrUnknownLanguage,
+		},
+		{
+			name:    "empty",
+			wantErr: ErrUnknownLanguage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectLanguage(tt.mnemonic)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectLanguage() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DetectLanguage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntropyFromMnemonic(t *testing.T) {
+	for lang, vectors := range loadVectors(t) {
+		for i, v := range vectors.Vectors {
+			entropy, err := EntropyFromMnemonic(v[1], lang)
+			if err != nil {
+				t.Fatalf("%s %d: EntropyFromMnemonic() error = %v", lang, i, err)
+			}
+			if got := hex.EncodeToString(entropy); got != v[0] {
+				t.Errorf("%s %d: EntropyFromMnemonic() = %v, want %v", lang, i, got, v[0])
+			}
+		}
+	}
+}
+
+func TestEntropyFromMnemonicErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  error
+		wantWord *UnknownWordError
+	}{
+		{
+			name:     "eleven words",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			wantErr:  ErrInvalidWordCount,
+		},
+		{
+			name:     "thirteen words",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			wantErr:  ErrInvalidWordCount,
+		},
+		{
+			name:     "unknown word",
+			mnemonic: "abandon abandon abandonn abandon abandon abandon abandon abandon abandon abandon abandon about",
+			wantErr:  ErrUnknownWord,
+			wantWord: &UnknownWordError{Index: 2, Word: "abandonn", Language: English},
+		},
+		{
+			name:     "checksum",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			wantErr:  ErrChecksumMismatch,
+		},
+		{
+			name:     "checksum of 24 words",
+			mnemonic: strings.Repeat("zoo ", 24),
+			wantErr:  ErrChecksumMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EntropyFromMnemonic(tt.mnemonic, English)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("EntropyFromMnemonic() error = %v, want %v", err, tt.wantErr)
+			}
+			var wordErr *UnknownWordError
+			if errors.As(err, &wordErr) && *wordErr != *tt.wantWord {
+				t.Errorf("EntropyFromMnemonic() error = %+v, want %+v", wordErr, tt.wantWord)
+			}
+			if _, err := NewSeedFromMnemonic(tt.mnemonic, "", English); !errors.Is(err, tt.wantErr) {
+				t.Errorf("NewSeedFromMnemonic() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMnemonic(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		lang     Language
+	}{
+		{
+			name:     "spaces",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			lang:     English,
+		},
+		{
+			name:     "extra white space",
+			mnemonic: " abandon  abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon\tabout\n",
+			lang:     English,
+		},
+		{
+			name:     "ideographic spaces",
+			mnemonic: "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら",
+			lang:     Japanese,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateMnemonic(tt.mnemonic, tt.lang); err != nil {
+				t.Errorf("validateMnemonic() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestWordListChecksums compares every wordlist with the canonical file of
+// the bips repository, bip-0039/<language>.txt: one word per line, each
+// followed by a newline.
+func TestWordListChecksums(t *testing.T) {
+	want := map[Language]string{
+		English:            "2f5eed53a4727b4bf8880d8f3f199efc90e58503646d9ff8eff3a2ed3b24dbda",
+		Japanese:           "2eed0aef492291e061633d7ad8117f1a2b03eb80a29d0e4e3117ac2528d05ffd",
+		Korean:             "9e95f86c167de88f450f0aaf89e87f6624a57f973c67b516e338e8e8b8897f60",
+		Spanish:            "46846a5a0139d1e3cb77293e521c2865f7bcdb82c44e8d0a06a2cd0ecba48c0b",
+		ChineseSimplified:  "5c5942792bd8340cb8b27cd592f1015edf56a8c5b26276ee18a482428e7c5726",
+		ChineseTraditional: "417b26b3d8500a4ae3d59717d7011952db6fc2fb84b807f3f94ac734e89c1b5f",
+		French:             "ebc3959ab7801a1df6bac4fa7d970652f1df76b683cd2f4003c941c63d517e59",
+		Italian:            "d392c49fdb700a24cd1fceb237c1f65dcc128f6b34a8aacb58b59384b5c648c2",
+		Czech:              "7e80e161c3e93d9554c2efb78d4e3cebf8fc727e9c52e03b83b94406bdcc95fc",
+	}
+	for _, lang := range Languages {
+		list, err := wordList(lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256([]byte(strings.Join(list, "\n") + "\n"))
+		if got := hex.EncodeToString(sum[:]); got != want[lang] {
+			t.Errorf("%s: SHA-256 of the wordlist = %v, want %v", lang, got, want[lang])
+		}
+	}
+}
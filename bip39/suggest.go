@@ -0,0 +1,166 @@
+package bip39
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSuggestDistance is the largest edit distance SuggestWords considers a
+// typo rather than a different word.
+const maxSuggestDistance = 2
+
+var (
+	ErrAmbiguousPrefix         = errors.New("prefix matches several words")
+	ErrNegativeSuggestionCount = errors.New("suggestion count must not be negative")
+)
+
+// ResolvePrefix returns the word of the language that starts with prefix.
+// The English, Spanish, French, Italian and Czech wordlists are built so
+// that the first four letters identify a word, which is all some backups
+// record. A complete word is returned as is even if it starts longer words.
+func ResolvePrefix(prefix string, lang Language) (string, error) {
+	list, err := wordList(lang)
+	if err != nil {
+		return "", err
+	}
+	prefix = norm.NFKD.String(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return "", ErrUnknownWord
+	}
+	var found []string
+	for _, word := range list {
+		if word == prefix {
+			return word, nil
+		}
+		if strings.HasPrefix(word, prefix) {
+			found = append(found, word)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("%w: %q", ErrUnknownWord, prefix)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrAmbiguousPrefix, prefix)
+}
+
+// SuggestWords returns up to n words of the language closest to word by
+// edit distance, nearest first, for correcting a typo. Words more than two
+// edits away are not suggested. A negative n is an error.
+func SuggestWords(word string, lang Language, n int) ([]string, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("%w: %d", ErrNegativeSuggestionCount, n)
+	}
+	list, err := wordList(lang)
+	if err != nil {
+		return nil, err
+	}
+	target := []rune(norm.NFKD.String(strings.TrimSpace(word)))
+
+	type candidate struct {
+		word     string
+		distance int
+	}
+	var candidates []candidate
+	for _, w := range list {
+		if d := editDistance(target, []rune(w)); d <= maxSuggestDistance {
+			candidates = append(candidates, candidate{w, d})
+		}
+	}
+	// Stable, so that words at the same distance keep the wordlist order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
+	if len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	suggestions := make([]string, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.word
+	}
+	return suggestions, nil
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// CompleteMnemonic returns every word that completes a mnemonic missing its
+// last word, such as 11 words of a 12 word mnemonic, into one with a valid
+// checksum. The last word carries the remaining entropy bits, so there are
+// 128 candidates for 12 words down to 8 for 24 words, in wordlist order.
+func CompleteMnemonic(words string, lang Language) ([]string, error) {
+	list, err := wordList(lang)
+	if err != nil {
+		return nil, err
+	}
+	index, err := wordIndex(lang)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic := mnemonicWords(words)
+	if len(mnemonic) < 11 || len(mnemonic) > 23 || (len(mnemonic)+1)%3 != 0 {
+		return nil, fmt.Errorf("%w: got %d, want one less", ErrInvalidWordCount, len(mnemonic))
+	}
+
+	var mnemonicBuff bytes.Buffer
+	for i, word := range mnemonic {
+		wordIdx, ok := index[word]
+		if !ok {
+			return nil, &UnknownWordError{Index: i, Word: word, Language: lang}
+		}
+		mnemonicBuff.WriteString(fmt.Sprintf("%.11b", wordIdx))
+	}
+	knownBinStr := mnemonicBuff.String()
+
+	// 最后一个单词由剩余的 entropy 位和 checksum 组成
+	entropyBitLen := (len(mnemonic) + 1) * wordBitLen * 32 / 33
+	freeBitLen := entropyBitLen - len(knownBinStr)
+	candidates := make([]string, 0, 1<<freeBitLen)
+	for free := 0; free < 1<<freeBitLen; free++ {
+		entropyBinStr := knownBinStr + fmt.Sprintf("%0*b", freeBitLen, free)
+		entropy := make([]byte, entropyBitLen/8)
+		for i := range entropy {
+			b, _ := strconv.ParseUint(entropyBinStr[i*8:(i+1)*8], 2, 8)
+			entropy[i] = byte(b)
+		}
+		lastBinStr := entropyBinStr[len(knownBinStr):] + entropyCheckSumBinStr(entropy)
+		wordIdx, _ := strconv.ParseInt(lastBinStr, 2, 16)
+		candidates = append(candidates, list[wordIdx])
+	}
+	return candidates, nil
+}
@@ -0,0 +1,130 @@
+package bip39
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestResolvePrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		lang    Language
+		want    string
+		wantErr error
+	}{
+		{name: "four letters", prefix: "aban", lang: English, want: "abandon"},
+		{name: "complete word", prefix: "act", lang: English, want: "act"},
+		{name: "longer prefix", prefix: "actr", lang: English, want: "actress"},
+		{name: "french", prefix: "abei", lang: French, want: "abeille"},
+		{name: "ambiguous", prefix: "ab", lang: English, wantErr: ErrAmbiguousPrefix},
+		{name: "unknown", prefix: "xyzz", lang: English, wantErr: ErrUnknownWord},
+		{name: "empty", prefix: " ", lang: English, wantErr: ErrUnknownWord},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePrefix(tt.prefix, tt.lang)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolvePrefix() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePrefixFourLetters(t *testing.T) {
+	for _, lang := range []Language{English, Spanish, French, Italian, Czech} {
+		list, _ := wordList(lang)
+		for _, word := range list {
+			// Four letters, as typed, not as decomposed by NFKD
+			prefix := []rune(norm.NFC.String(word))
+			if len(prefix) > 4 {
+				prefix = prefix[:4]
+			}
+			if got, err := ResolvePrefix(string(prefix), lang); err != nil || got != word {
+				t.Errorf("%s: ResolvePrefix(%q) = %v, %v, want %v", lang, string(prefix), got, err, word)
+			}
+		}
+	}
+}
+
+func TestSuggestWords(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+		n    int
+		want []string
+	}{
+		{name: "missing letter", word: "abandn", n: 1, want: []string{"abandon"}},
+		{name: "swapped letters", word: "abuot", n: 2, want: []string{"about", "abuse"}},
+		{name: "exact word first", word: "zoo", n: 1, want: []string{"zoo"}},
+		{name: "too far", word: "qqqqqqqq", n: 3, want: []string{}},
+		{name: "none", word: "abandn", n: 0, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SuggestWords(tt.word, English, tt.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestWords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := SuggestWords("abandn", English, -1); !errors.Is(err, ErrNegativeSuggestionCount) {
+		t.Errorf("SuggestWords() with n = -1 error = %v, want %v", err, ErrNegativeSuggestionCount)
+	}
+}
+
+func TestCompleteMnemonic(t *testing.T) {
+	tests := []struct {
+		name      string
+		words     int
+		wantCount int
+		want      string
+	}{
+		{name: "12 words", words: 11, wantCount: 128, want: "about"},
+		{name: "15 words", words: 14, wantCount: 64},
+		{name: "18 words", words: 17, wantCount: 32, want: "agent"},
+		{name: "21 words", words: 20, wantCount: 16},
+		{name: "24 words", words: 23, wantCount: 8, want: "art"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix := strings.Repeat("abandon ", tt.words)
+			got, err := CompleteMnemonic(prefix, English)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tt.wantCount {
+				t.Errorf("CompleteMnemonic() returned %d words, want %d", len(got), tt.wantCount)
+			}
+			found := tt.want == ""
+			for _, word := range got {
+				if _, err := EntropyFromMnemonic(prefix+word, English); err != nil {
+					t.Errorf("EntropyFromMnemonic() with %q error = %v", word, err)
+				}
+				found = found || word == tt.want
+			}
+			if !found {
+				t.Errorf("CompleteMnemonic() = %v, want it to contain %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CompleteMnemonic(strings.Repeat("abandon ", 12), English); !errors.Is(err, ErrInvalidWordCount) {
+		t.Errorf("CompleteMnemonic() of 12 words error = %v, want %v", err, ErrInvalidWordCount)
+	}
+	var wordErr *UnknownWordError
+	if _, err := CompleteMnemonic(strings.Repeat("abandon ", 10)+"abandn", English); !errors.As(err, &wordErr) || wordErr.Index != 10 {
+		t.Errorf("CompleteMnemonic() error = %v, want an *UnknownWordError at index 10", err)
+	}
+}
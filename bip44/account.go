@@ -0,0 +1,95 @@
+package bip44
+
+import (
+	"fmt"
+
+	"github.com/dubuqingfeng/signer/bip32"
+)
+
+// Account is the extended key pair at m / purpose' / coin_type' / account'.
+type Account struct {
+	Path       Path
+	PrivateKey *bip32.PrivateKey
+	PublicKey  *bip32.PublicKey
+}
+
+// DeriveAccount derives the account keys from a bip39 seed in one call. The extended keys
+// use the SLIP-132 version bytes of the purpose on the network, e.g. zpub for BIP-84.
+// Coins of another curve than secp256k1, such as Solana, are rejected.
+func DeriveAccount(seed []byte, network Network, purpose Purpose, account uint32) (*Account, error) {
+	if curve := network.Coin.Defaults().Curve; curve != Secp256k1 {
+		return nil, fmt.Errorf("%w: coin type %d uses %s", ErrUnsupportedCurve, network.Coin, curve)
+	}
+	path := NewAccountPath(purpose, network.Coin, account)
+	accountPath, err := path.AccountPath()
+	if err != nil {
+		return nil, err
+	}
+	version, err := network.KeyVersion(purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := bip32.NewMasterKeyWithVersion(seed, version)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := master.DeriveWithDerivationPath(accountPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Account{
+		Path:       path,
+		PrivateKey: privateKey,
+		PublicKey:  privateKey.ToPublicKey(),
+	}, nil
+}
+
+// AddressKey derives the private key of the address at index on the change chain.
+func (a *Account) AddressKey(change Change, index uint32) (*bip32.PrivateKey, error) {
+	if err := a.Path.Address(change, index).Validate(); err != nil {
+		return nil, err
+	}
+	return a.PrivateKey.DeriveWithDerivationPath(bip32.DerivationPath{Indexes: []uint32{uint32(change), index}})
+}
+
+// AddressPublicKey derives the public key of the address at index on the change chain
+// from the account public key only, as a watch-only wallet would.
+func (a *Account) AddressPublicKey(change Change, index uint32) (*bip32.PublicKey, error) {
+	if err := a.Path.Address(change, index).Validate(); err != nil {
+		return nil, err
+	}
+	return a.PublicKey.DeriveWithDerivationPath(bip32.DerivationPath{Indexes: []uint32{uint32(change), index}})
+}
+
+// MaxAddressKeys is the most keys AddressKeys derives in one call.
+const MaxAddressKeys = 1024
+
+// AddressKeys derives count address private keys from index start on the change chain,
+// at most MaxAddressKeys of them.
+func (a *Account) AddressKeys(change Change, start, count uint32) ([]*bip32.PrivateKey, error) {
+	if err := a.Path.Address(change, start).Validate(); err != nil {
+		return nil, err
+	}
+	if count > MaxAddressKeys {
+		return nil, fmt.Errorf("%w: got %d", ErrTooManyKeys, count)
+	}
+	if uint64(start)+uint64(count) > bip32.HardenedKeyZeroIndex {
+		return nil, ErrInvalidIndex
+	}
+	chain, err := a.PrivateKey.Derive(uint32(change))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]*bip32.PrivateKey, 0, count)
+	for i := uint32(0); i < count; i++ {
+		key, err := chain.Derive(start + i)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,132 @@
+package bip44
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// seed of the mnemonic "abandon abandon ... about" used by the BIP-49/84/86 test vectors
+const testSeed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+func TestDeriveAccount(t *testing.T) {
+	seed, _ := hex.DecodeString(testSeed)
+	tests := []struct {
+		name        string
+		network     Network
+		purpose     Purpose
+		accountXpub string
+		receive     []string
+		change      string
+	}{
+		{
+			name:        "bip44",
+			network:     Network{Coin: BitcoinCoinType},
+			purpose:     BIP44Purpose,
+			accountXpub: "xpub6BosfCnifzxcFwrSzQiqu2DBVTshkCXacvNsWGYJVVhhawA7d4R5WSWGFNbi8Aw6ZRc1brxMyWMzG3DSSSSoekkudhUd9yLb6qx39T9nMdj",
+			receive:     []string{"03aaeb52dd7494c361049de67cc680e83ebcbbbdbeb13637d92cd845f70308af5e"},
+		},
+		{
+			name:        "bip84",
+			network:     Network{SegwitEnabled: true, Coin: BitcoinCoinType},
+			purpose:     BIP84Purpose,
+			accountXpub: "zpub6rFR7y4Q2AijBEqTUquhVz398htDFrtymD9xYYfG1m4wAcvPhXNfE3EfH1r1ADqtfSdVCToUG868RvUUkgDKf31mGDtKsAYz2oz2AGutZYs",
+			receive: []string{
+				"0330d54fd0dd420a6e5f8d3624f5f3482cae350f79d5f0753bf5beef9c2d91af3c",
+				"03e775fd51f0dfb8cd865d9ff1cca2a158cf651fe997fdc9fee9c1d3b5e995ea77",
+			},
+			change: "03025324888e429ab8e3dbaf1f7802648b9cd01e9b418485c5fa4c1b9b5700e1a6",
+		},
+		{
+			name:        "bip86",
+			network:     Network{SegwitEnabled: true, Coin: BitcoinCoinType},
+			purpose:     BIP86Purpose,
+			accountXpub: "xpub6BgBgsespWvERF3LHQu6CnqdvfEvtMcQjYrcRzx53QJjSxarj2afYWcLteoGVky7D3UKDP9QyrLprQ3VCECoY49yfdDEHGCtMMj92pReUsQ",
+			receive:     []string{"03cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"},
+		},
+		{
+			name:    "bip49 testnet",
+			network: Network{SegwitEnabled: true, Coin: TestnetCoinType},
+			purpose: BIP49Purpose,
+			receive: []string{"03a1af804ac108a8a51782198c2d034b28bf90c8803f5a53f76276fa69a4eae77f"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account, err := DeriveAccount(seed, tt.network, tt.purpose, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.accountXpub != "" && account.PublicKey.String() != tt.accountXpub {
+				t.Errorf("PublicKey = %s, want %s", account.PublicKey, tt.accountXpub)
+			}
+
+			keys, err := account.AddressKeys(ExternalChain, 0, uint32(len(tt.receive)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, key := range keys {
+				if got := hex.EncodeToString(key.ToPublicKeyBytes()); got != tt.receive[i] {
+					t.Errorf("receive %d = %s, want %s", i, got, tt.receive[i])
+				}
+				publicKey, err := account.AddressPublicKey(ExternalChain, uint32(i))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := publicKey.String(); got != key.ToPublicKey().String() {
+					t.Errorf("AddressPublicKey(%d) = %s, want %s", i, got, key.ToPublicKey())
+				}
+			}
+
+			if tt.change != "" {
+				key, err := account.AddressKey(InternalChain, 0)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := hex.EncodeToString(key.ToPublicKeyBytes()); got != tt.change {
+					t.Errorf("change = %s, want %s", got, tt.change)
+				}
+			}
+		})
+	}
+}
+
+func TestDeriveAccountSegwitDisabled(t *testing.T) {
+	seed, _ := hex.DecodeString(testSeed)
+	for _, purpose := range []Purpose{BIP49Purpose, BIP84Purpose, BIP86Purpose} {
+		if _, err := DeriveAccount(seed, Network{Coin: BitcoinCoinType}, purpose, 0); err != ErrSegwitDisabled {
+			t.Errorf("DeriveAccount() with purpose %v error = %v, want %v", purpose, err, ErrSegwitDisabled)
+		}
+	}
+	if got := (Network{SegwitEnabled: true}).DefaultPurpose(); got != BIP84Purpose {
+		t.Errorf("DefaultPurpose() = %v, want %v", got, BIP84Purpose)
+	}
+}
+
+func TestDeriveAccountEd25519(t *testing.T) {
+	seed, _ := hex.DecodeString(testSeed)
+	if _, err := DeriveAccount(seed, Network{Coin: SolanaCoinType}, BIP44Purpose, 0); !errors.Is(err, ErrUnsupportedCurve) {
+		t.Errorf("DeriveAccount() of SOL error = %v, want %v", err, ErrUnsupportedCurve)
+	}
+}
+
+func TestAddressKeysLimit(t *testing.T) {
+	seed, _ := hex.DecodeString(testSeed)
+	account, err := DeriveAccount(seed, Network{Coin: BitcoinCoinType}, BIP44Purpose, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := account.AddressKeys(ExternalChain, 0, 1<<31-1); !errors.Is(err, ErrTooManyKeys) {
+		t.Errorf("AddressKeys() of 2^31-1 keys error = %v, want %v", err, ErrTooManyKeys)
+	}
+	if _, err := account.AddressKeys(ExternalChain, 1<<31-1, 2); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("AddressKeys() past 2^31 error = %v, want %v", err, ErrInvalidIndex)
+	}
+	keys, err := account.AddressKeys(ExternalChain, 1<<31-2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Errorf("AddressKeys() returned %d keys, want 2", len(keys))
+	}
+}
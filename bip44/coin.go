@@ -0,0 +1,122 @@
+package bip44
+
+import (
+	"errors"
+	"strings"
+)
+
+//go:generate go run gen_slip44.go -input testdata/slip-0044.md
+
+// Curve is the elliptic curve a coin signs with.
+type Curve string
+
+const (
+	Secp256k1 Curve = "secp256k1"
+	Ed25519   Curve = "ed25519"
+)
+
+// AddressFormat is the address encoding a coin uses by default.
+type AddressFormat string
+
+const (
+	AddressP2PKH    AddressFormat = "p2pkh"
+	AddressP2WPKH   AddressFormat = "p2wpkh"
+	AddressEthereum AddressFormat = "ethereum"
+	AddressTron     AddressFormat = "tron"
+	AddressBech32   AddressFormat = "bech32"
+	AddressCKB      AddressFormat = "ckb"
+	AddressBase58   AddressFormat = "base58"
+)
+
+var (
+	ErrUnknownCoin = errors.New("unknown SLIP-44 coin")
+)
+
+// Coin is a SLIP-44 registered coin type.
+type Coin struct {
+	Type   CoinType
+	Symbol string
+	Name   string
+}
+
+// CoinDefaults are the per-coin defaults used to build paths and addresses.
+type CoinDefaults struct {
+	Curve         Curve
+	Purpose       Purpose
+	AddressFormat AddressFormat
+}
+
+// coinDefaults are the defaults of the coins we sign for, see RegisterCoinDefaults.
+var coinDefaults = map[CoinType]CoinDefaults{
+	BitcoinCoinType:  {Secp256k1, BIP84Purpose, AddressP2WPKH},
+	TestnetCoinType:  {Secp256k1, BIP84Purpose, AddressP2WPKH},
+	LitecoinCoinType: {Secp256k1, BIP84Purpose, AddressP2WPKH},
+	DogecoinCoinType: {Secp256k1, BIP44Purpose, AddressP2PKH},
+	EthereumCoinType: {Secp256k1, BIP44Purpose, AddressEthereum},
+	CosmosCoinType:   {Secp256k1, BIP44Purpose, AddressBech32},
+	TronCoinType:     {Secp256k1, BIP44Purpose, AddressTron},
+	NervosCoinType:   {Secp256k1, BIP44Purpose, AddressCKB},
+	SolanaCoinType:   {Ed25519, BIP44Purpose, AddressBase58},
+}
+
+var (
+	coinsByType   map[CoinType]Coin
+	coinsBySymbol map[string]Coin
+)
+
+func init() {
+	coinsByType = make(map[CoinType]Coin, len(slip44Coins))
+	coinsBySymbol = make(map[string]Coin, len(slip44Coins))
+	for _, coin := range slip44Coins {
+		coinsByType[coin.Type] = coin
+		// several coins share a symbol, the lowest registered index wins
+		if _, ok := coinsBySymbol[coin.Symbol]; coin.Symbol != "" && !ok {
+			coinsBySymbol[coin.Symbol] = coin
+		}
+	}
+}
+
+// CoinByType returns the registered coin of the coin type.
+func CoinByType(coinType CoinType) (Coin, error) {
+	coin, ok := coinsByType[coinType]
+	if !ok {
+		return Coin{}, ErrUnknownCoin
+	}
+	return coin, nil
+}
+
+// CoinBySymbol returns the registered coin of the symbol, case insensitive.
+func CoinBySymbol(symbol string) (Coin, error) {
+	coin, ok := coinsBySymbol[strings.ToUpper(strings.TrimSpace(symbol))]
+	if !ok {
+		return Coin{}, ErrUnknownCoin
+	}
+	return coin, nil
+}
+
+// RegisterCoinDefaults attaches defaults to a coin type, replacing the existing ones.
+// It is meant to be called from init functions.
+func RegisterCoinDefaults(coinType CoinType, defaults CoinDefaults) {
+	coinDefaults[coinType] = defaults
+}
+
+// Defaults returns the defaults attached to the coin type,
+// a secp256k1 BIP-44 coin is assumed when none are registered.
+func (c CoinType) Defaults() CoinDefaults {
+	if defaults, ok := coinDefaults[c]; ok {
+		return defaults
+	}
+	return CoinDefaults{Curve: Secp256k1, Purpose: BIP44Purpose}
+}
+
+// Defaults returns the defaults attached to the coin.
+func (c Coin) Defaults() CoinDefaults {
+	return c.Type.Defaults()
+}
+
+// NewCoinAccountPath returns the first external address path of the account,
+// using the default purpose of the coin. The path of an ed25519 coin is hardened
+// at every level, e.g. m/44'/501'/0'/0' for Solana.
+func NewCoinAccountPath(coinType CoinType, account uint32) Path {
+	return NewAccountPath(coinType.Defaults().Purpose, coinType, account)
+}
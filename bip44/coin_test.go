@@ -0,0 +1,117 @@
+package bip44
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCoinLookup(t *testing.T) {
+	tests := []struct {
+		symbol string
+		want   CoinType
+		name   string
+	}{
+		{"BTC", BitcoinCoinType, "Bitcoin"},
+		{"ltc", LitecoinCoinType, "Litecoin"},
+		{"DOGE", DogecoinCoinType, "Dogecoin"},
+		{"ETH", EthereumCoinType, "Ether"},
+		{"TRX", TronCoinType, "Tron"},
+		{"SOL", SolanaCoinType, "Solana"},
+		{"ATOM", CosmosCoinType, "Atom"},
+		{" CKB ", NervosCoinType, "Nervos CKB"},
+		{"AVAX", CoinType(9000), "Avalanche"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.symbol, func(t *testing.T) {
+			coin, err := CoinBySymbol(tt.symbol)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if coin.Type != tt.want || coin.Name != tt.name {
+				t.Errorf("CoinBySymbol() = %+v, want %d %s", coin, tt.want, tt.name)
+			}
+
+			byType, err := CoinByType(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if byType != coin {
+				t.Errorf("CoinByType() = %+v, want %+v", byType, coin)
+			}
+		})
+	}
+
+	if _, err := CoinBySymbol("NOPE"); err != ErrUnknownCoin {
+		t.Errorf("CoinBySymbol() error = %v, want %v", err, ErrUnknownCoin)
+	}
+	if _, err := CoinByType(CoinType(0x7fffffff)); err != ErrUnknownCoin {
+		t.Errorf("CoinByType() error = %v, want %v", err, ErrUnknownCoin)
+	}
+}
+
+func TestSlip44CoinsSorted(t *testing.T) {
+	for i := 1; i < len(slip44Coins); i++ {
+		if slip44Coins[i-1].Type >= slip44Coins[i].Type {
+			t.Errorf("coin %d listed after %d", slip44Coins[i].Type, slip44Coins[i-1].Type)
+		}
+	}
+}
+
+// TestSlip44CoinsGenerated checks that slip44.go is the output of
+// gen_slip44.go for the pinned registry, not edited by hand.
+func TestSlip44CoinsGenerated(t *testing.T) {
+	source, err := os.ReadFile("testdata/slip-0044.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []CoinType
+	for _, line := range strings.Split(string(source), "\n") {
+		columns := strings.Split(line, "|")
+		if len(columns) < 5 {
+			continue
+		}
+		if index, err := strconv.ParseUint(strings.TrimSpace(columns[1]), 10, 32); err == nil {
+			types = append(types, CoinType(index))
+		}
+	}
+	if len(types) != len(slip44Coins) {
+		t.Fatalf("slip44.go has %d coins, testdata/slip-0044.md %d; run go generate", len(slip44Coins), len(types))
+	}
+	for i, coinType := range types {
+		if slip44Coins[i].Type != coinType {
+			t.Errorf("coin %d is %d, want %d; run go generate", i, slip44Coins[i].Type, coinType)
+		}
+	}
+}
+
+func TestCoinDefaults(t *testing.T) {
+	if got := NewCoinAccountPath(BitcoinCoinType, 0).String(); got != "m/84'/0'/0'/0/0" {
+		t.Errorf("NewCoinAccountPath(BTC) = %s", got)
+	}
+	if got := NewCoinAccountPath(EthereumCoinType, 0).String(); got != "m/44'/60'/0'/0/0" {
+		t.Errorf("NewCoinAccountPath(ETH) = %s", got)
+	}
+	if got := SolanaCoinType.Defaults().Curve; got != Ed25519 {
+		t.Errorf("SOL curve = %s, want %s", got, Ed25519)
+	}
+	if got := NewCoinAccountPath(SolanaCoinType, 0).String(); got != "m/44'/501'/0'/0'" {
+		t.Errorf("NewCoinAccountPath(SOL) = %s", got)
+	}
+
+	// unregistered coins fall back to a secp256k1 BIP-44 coin
+	dash, err := CoinBySymbol("DASH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dash.Defaults(); got.Curve != Secp256k1 || got.Purpose != BIP44Purpose {
+		t.Errorf("DASH defaults = %+v", got)
+	}
+
+	RegisterCoinDefaults(dash.Type, CoinDefaults{Curve: Secp256k1, Purpose: BIP44Purpose, AddressFormat: AddressP2PKH})
+	defer delete(coinDefaults, dash.Type)
+	if got := dash.Defaults().AddressFormat; got != AddressP2PKH {
+		t.Errorf("DASH address format = %s, want %s", got, AddressP2PKH)
+	}
+}
@@ -0,0 +1,108 @@
+//go:build ignore
+
+// gen_slip44.go generates slip44.go from the SLIP-44 registry.
+//
+//	go run gen_slip44.go [-input slip-0044.md] [-output slip44.go]
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const slip44URL = "https://raw.githubusercontent.com/satoshilabs/slips/master/slip-0044.md"
+
+var linkPattern = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+type entry struct {
+	index  uint32
+	symbol string
+	name   string
+}
+
+func main() {
+	input := flag.String("input", "", "local slip-0044.md, downloaded from "+slip44URL+" when empty")
+	output := flag.String("output", "slip44.go", "generated file")
+	flag.Parse()
+
+	source, err := readSource(*input)
+	if err != nil {
+		log.Fatal(err)
+	}
+	entries, err := parse(source)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by gen_slip44.go; DO NOT EDIT.\n\n")
+	buf.WriteString("package bip44\n\n")
+	buf.WriteString("// slip44Coins is the SLIP-44 registered coin types, " + slip44URL + "\n")
+	buf.WriteString("var slip44Coins = []Coin{\n")
+	for _, e := range entries {
+		fmt.Fprintf(&buf, "\t{Type: %d, Symbol: %q, Name: %q},\n", e.index, e.symbol, e.name)
+	}
+	buf.WriteString("}\n")
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readSource(input string) ([]byte, error) {
+	if input != "" {
+		return os.ReadFile(input)
+	}
+	resp, err := http.Get(slip44URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", slip44URL, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// parse reads the rows of the markdown table: | index | path component | symbol | coin |
+func parse(source []byte) ([]entry, error) {
+	var entries []entry
+	scanner := bufio.NewScanner(bytes.NewReader(source))
+	for scanner.Scan() {
+		columns := strings.Split(scanner.Text(), "|")
+		if len(columns) < 5 {
+			continue
+		}
+		index, err := strconv.ParseUint(strings.TrimSpace(columns[1]), 10, 32)
+		if err != nil {
+			// header, separator or a reserved range row
+			continue
+		}
+		pathComponent, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(columns[2]), "0x"), 16, 32)
+		if err != nil || pathComponent != index+0x80000000 {
+			return nil, fmt.Errorf("row %d: path component %q does not match", index, columns[2])
+		}
+
+		name := linkPattern.ReplaceAllString(strings.TrimSpace(columns[4]), "$1")
+		entries = append(entries, entry{
+			index:  uint32(index),
+			symbol: strings.TrimSpace(columns[3]),
+			name:   strings.TrimSpace(name),
+		})
+	}
+	return entries, scanner.Err()
+}
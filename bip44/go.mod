@@ -0,0 +1,12 @@
+module github.com/dubuqingfeng/signer/bip44
+
+go 1.18
+
+require github.com/dubuqingfeng/signer/bip32 v0.0.0
+
+require (
+	github.com/mndrix/btcutil v0.0.0-20130527213604-d3a63a5752ec // indirect
+	golang.org/x/crypto v0.9.0 // indirect
+)
+
+replace github.com/dubuqingfeng/signer/bip32 => ../bip32
@@ -1,16 +1,35 @@
 package bip44
 
+import (
+	"github.com/dubuqingfeng/signer/bip32"
+)
+
 type Purpose uint32
 
 const (
+	// BIP44Purpose is used by legacy P2PKH accounts
 	BIP44Purpose Purpose = 44
+	// BIP49Purpose is used by nested segwit P2WPKH-nested-in-P2SH accounts
+	BIP49Purpose Purpose = 49
+	// BIP84Purpose is used by native segwit P2WPKH accounts
+	BIP84Purpose Purpose = 84
+	// BIP86Purpose is used by taproot P2TR single key accounts
+	BIP86Purpose Purpose = 86
 )
 
 type CoinType uint32
 
+// SLIP-44 coin types of the coins we sign for, the full registry is in slip44.go
 const (
-	BitcoinCoinType CoinType = 0
-	TestnetCoinType CoinType = 1
+	BitcoinCoinType  CoinType = 0
+	TestnetCoinType  CoinType = 1
+	LitecoinCoinType CoinType = 2
+	DogecoinCoinType CoinType = 3
+	EthereumCoinType CoinType = 60
+	CosmosCoinType   CoinType = 118
+	TronCoinType     CoinType = 195
+	NervosCoinType   CoinType = 309
+	SolanaCoinType   CoinType = 501
 )
 
 const (
@@ -27,4 +46,61 @@ const (
 type Network struct {
 	SegwitEnabled bool
 	Coin          CoinType
+	// RegTest marks the regression test network, which shares the coin
+	// type and extended key versions of testnet but not its addresses.
+	RegTest bool
+}
+
+// IsValid reports whether the purpose is one of BIP-44, BIP-49, BIP-84 or BIP-86.
+func (p Purpose) IsValid() bool {
+	switch p {
+	case BIP44Purpose, BIP49Purpose, BIP84Purpose, BIP86Purpose:
+		return true
+	}
+	return false
+}
+
+// ScriptType returns the SLIP-132 script type of the extended keys of the purpose.
+// BIP-86 has no registered version bytes and uses the xpub/tpub ones.
+func (p Purpose) ScriptType() (bip32.ScriptType, error) {
+	switch p {
+	case BIP44Purpose, BIP86Purpose:
+		return bip32.ScriptP2PKH, nil
+	case BIP49Purpose:
+		return bip32.ScriptP2WPKHInP2SH, nil
+	case BIP84Purpose:
+		return bip32.ScriptP2WPKH, nil
+	}
+	return 0, ErrInvalidPurpose
+}
+
+// IsTestnet reports whether the network uses the testnet coin type.
+func (n Network) IsTestnet() bool {
+	return n.Coin == TestnetCoinType
+}
+
+// DefaultPurpose returns BIP-84 for segwit enabled networks, BIP-44 otherwise.
+func (n Network) DefaultPurpose() Purpose {
+	if n.SegwitEnabled {
+		return BIP84Purpose
+	}
+	return BIP44Purpose
+}
+
+// KeyVersion returns the extended key version bytes for the purpose on the network.
+func (n Network) KeyVersion(purpose Purpose) (bip32.KeyVersion, error) {
+	script, err := purpose.ScriptType()
+	if err != nil {
+		return bip32.KeyVersion{}, err
+	}
+	// BIP-86 taproot outputs are segwit v1
+	if (purpose == BIP49Purpose || purpose == BIP84Purpose || purpose == BIP86Purpose) && !n.SegwitEnabled {
+		return bip32.KeyVersion{}, ErrSegwitDisabled
+	}
+
+	network := bip32.Mainnet
+	if n.IsTestnet() {
+		network = bip32.Testnet
+	}
+	return bip32.VersionFor(network, script)
 }
@@ -0,0 +1,154 @@
+package bip44
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dubuqingfeng/signer/bip32"
+)
+
+// Change selects the external (receive) or internal (change) chain of an account.
+type Change uint32
+
+const (
+	ExternalChain Change = 0
+	InternalChain Change = 1
+)
+
+// Depth of the levels in m / purpose' / coin_type' / account' / change / address_index
+const (
+	PurposeDepth = iota + 1
+	CoinTypeDepth
+	AccountDepth
+	ChangeDepth
+	AddressIndexDepth
+)
+
+var (
+	ErrInvalidPurpose   = errors.New("purpose must be one of 44, 49, 84 or 86")
+	ErrInvalidChange    = errors.New("change must be 0 (external) or 1 (internal)")
+	ErrInvalidIndex     = errors.New("index must be below 2^31")
+	ErrInvalidLevels    = errors.New("path must be m / purpose' / coin_type' / account' / change / address_index")
+	ErrSegwitDisabled   = errors.New("segwit purpose on a network without segwit")
+	ErrNoAddressIndex   = errors.New("ed25519 paths have no address index level")
+	ErrUnsupportedCurve = errors.New("only secp256k1 coins derive with bip32")
+	ErrTooManyKeys      = errors.New("too many address keys in one call")
+)
+
+// Path is a BIP-44 style path: m / purpose' / coin_type' / account' / change / address_index.
+// The purpose, coin type and account levels are always hardened, change and address index never are.
+// SLIP-10 ed25519 derives hardened children only, so the path of an ed25519 coin is
+// m / purpose' / coin_type' / account' / change', as Solana wallets use, and its index is always 0.
+type Path struct {
+	Purpose Purpose
+	Coin    CoinType
+	Account uint32
+	Change  Change
+	Index   uint32
+}
+
+// NewAccountPath returns the path of the first external address of the account.
+func NewAccountPath(purpose Purpose, coin CoinType, account uint32) Path {
+	return Path{Purpose: purpose, Coin: coin, Account: account}
+}
+
+// Address returns the path of the address at index on the change chain of the same account.
+func (p Path) Address(change Change, index uint32) Path {
+	p.Change = change
+	p.Index = index
+	return p
+}
+
+// Validate checks every level is in range before it gets hardened.
+func (p Path) Validate() error {
+	if !p.Purpose.IsValid() {
+		return ErrInvalidPurpose
+	}
+	if p.Change != ExternalChain && p.Change != InternalChain {
+		return ErrInvalidChange
+	}
+	if uint32(p.Coin) >= bip32.HardenedKeyZeroIndex || p.Account >= bip32.HardenedKeyZeroIndex || p.Index >= bip32.HardenedKeyZeroIndex {
+		return ErrInvalidIndex
+	}
+	if p.hardenedOnly() && p.Index != 0 {
+		return ErrNoAddressIndex
+	}
+	return nil
+}
+
+// hardenedOnly reports whether the coin derives with ed25519, whose paths are hardened at every level.
+func (p Path) hardenedOnly() bool {
+	return p.Coin.Defaults().Curve == Ed25519
+}
+
+// AccountPath returns the bip32 path m / purpose' / coin_type' / account'.
+func (p Path) AccountPath() (bip32.DerivationPath, error) {
+	if err := p.Validate(); err != nil {
+		return bip32.DerivationPath{}, err
+	}
+	return bip32.NewDerivationPath(
+		uint32(p.Purpose)+bip32.HardenedKeyZeroIndex,
+		uint32(p.Coin)+bip32.HardenedKeyZeroIndex,
+		p.Account+bip32.HardenedKeyZeroIndex,
+	), nil
+}
+
+// DerivationPath returns the full bip32 path m / purpose' / coin_type' / account' / change / address_index,
+// or m / purpose' / coin_type' / account' / change' for an ed25519 coin.
+func (p Path) DerivationPath() (bip32.DerivationPath, error) {
+	account, err := p.AccountPath()
+	if err != nil {
+		return bip32.DerivationPath{}, err
+	}
+	if p.hardenedOnly() {
+		return account.Child(uint32(p.Change) + bip32.HardenedKeyZeroIndex), nil
+	}
+	return account.Child(uint32(p.Change)).Child(p.Index), nil
+}
+
+// String formats the path, e.g. m/44'/0'/0'/0/0, or m/44'/501'/0'/0' for an ed25519 coin
+func (p Path) String() string {
+	if p.hardenedOnly() {
+		return fmt.Sprintf("m/%d'/%d'/%d'/%d'", p.Purpose, p.Coin, p.Account, p.Change)
+	}
+	return fmt.Sprintf("m/%d'/%d'/%d'/%d/%d", p.Purpose, p.Coin, p.Account, p.Change, p.Index)
+}
+
+// ParsePath parses a full five levels BIP-44 style path, or the four hardened levels of an
+// ed25519 coin, and enforces which levels are hardened.
+func ParsePath(path string) (Path, error) {
+	p, err := bip32.ParseDerivationPath(path)
+	if err != nil {
+		return Path{}, err
+	}
+	if !p.Absolute || len(p.Indexes) < ChangeDepth {
+		return Path{}, ErrInvalidLevels
+	}
+
+	result := Path{Coin: CoinType(p.Indexes[1] &^ bip32.HardenedKeyZeroIndex)}
+	depths := AddressIndexDepth
+	if result.hardenedOnly() {
+		depths = ChangeDepth
+	}
+	if len(p.Indexes) != depths {
+		return Path{}, ErrInvalidLevels
+	}
+	for depth, index := range p.Indexes {
+		hardened := index >= bip32.HardenedKeyZeroIndex
+		if hardened != (depth+1 <= AccountDepth || result.hardenedOnly()) {
+			return Path{}, ErrInvalidLevels
+		}
+		p.Indexes[depth] = index &^ bip32.HardenedKeyZeroIndex
+	}
+
+	result.Purpose = Purpose(p.Indexes[0])
+	result.Account = p.Indexes[2]
+	result.Change = Change(p.Indexes[3])
+	if depths == AddressIndexDepth {
+		result.Index = p.Indexes[4]
+	}
+	if err := result.Validate(); err != nil {
+		return Path{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package bip44
+
+import (
+	"testing"
+
+	"github.com/dubuqingfeng/signer/bip32"
+)
+
+func TestPathDerivationPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    Path
+		want    string
+		wantErr error
+	}{
+		{
+			name: "bip44 bitcoin",
+			path: NewAccountPath(BIP44Purpose, BitcoinCoinType, 0),
+			want: "m/44'/0'/0'/0/0",
+		},
+		{
+			name: "bip84 testnet change",
+			path: NewAccountPath(BIP84Purpose, TestnetCoinType, 2).Address(InternalChain, 7),
+			want: "m/84'/1'/2'/1/7",
+		},
+		{
+			name: "solana hardened",
+			path: NewCoinAccountPath(SolanaCoinType, 3),
+			want: "m/44'/501'/3'/0'",
+		},
+		{
+			name:    "solana address index",
+			path:    NewCoinAccountPath(SolanaCoinType, 0).Address(ExternalChain, 1),
+			wantErr: ErrNoAddressIndex,
+		},
+		{
+			name:    "unknown purpose",
+			path:    NewAccountPath(Purpose(45), BitcoinCoinType, 0),
+			wantErr: ErrInvalidPurpose,
+		},
+		{
+			name:    "invalid change",
+			path:    NewAccountPath(BIP86Purpose, BitcoinCoinType, 0).Address(Change(2), 0),
+			wantErr: ErrInvalidChange,
+		},
+		{
+			name:    "hardened account",
+			path:    NewAccountPath(BIP49Purpose, BitcoinCoinType, bip32.HardenedKeyZeroIndex),
+			wantErr: ErrInvalidIndex,
+		},
+		{
+			name:    "hardened index",
+			path:    NewAccountPath(BIP49Purpose, BitcoinCoinType, 0).Address(ExternalChain, bip32.HardenedKeyZeroIndex),
+			wantErr: ErrInvalidIndex,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.path.DerivationPath()
+			if err != tt.wantErr {
+				t.Fatalf("DerivationPath() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("DerivationPath() = %v, want %v", got, tt.want)
+			}
+			if tt.path.String() != tt.want {
+				t.Errorf("String() = %v, want %v", tt.path, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    Path
+		wantErr error
+	}{
+		{
+			name: "bip44",
+			path: "m/44'/0'/0'/0/0",
+			want: Path{Purpose: BIP44Purpose},
+		},
+		{
+			name: "bip86 h marker",
+			path: "m/86h/1h/3h/1/9",
+			want: Path{Purpose: BIP86Purpose, Coin: TestnetCoinType, Account: 3, Change: InternalChain, Index: 9},
+		},
+		{
+			name: "solana",
+			path: "m/44'/501'/2'/0'",
+			want: Path{Purpose: BIP44Purpose, Coin: SolanaCoinType, Account: 2},
+		},
+		{name: "account level only", path: "m/44'/0'/0'", wantErr: ErrInvalidLevels},
+		{name: "solana address index", path: "m/44'/501'/0'/0'/0'", wantErr: ErrInvalidLevels},
+		{name: "solana non-hardened change", path: "m/44'/501'/0'/0/0", wantErr: ErrInvalidLevels},
+		{name: "relative", path: "44'/0'/0'/0/0", wantErr: ErrInvalidLevels},
+		{name: "non-hardened coin", path: "m/44'/0/0'/0/0", wantErr: ErrInvalidLevels},
+		{name: "hardened change", path: "m/44'/0'/0'/0'/0", wantErr: ErrInvalidLevels},
+		{name: "unknown purpose", path: "m/0'/0'/0'/0/0", wantErr: ErrInvalidPurpose},
+		{name: "invalid change", path: "m/44'/0'/0'/2/0", wantErr: ErrInvalidChange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePath(tt.path)
+			if err != tt.wantErr {
+				t.Fatalf("ParsePath() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePath() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
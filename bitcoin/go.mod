@@ -0,0 +1,12 @@
+module github.com/dubuqingfeng/signer/bitcoin
+
+go 1.18
+
+require github.com/dubuqingfeng/signer/secp256k1-go v0.0.0
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+)
+
+replace github.com/dubuqingfeng/signer/secp256k1-go => ../secp256k1-go
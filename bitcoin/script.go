@@ -0,0 +1,61 @@
+package bitcoin
+
+const (
+	OpPushData1     = 0x4c
+	OpPushData2     = 0x4d
+	OpPushData4     = 0x4e
+	OpDup           = 0x76
+	OpEqualVerify   = 0x88
+	OpHash160       = 0xa9
+	OpCodeSeparator = 0xab
+	OpCheckSig      = 0xac
+)
+
+// P2PKHScript returns the pay to public key hash script, also the BIP-143 script code of P2WPKH inputs.
+func P2PKHScript(pubKeyHash []byte) []byte {
+	script := []byte{OpDup, OpHash160, byte(len(pubKeyHash))}
+	script = append(script, pubKeyHash...)
+	return append(script, OpEqualVerify, OpCheckSig)
+}
+
+// removeOpcode returns the script without the opcode, pushed data is kept as is.
+// A truncated push ends the parsing and the rest of the script is kept.
+func removeOpcode(script []byte, opcode byte) []byte {
+	result := make([]byte, 0, len(script))
+	for i := 0; i < len(script); {
+		size := opSize(script[i:])
+		if size <= 0 || size > len(script)-i {
+			return append(result, script[i:]...)
+		}
+		if script[i] != opcode {
+			result = append(result, script[i:i+size]...)
+		}
+		i += size
+	}
+	return result
+}
+
+// opSize returns the length of the opcode and its pushed data, or -1 when the push length is truncated.
+func opSize(script []byte) int {
+	op := script[0]
+	switch {
+	case op > 0 && op < OpPushData1:
+		return 1 + int(op)
+	case op == OpPushData1:
+		if len(script) < 2 {
+			return -1
+		}
+		return 2 + int(script[1])
+	case op == OpPushData2:
+		if len(script) < 3 {
+			return -1
+		}
+		return 3 + (int(script[1]) | int(script[2])<<8)
+	case op == OpPushData4:
+		if len(script) < 5 {
+			return -1
+		}
+		return 5 + (int(script[1]) | int(script[2])<<8 | int(script[3])<<16 | int(script[4])<<24)
+	}
+	return 1
+}
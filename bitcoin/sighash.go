@@ -0,0 +1,249 @@
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// SigHashType selects the parts of the transaction a signature commits to.
+type SigHashType uint32
+
+const (
+	// SigHashDefault is the taproot only default, committing like SigHashAll without an explicit type byte.
+	SigHashDefault      SigHashType = 0x00
+	SigHashAll          SigHashType = 0x01
+	SigHashNone         SigHashType = 0x02
+	SigHashSingle       SigHashType = 0x03
+	SigHashAnyoneCanPay SigHashType = 0x80
+
+	sigHashMask = 0x1f
+)
+
+// NoCodeSeparator is the taproot script path code separator position when none was executed.
+const NoCodeSeparator uint32 = 0xffffffff
+
+var (
+	ErrInputIndex          = errors.New("input index out of range")
+	ErrSigHashSingleOutput = errors.New("SIGHASH_SINGLE without a matching output")
+	ErrInvalidSigHashType  = errors.New("invalid taproot sighash type")
+	ErrPrevOutputs         = errors.New("previous outputs must match the inputs")
+	ErrInvalidAnnex        = errors.New("annex must start with 0x50")
+)
+
+func (t SigHashType) base() SigHashType {
+	return t & sigHashMask
+}
+
+func (t SigHashType) anyoneCanPay() bool {
+	return t&SigHashAnyoneCanPay != 0
+}
+
+// LegacySigHash returns the pre-segwit signature hash of the input with the sub script,
+// OP_CODESEPARATORs are removed from it. SIGHASH_SINGLE without a matching output signs
+// the hash 1 as the original client does.
+func (tx *Transaction) LegacySigHash(idx int, subScript []byte, hashType SigHashType) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, ErrInputIndex
+	}
+	if hashType.base() == SigHashSingle && idx >= len(tx.TxOut) {
+		one := make([]byte, 32)
+		one[0] = 0x01
+		return one, nil
+	}
+
+	c := tx.Copy()
+	for i, in := range c.TxIn {
+		in.Witness = nil
+		if i == idx {
+			in.SignatureScript = removeOpcode(subScript, OpCodeSeparator)
+		} else {
+			in.SignatureScript = nil
+		}
+	}
+
+	switch hashType.base() {
+	case SigHashNone:
+		c.TxOut = nil
+		for i, in := range c.TxIn {
+			if i != idx {
+				in.Sequence = 0
+			}
+		}
+	case SigHashSingle:
+		c.TxOut = c.TxOut[:idx+1]
+		for _, out := range c.TxOut[:idx] {
+			out.Value = -1
+			out.PkScript = nil
+		}
+		for i, in := range c.TxIn {
+			if i != idx {
+				in.Sequence = 0
+			}
+		}
+	}
+	if hashType.anyoneCanPay() {
+		c.TxIn = c.TxIn[idx : idx+1]
+	}
+
+	preimage := AppendUint32(c.SerializeNoWitness(), uint32(hashType))
+	hash := doubleSha256(preimage)
+	return hash[:], nil
+}
+
+// WitnessV0SigHash returns the BIP-143 signature hash of a segwit v0 input spending amount,
+// scriptCode is P2PKHScript(hash160(pubkey)) for P2WPKH and the witness script for P2WSH.
+func (tx *Transaction) WitnessV0SigHash(idx int, scriptCode []byte, amount int64, hashType SigHashType) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, ErrInputIndex
+	}
+
+	var hashPrevouts, hashSequence, hashOutputs Hash
+	if !hashType.anyoneCanPay() {
+		hashPrevouts = doubleSha256(tx.prevoutsData())
+		if hashType.base() != SigHashSingle && hashType.base() != SigHashNone {
+			hashSequence = doubleSha256(tx.sequencesData())
+		}
+	}
+	switch {
+	case hashType.base() != SigHashSingle && hashType.base() != SigHashNone:
+		hashOutputs = doubleSha256(tx.outputsData())
+	case hashType.base() == SigHashSingle && idx < len(tx.TxOut):
+		hashOutputs = doubleSha256(appendTxOut(nil, tx.TxOut[idx]))
+	}
+
+	in := tx.TxIn[idx]
+	preimage := AppendUint32(nil, uint32(tx.Version))
+	preimage = append(preimage, hashPrevouts[:]...)
+	preimage = append(preimage, hashSequence[:]...)
+	preimage = appendOutPoint(preimage, in.PreviousOutPoint)
+	preimage = AppendVarBytes(preimage, scriptCode)
+	preimage = AppendUint64(preimage, uint64(amount))
+	preimage = AppendUint32(preimage, in.Sequence)
+	preimage = append(preimage, hashOutputs[:]...)
+	preimage = AppendUint32(preimage, tx.LockTime)
+	preimage = AppendUint32(preimage, uint32(hashType))
+
+	hash := doubleSha256(preimage)
+	return hash[:], nil
+}
+
+// TaprootSigHashOptions are the optional parts of the BIP-341 signature message.
+type TaprootSigHashOptions struct {
+	// Annex is the last witness element starting with 0x50, if any.
+	Annex []byte
+	// LeafHash is the BIP-342 tapleaf hash of a script path spend, nil for key path spends.
+	LeafHash []byte
+	// CodeSepPos is the opcode position of the last executed OP_CODESEPARATOR of a script path
+	// spend, NoCodeSeparator when none was executed.
+	CodeSepPos uint32
+}
+
+// TaprootSigHash returns the BIP-341 signature hash of the input, prevOuts are the outputs
+// spent by every input of the transaction.
+func (tx *Transaction) TaprootSigHash(idx int, prevOuts []*TxOut, hashType SigHashType, opts *TaprootSigHashOptions) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, ErrInputIndex
+	}
+	if len(prevOuts) != len(tx.TxIn) {
+		return nil, ErrPrevOutputs
+	}
+	switch hashType {
+	case SigHashDefault, SigHashAll, SigHashNone, SigHashSingle,
+		SigHashAll | SigHashAnyoneCanPay, SigHashNone | SigHashAnyoneCanPay, SigHashSingle | SigHashAnyoneCanPay:
+	default:
+		return nil, ErrInvalidSigHashType
+	}
+	if opts == nil {
+		opts = &TaprootSigHashOptions{}
+	}
+	if opts.Annex != nil && (len(opts.Annex) == 0 || opts.Annex[0] != 0x50) {
+		return nil, ErrInvalidAnnex
+	}
+
+	// epoch 0
+	msg := []byte{0x00, byte(hashType)}
+	msg = AppendUint32(msg, uint32(tx.Version))
+	msg = AppendUint32(msg, tx.LockTime)
+
+	if !hashType.anyoneCanPay() {
+		var amounts, scripts []byte
+		for _, out := range prevOuts {
+			amounts = AppendUint64(amounts, uint64(out.Value))
+			scripts = AppendVarBytes(scripts, out.PkScript)
+		}
+		for _, data := range [][]byte{tx.prevoutsData(), amounts, scripts, tx.sequencesData()} {
+			hash := sha256.Sum256(data)
+			msg = append(msg, hash[:]...)
+		}
+	}
+	if hashType.base() != SigHashNone && hashType.base() != SigHashSingle {
+		hash := sha256.Sum256(tx.outputsData())
+		msg = append(msg, hash[:]...)
+	}
+
+	var spendType byte
+	if opts.LeafHash != nil {
+		spendType |= 0x2
+	}
+	if opts.Annex != nil {
+		spendType |= 0x1
+	}
+	msg = append(msg, spendType)
+
+	if hashType.anyoneCanPay() {
+		in := tx.TxIn[idx]
+		msg = appendOutPoint(msg, in.PreviousOutPoint)
+		msg = AppendUint64(msg, uint64(prevOuts[idx].Value))
+		msg = AppendVarBytes(msg, prevOuts[idx].PkScript)
+		msg = AppendUint32(msg, in.Sequence)
+	} else {
+		msg = AppendUint32(msg, uint32(idx))
+	}
+	if opts.Annex != nil {
+		hash := sha256.Sum256(AppendVarBytes(nil, opts.Annex))
+		msg = append(msg, hash[:]...)
+	}
+	if hashType.base() == SigHashSingle {
+		if idx >= len(tx.TxOut) {
+			return nil, ErrSigHashSingleOutput
+		}
+		hash := sha256.Sum256(appendTxOut(nil, tx.TxOut[idx]))
+		msg = append(msg, hash[:]...)
+	}
+	if opts.LeafHash != nil {
+		if len(opts.LeafHash) != 32 {
+			return nil, ErrInvalidHashLength
+		}
+		// key_version 0
+		msg = append(msg, opts.LeafHash...)
+		msg = append(msg, 0x00)
+		msg = AppendUint32(msg, opts.CodeSepPos)
+	}
+	return secp256k1.TaggedHash("TapSighash", msg), nil
+}
+
+func (tx *Transaction) prevoutsData() []byte {
+	var b []byte
+	for _, in := range tx.TxIn {
+		b = appendOutPoint(b, in.PreviousOutPoint)
+	}
+	return b
+}
+
+func (tx *Transaction) sequencesData() []byte {
+	var b []byte
+	for _, in := range tx.TxIn {
+		b = AppendUint32(b, in.Sequence)
+	}
+	return b
+}
+
+func (tx *Transaction) outputsData() []byte {
+	var b []byte
+	for _, out := range tx.TxOut {
+		b = appendTxOut(b, out)
+	}
+	return b
+}
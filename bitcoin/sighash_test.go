@@ -0,0 +1,204 @@
+package bitcoin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// testdata/sighash.json are the legacy signature hash vectors of bitcoin core
+func TestLegacySigHash(t *testing.T) {
+	file, err := os.ReadFile("testdata/sighash.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests [][]interface{}
+	if err := json.Unmarshal(file, &tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range tests[1:] {
+		tx, err := DeserializeTransaction(mustDecodeHex(t, test[0].(string)))
+		if err != nil {
+			t.Errorf("#%d: DeserializeTransaction() error = %v", i, err)
+			continue
+		}
+		subScript := mustDecodeHex(t, test[1].(string))
+		idx := int(test[2].(float64))
+		hashType := SigHashType(uint32(int32(test[3].(float64))))
+		want, _ := NewHashFromStr(test[4].(string))
+
+		hash, err := tx.LegacySigHash(idx, subScript, hashType)
+		if err != nil {
+			t.Errorf("#%d: LegacySigHash() error = %v", i, err)
+			continue
+		}
+		if !bytes.Equal(hash, want[:]) {
+			t.Errorf("#%d: LegacySigHash() = %x, want %x", i, hash, want)
+		}
+	}
+}
+
+// BIP-143 examples
+func TestWitnessV0SigHash(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	tests := []struct {
+		name       string
+		unsigned   string
+		idx        int
+		scriptCode string
+		amount     int64
+		sigHash    string
+		privateKey string
+		publicKey  string
+	}{
+		{
+			name:       "native P2WPKH",
+			unsigned:   "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1b309fe287d9b2b55d57b90ec68a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000",
+			idx:        1,
+			scriptCode: "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac",
+			amount:     600000000,
+			sigHash:    "c37af31116d1b27caf68aae9e3ac82f1477929014d5b917657d0eb49478cb670",
+			privateKey: "619c335025c7f4012e556c2a58b2506e30b8511b53ade95ea316fd8c3286feb9",
+			publicKey:  "025476c2e83188368da1ff3e292e7acafcdb3566bb0ad253f62fc70f07aeee6357",
+		},
+		{
+			name:       "P2SH-P2WPKH",
+			unsigned:   "0100000001db6b1b20aa0fd7b23880be2ecbd4a98130974cf4748fb66092ac4d3ceb1a54770100000000feffffff02b8b4eb0b000000001976a914a457b684d7f0d539a46a45bbc043f35b59d0d96388ac0008af2f000000001976a914fd270b1ee6abcaea97fea7ad0402e8bd8ad6d77c88ac92040000",
+			idx:        0,
+			scriptCode: "76a91479091972186c449eb1ded22b78e40d009bdf008988ac",
+			amount:     1000000000,
+			sigHash:    "64f3b0f4dd2bb3aa1ce8566d220cc74dda9df97d8490cc81d89d735c92e59fb6",
+			privateKey: "eb696a065ef48a2192da5b28b694f87544b30fae8327c4510137a922f32c6dcf",
+			publicKey:  "03ad1d8e89212f0b92c74d23bb710c00662ad1470198ac48c43f7d6f93a2a26873",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := DeserializeTransaction(mustDecodeHex(t, tt.unsigned))
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := tx.WitnessV0SigHash(tt.idx, mustDecodeHex(t, tt.scriptCode), tt.amount, SigHashAll)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(hash) != tt.sigHash {
+				t.Errorf("WitnessV0SigHash() = %x, want %s", hash, tt.sigHash)
+			}
+
+			sig, err := tx.SignWitnessV0Input(ctx, tt.idx, mustDecodeHex(t, tt.scriptCode), tt.amount, SigHashAll, mustDecodeHex(t, tt.privateKey))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sig[len(sig)-1] != byte(SigHashAll) {
+				t.Errorf("sighash type byte = %x", sig[len(sig)-1])
+			}
+			if !VerifyECDSA(ctx, hash, sig[:len(sig)-1], mustDecodeHex(t, tt.publicKey)) {
+				t.Errorf("VerifyECDSA() = false")
+			}
+		})
+	}
+}
+
+// taprootTestTx spends a P2TR, a P2WPKH and a P2TR output.
+func taprootTestTx() (*Transaction, []*TxOut) {
+	tx := NewTransaction(2)
+	tx.LockTime = 500000
+	for _, in := range []struct {
+		tag      string
+		index    uint32
+		sequence uint32
+	}{{"in0", 1, 0xfffffffd}, {"in1", 0, 0xffffffff}, {"in2", 7, 0}} {
+		tx.AddTxIn(OutPoint{Hash: sha256.Sum256([]byte(in.tag)), Index: in.index}).Sequence = in.sequence
+	}
+	tx.AddTxOut(50000, append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x11}, 20)...))
+	tx.AddTxOut(123456789, append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0x22}, 32)...))
+
+	prevOuts := []*TxOut{
+		{Value: 100000, PkScript: append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0x33}, 32)...)},
+		{Value: 200000, PkScript: append([]byte{0x00, 0x14}, bytes.Repeat([]byte{0x44}, 20)...)},
+		{Value: 300000, PkScript: append([]byte{0x51, 0x20}, bytes.Repeat([]byte{0x55}, 32)...)},
+	}
+	return tx, prevOuts
+}
+
+// The expected hashes were cross checked with an independent implementation of the BIP-341 signature message.
+func TestTaprootSigHash(t *testing.T) {
+	tx, prevOuts := taprootTestTx()
+	leafHash := sha256.Sum256([]byte("leaf"))
+
+	tests := []struct {
+		idx      int
+		hashType SigHashType
+		opts     *TaprootSigHashOptions
+		want     string
+	}{
+		{0, SigHashDefault, nil, "138143392666e46afa1a6f887a7e14db1fc00bda17231ad5ac85ad33a431238d"},
+		{0, SigHashAll, nil, "47fa7674667b2dac21507c18b3301ce3a6d0e5e7061927baa67406de54d3bddf"},
+		{1, SigHashNone, nil, "fbd41eb4a603f4b0c6ae7206d62d02292ff6b50312197a4f2710e2b502bc95dc"},
+		{1, SigHashSingle, nil, "dd902dafc57031eec9f1b9e0ce09f3970be2d5ea3e8979dd64a74d663737e23f"},
+		{0, SigHashAll | SigHashAnyoneCanPay, nil, "cc048b3cf2363555cc4b1956b502f019d81d19dd0ad9ea0e040f9db7e52a020e"},
+		{1, SigHashNone | SigHashAnyoneCanPay, nil, "b3979af81408e12ec935c3d0f2a8d1b5a9a15bf5f6e2eaa83f57aa3bb761a3e3"},
+		{1, SigHashSingle | SigHashAnyoneCanPay, nil, "3ca8d7ee08105012c8ab2f2508b824efc363d93bb8b368c6e14d78add03ed4ca"},
+		{2, SigHashDefault, &TaprootSigHashOptions{Annex: []byte{0x50, 0xaa, 0xbb}}, "9c533b8a3cb188cf036249b2ade839f16873386cc69ad8ff2a33f3dfcf7c15b7"},
+		{0, SigHashAll, &TaprootSigHashOptions{LeafHash: leafHash[:], CodeSepPos: NoCodeSeparator}, "9687cb5b51d61fdb87354f11832b6043f64059478b1c17a657122c5b05928d44"},
+		{1, SigHashSingle | SigHashAnyoneCanPay, &TaprootSigHashOptions{Annex: []byte{0x50}, LeafHash: leafHash[:], CodeSepPos: 3}, "e3e4e0eaa2dd945a47a43c89b1ff47e56c0210a1f503a09615f2dd7e10826d0b"},
+	}
+	for _, tt := range tests {
+		hash, err := tx.TaprootSigHash(tt.idx, prevOuts, tt.hashType, tt.opts)
+		if err != nil {
+			t.Fatalf("TaprootSigHash(%d, %x) error = %v", tt.idx, tt.hashType, err)
+		}
+		if hex.EncodeToString(hash) != tt.want {
+			t.Errorf("TaprootSigHash(%d, %x) = %x, want %s", tt.idx, tt.hashType, hash, tt.want)
+		}
+	}
+
+	// ANYONECANPAY does not commit to the other inputs
+	acp, _ := tx.TaprootSigHash(0, prevOuts, SigHashAll|SigHashAnyoneCanPay, nil)
+	tx.TxIn[1].Sequence = 1
+	prevOuts[2].Value++
+	if changed, _ := tx.TaprootSigHash(0, prevOuts, SigHashAll|SigHashAnyoneCanPay, nil); !bytes.Equal(acp, changed) {
+		t.Errorf("SIGHASH_ANYONECANPAY committed to another input")
+	}
+
+	invalid := []struct {
+		idx      int
+		prevOuts []*TxOut
+		hashType SigHashType
+		opts     *TaprootSigHashOptions
+		err      error
+	}{
+		{3, prevOuts, SigHashAll, nil, ErrInputIndex},
+		{0, prevOuts[:2], SigHashAll, nil, ErrPrevOutputs},
+		{0, prevOuts, 0x04, nil, ErrInvalidSigHashType},
+		{0, prevOuts, SigHashAnyoneCanPay, nil, ErrInvalidSigHashType},
+		{2, prevOuts, SigHashSingle, nil, ErrSigHashSingleOutput},
+		{0, prevOuts, SigHashAll, &TaprootSigHashOptions{Annex: []byte{0x51}}, ErrInvalidAnnex},
+	}
+	for _, tt := range invalid {
+		if _, err := tx.TaprootSigHash(tt.idx, tt.prevOuts, tt.hashType, tt.opts); err != tt.err {
+			t.Errorf("TaprootSigHash(%d, %x) error = %v, want %v", tt.idx, tt.hashType, err, tt.err)
+		}
+	}
+}
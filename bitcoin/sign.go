@@ -0,0 +1,50 @@
+package bitcoin
+
+import (
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// SignECDSA signs the signature hash and returns the DER signature followed by the sighash type byte.
+func SignECDSA(ctx *secp256k1.Context, sigHash []byte, seckey []byte, hashType SigHashType) ([]byte, error) {
+	_, sig, err := secp256k1.EcdsaSign(ctx, sigHash, seckey)
+	if err != nil {
+		return nil, err
+	}
+	_, der, err := secp256k1.EcdsaSignatureSerializeDer(ctx, sig)
+	if err != nil {
+		return nil, err
+	}
+	return append(der, byte(hashType)), nil
+}
+
+// VerifyECDSA verifies a DER signature, without the sighash type byte, of the signature hash.
+func VerifyECDSA(ctx *secp256k1.Context, sigHash []byte, der []byte, pubKey []byte) bool {
+	_, sig, err := secp256k1.EcdsaSignatureParseDer(ctx, der)
+	if err != nil {
+		return false
+	}
+	_, pk, err := secp256k1.EcPubkeyParse(ctx, pubKey)
+	if err != nil {
+		return false
+	}
+	ok, err := secp256k1.EcdsaVerify(ctx, sig, sigHash, pk)
+	return err == nil && ok == 1
+}
+
+// SignLegacyInput signs a pre-segwit input, subScript is the previous output script or the redeem script.
+func (tx *Transaction) SignLegacyInput(ctx *secp256k1.Context, idx int, subScript []byte, hashType SigHashType, seckey []byte) ([]byte, error) {
+	sigHash, err := tx.LegacySigHash(idx, subScript, hashType)
+	if err != nil {
+		return nil, err
+	}
+	return SignECDSA(ctx, sigHash, seckey, hashType)
+}
+
+// SignWitnessV0Input signs a segwit v0 input spending amount.
+func (tx *Transaction) SignWitnessV0Input(ctx *secp256k1.Context, idx int, scriptCode []byte, amount int64, hashType SigHashType, seckey []byte) ([]byte, error) {
+	sigHash, err := tx.WitnessV0SigHash(idx, scriptCode, amount, hashType)
+	if err != nil {
+		return nil, err
+	}
+	return SignECDSA(ctx, sigHash, seckey, hashType)
+}
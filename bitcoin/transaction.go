@@ -0,0 +1,249 @@
+// Package bitcoin implements the bitcoin transaction wire format, signature hashes and signing.
+package bitcoin
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+const (
+	// MaxSequence is the final input sequence.
+	MaxSequence uint32 = 0xffffffff
+
+	witnessMarker = 0x00
+	witnessFlag   = 0x01
+
+	// minTxInSize is an outpoint, an empty script and a sequence, minTxOutSize a value and an empty script.
+	minTxInSize  = 41
+	minTxOutSize = 9
+)
+
+var ErrInvalidHashLength = errors.New("hash must be 32 bytes")
+
+// Hash is a double SHA-256 hash in internal byte order.
+type Hash [32]byte
+
+// NewHashFromStr decodes the reversed hex of a txid.
+func NewHashFromStr(s string) (Hash, error) {
+	var h Hash
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return h, err
+	}
+	if len(b) != len(h) {
+		return h, ErrInvalidHashLength
+	}
+	for i := range b {
+		h[i] = b[len(b)-1-i]
+	}
+	return h, nil
+}
+
+// String returns the reversed hex shown by block explorers.
+func (h Hash) String() string {
+	var reversed Hash
+	for i := range h {
+		reversed[i] = h[len(h)-1-i]
+	}
+	return hex.EncodeToString(reversed[:])
+}
+
+// OutPoint is the output spent by an input.
+type OutPoint struct {
+	Hash  Hash
+	Index uint32
+}
+
+// TxIn is a transaction input.
+type TxIn struct {
+	PreviousOutPoint OutPoint
+	SignatureScript  []byte
+	Witness          [][]byte
+	Sequence         uint32
+}
+
+// TxOut is a transaction output.
+type TxOut struct {
+	Value    int64
+	PkScript []byte
+}
+
+// Transaction is a bitcoin transaction.
+type Transaction struct {
+	Version  int32
+	TxIn     []*TxIn
+	TxOut    []*TxOut
+	LockTime uint32
+}
+
+// NewTransaction returns an empty transaction.
+func NewTransaction(version int32) *Transaction {
+	return &Transaction{Version: version}
+}
+
+// AddTxIn adds an input spending the outpoint with the final sequence.
+func (tx *Transaction) AddTxIn(outPoint OutPoint) *TxIn {
+	in := &TxIn{PreviousOutPoint: outPoint, Sequence: MaxSequence}
+	tx.TxIn = append(tx.TxIn, in)
+	return in
+}
+
+// AddTxOut adds an output.
+func (tx *Transaction) AddTxOut(value int64, pkScript []byte) *TxOut {
+	out := &TxOut{Value: value, PkScript: pkScript}
+	tx.TxOut = append(tx.TxOut, out)
+	return out
+}
+
+// HasWitness reports whether any input has a witness.
+func (tx *Transaction) HasWitness() bool {
+	for _, in := range tx.TxIn {
+		if len(in.Witness) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Serialize returns the BIP-144 serialization, with the marker, flag and witnesses when any input has a witness.
+func (tx *Transaction) Serialize() []byte {
+	return tx.serialize(tx.HasWitness())
+}
+
+// SerializeNoWitness returns the legacy serialization used for the txid.
+func (tx *Transaction) SerializeNoWitness() []byte {
+	return tx.serialize(false)
+}
+
+func (tx *Transaction) serialize(witness bool) []byte {
+	b := AppendUint32(nil, uint32(tx.Version))
+	if witness {
+		b = append(b, witnessMarker, witnessFlag)
+	}
+	b = AppendVarInt(b, uint64(len(tx.TxIn)))
+	for _, in := range tx.TxIn {
+		b = appendOutPoint(b, in.PreviousOutPoint)
+		b = AppendVarBytes(b, in.SignatureScript)
+		b = AppendUint32(b, in.Sequence)
+	}
+	b = AppendVarInt(b, uint64(len(tx.TxOut)))
+	for _, out := range tx.TxOut {
+		b = appendTxOut(b, out)
+	}
+	if witness {
+		for _, in := range tx.TxIn {
+			b = AppendVarInt(b, uint64(len(in.Witness)))
+			for _, item := range in.Witness {
+				b = AppendVarBytes(b, item)
+			}
+		}
+	}
+	return AppendUint32(b, tx.LockTime)
+}
+
+// TxHash returns the txid, the double SHA-256 of the serialization without witnesses.
+func (tx *Transaction) TxHash() Hash {
+	return doubleSha256(tx.SerializeNoWitness())
+}
+
+// WitnessHash returns the wtxid, equal to the txid when no input has a witness.
+func (tx *Transaction) WitnessHash() Hash {
+	return doubleSha256(tx.Serialize())
+}
+
+// Copy returns a deep copy of the transaction.
+func (tx *Transaction) Copy() *Transaction {
+	c := &Transaction{Version: tx.Version, LockTime: tx.LockTime}
+	for _, in := range tx.TxIn {
+		copied := &TxIn{
+			PreviousOutPoint: in.PreviousOutPoint,
+			SignatureScript:  append([]byte{}, in.SignatureScript...),
+			Sequence:         in.Sequence,
+		}
+		for _, item := range in.Witness {
+			copied.Witness = append(copied.Witness, append([]byte{}, item...))
+		}
+		c.TxIn = append(c.TxIn, copied)
+	}
+	for _, out := range tx.TxOut {
+		c.TxOut = append(c.TxOut, &TxOut{Value: out.Value, PkScript: append([]byte{}, out.PkScript...)})
+	}
+	return c
+}
+
+// DeserializeTransaction decodes a transaction with or without witnesses.
+func DeserializeTransaction(data []byte) (*Transaction, error) {
+	return deserializeTransaction(data, true)
+}
+
+// DeserializeTransactionNoWitness decodes a transaction in the legacy format only,
+// where a transaction without inputs is not mistaken for the witness marker.
+func DeserializeTransactionNoWitness(data []byte) (*Transaction, error) {
+	return deserializeTransaction(data, false)
+}
+
+func deserializeTransaction(data []byte, allowWitness bool) (*Transaction, error) {
+	r := NewReader(data)
+	tx := &Transaction{Version: int32(r.Uint32())}
+
+	inCount := r.Count(minTxInSize)
+	witness := false
+	if allowWitness && inCount == 0 && r.err == nil && len(r.data) > 0 && r.data[0] == witnessFlag {
+		r.Byte()
+		witness = true
+		inCount = r.Count(minTxInSize)
+		if inCount == 0 && r.err == nil {
+			return nil, ErrInvalidWitness
+		}
+	}
+
+	for i := 0; i < inCount && r.err == nil; i++ {
+		in := &TxIn{}
+		copy(in.PreviousOutPoint.Hash[:], r.Read(32))
+		in.PreviousOutPoint.Index = r.Uint32()
+		in.SignatureScript = r.VarBytes()
+		in.Sequence = r.Uint32()
+		tx.TxIn = append(tx.TxIn, in)
+	}
+	outCount := r.Count(minTxOutSize)
+	for i := 0; i < outCount && r.err == nil; i++ {
+		out := &TxOut{Value: int64(r.Uint64())}
+		out.PkScript = r.VarBytes()
+		tx.TxOut = append(tx.TxOut, out)
+	}
+	if witness {
+		for _, in := range tx.TxIn {
+			items := r.Count(1)
+			for i := 0; i < items && r.err == nil; i++ {
+				in.Witness = append(in.Witness, r.VarBytes())
+			}
+		}
+		if r.err == nil && !tx.HasWitness() {
+			// the witness serialization must not be used without witnesses
+			return nil, ErrInvalidWitness
+		}
+	}
+	tx.LockTime = r.Uint32()
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) != 0 {
+		return nil, ErrTrailingBytes
+	}
+	return tx, nil
+}
+
+func appendOutPoint(b []byte, outPoint OutPoint) []byte {
+	return AppendUint32(append(b, outPoint.Hash[:]...), outPoint.Index)
+}
+
+func appendTxOut(b []byte, out *TxOut) []byte {
+	return AppendVarBytes(AppendUint64(b, uint64(out.Value)), out.PkScript)
+}
+
+func doubleSha256(data []byte) Hash {
+	first := sha256.Sum256(data)
+	return sha256.Sum256(first[:])
+}
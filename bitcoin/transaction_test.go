@@ -0,0 +1,133 @@
+package bitcoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+func TestTransactionSerialize(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		txid  string
+		wtxid string
+	}{
+		{
+			// the first bitcoin transaction, block 170
+			name:  "legacy",
+			raw:   "0100000001c997a5e56e104102fa209c6a852dd90660a20b2d9c352423edce25857fcd3704000000004847304402204e45e16932b8af514961a1d3a1a25fdf3f4f7732e9d624c6c61548ab5fb8cd410220181522ec8eca07de4860a4acdd12909d831cc56cbbac4622082221a8768d1d0901ffffffff0200ca9a3b00000000434104ae1a62fe09c5f51b13905f07f06b99a2f7159b2225f374cd378d71302fa28414e7aab37397f554a7df5f142c21c1b7303b8a0626f1baded5c72a704f7e6cd84cac00286bee0000000043410411db93e1dcdb8a016b49840f8c53bc1eb68a382e97b1482ecad7b148a6909a5cb2e0eaddfb84ccf9744464f82e160bfa9b8b64f9d4c03f999b8643f656b412a3ac00000000",
+			txid:  "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+			wtxid: "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := hex.DecodeString(tt.raw)
+			tx, err := DeserializeTransaction(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(tx.Serialize()); got != tt.raw {
+				t.Errorf("Serialize() = %s, want %s", got, tt.raw)
+			}
+			if tx.TxHash().String() != tt.txid {
+				t.Errorf("TxHash() = %s, want %s", tx.TxHash(), tt.txid)
+			}
+			if tx.WitnessHash().String() != tt.wtxid {
+				t.Errorf("WitnessHash() = %s, want %s", tx.WitnessHash(), tt.wtxid)
+			}
+		})
+	}
+}
+
+func TestTransactionWitness(t *testing.T) {
+	tx, _ := taprootTestTx()
+	legacy := tx.Serialize()
+	tx.TxIn[0].Witness = [][]byte{bytes.Repeat([]byte{0xaa}, 64)}
+	tx.TxIn[2].Witness = [][]byte{{}, {0x01, 0x02}}
+
+	raw := tx.Serialize()
+	if raw[4] != 0x00 || raw[5] != 0x01 {
+		t.Fatalf("Serialize() has no segwit marker and flag: %x", raw[:6])
+	}
+	if !bytes.Equal(tx.SerializeNoWitness(), legacy) {
+		t.Errorf("SerializeNoWitness() = %x, want %x", tx.SerializeNoWitness(), legacy)
+	}
+	if tx.TxHash() != doubleSha256(legacy) || tx.WitnessHash() != doubleSha256(raw) || tx.TxHash() == tx.WitnessHash() {
+		t.Errorf("TxHash() = %s, WitnessHash() = %s", tx.TxHash(), tx.WitnessHash())
+	}
+
+	decoded, err := DeserializeTransaction(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded.Serialize(), raw) {
+		t.Errorf("DeserializeTransaction() round trip = %x, want %x", decoded.Serialize(), raw)
+	}
+	if len(decoded.TxIn[1].Witness) != 0 || len(decoded.TxIn[2].Witness) != 2 {
+		t.Errorf("witnesses = %v", decoded.TxIn)
+	}
+
+	invalid := []struct {
+		raw []byte
+		err error
+	}{
+		{raw[:len(raw)-1], ErrUnexpectedEnd},
+		{append(append([]byte{}, raw...), 0x00), ErrTrailingBytes},
+		// the witness flag with three empty witnesses
+		{append(append(append(append(append([]byte{}, legacy[:4]...), 0x00, 0x01), legacy[4:len(legacy)-4]...),
+			0x00, 0x00, 0x00), legacy[len(legacy)-4:]...), ErrInvalidWitness},
+		// a count of 3 inputs as 0xfd0300
+		{append(append(append([]byte{}, legacy[:4]...), 0xfd, 0x03, 0x00), legacy[5:]...), ErrNonCanonicalVarInt},
+	}
+	for i, tt := range invalid {
+		if _, err := DeserializeTransaction(tt.raw); err != tt.err {
+			t.Errorf("#%d: DeserializeTransaction() error = %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+func TestDeserializeTransactionNoWitness(t *testing.T) {
+	// no inputs and an OP_RETURN output, the output count reads as the witness flag
+	raw, _ := hex.DecodeString("01000000000100000000000000000d6a0b68656c6c6f20776f726c6400000000")
+	tx, err := DeserializeTransactionNoWitness(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxIn) != 0 || len(tx.TxOut) != 1 || !bytes.Equal(tx.SerializeNoWitness(), raw) {
+		t.Errorf("DeserializeTransactionNoWitness() = %+v", tx)
+	}
+	if _, err := DeserializeTransaction(raw); err == nil {
+		t.Errorf("DeserializeTransaction() accepted the input-less transaction as segwit")
+	}
+}
+
+func TestSignLegacyInput(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	seckey := mustDecodeHex(t, "619c335025c7f4012e556c2a58b2506e30b8511b53ade95ea316fd8c3286feb9")
+	pubKey := mustDecodeHex(t, "025476c2e83188368da1ff3e292e7acafcdb3566bb0ad253f62fc70f07aeee6357")
+	subScript := P2PKHScript(mustDecodeHex(t, "1d0f172a0ecb48aee1be1f2687d2963ae33f71a1"))
+
+	tx, _ := taprootTestTx()
+	for _, hashType := range []SigHashType{SigHashAll, SigHashNone, SigHashSingle | SigHashAnyoneCanPay} {
+		sig, err := tx.SignLegacyInput(ctx, 1, subScript, hashType, seckey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig[len(sig)-1] != byte(hashType) {
+			t.Errorf("sighash type byte = %x, want %x", sig[len(sig)-1], hashType)
+		}
+		hash, _ := tx.LegacySigHash(1, subScript, hashType)
+		if !VerifyECDSA(ctx, hash, sig[:len(sig)-1], pubKey) {
+			t.Errorf("VerifyECDSA(%x) = false", hashType)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package bitcoin
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	ErrUnexpectedEnd      = errors.New("unexpected end of data")
+	ErrNonCanonicalVarInt = errors.New("non canonical compact size")
+	ErrTrailingBytes      = errors.New("trailing bytes after the transaction")
+	ErrInvalidWitness     = errors.New("invalid witness serialization")
+)
+
+// AppendVarInt appends the compact size encoding of n.
+func AppendVarInt(b []byte, n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return append(b, byte(n))
+	case n <= 0xffff:
+		return append(b, 0xfd, byte(n), byte(n>>8))
+	case n <= 0xffffffff:
+		return AppendUint32(append(b, 0xfe), uint32(n))
+	}
+	return AppendUint64(append(b, 0xff), n)
+}
+
+// AppendUint32 appends n in little endian.
+func AppendUint32(b []byte, n uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+// AppendUint64 appends n in little endian.
+func AppendUint64(b []byte, n uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], n)
+	return append(b, buf[:]...)
+}
+
+// AppendVarBytes appends the compact size of data followed by data.
+func AppendVarBytes(b []byte, data []byte) []byte {
+	return append(AppendVarInt(b, uint64(len(data))), data...)
+}
+
+// Reader reads the wire encoding and keeps the first error, so a sequence of
+// reads needs a single check of Err at the end.
+type Reader struct {
+	data []byte
+	err  error
+}
+
+// NewReader returns a Reader of data.
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Err returns the first error of the reads.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Len returns the number of unread bytes.
+func (r *Reader) Len() int {
+	return len(r.data)
+}
+
+// Read returns the next n bytes.
+func (r *Reader) Read(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data) < n {
+		r.err = ErrUnexpectedEnd
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *Reader) Byte() byte {
+	b := r.Read(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *Reader) Uint32() uint32 {
+	b := r.Read(4)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+func (r *Reader) Uint64() uint64 {
+	b := r.Read(8)
+	if b == nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint64(b)
+}
+
+// VarInt reads a compact size and rejects a non canonical encoding.
+func (r *Reader) VarInt() uint64 {
+	prefix := r.Byte()
+	var n, min uint64
+	switch prefix {
+	case 0xfd:
+		b := r.Read(2)
+		if b == nil {
+			return 0
+		}
+		n, min = uint64(binary.LittleEndian.Uint16(b)), 0xfd
+	case 0xfe:
+		n, min = uint64(r.Uint32()), 0x10000
+	case 0xff:
+		n, min = r.Uint64(), 0x100000000
+	default:
+		return uint64(prefix)
+	}
+	if r.err == nil && n < min {
+		r.err = ErrNonCanonicalVarInt
+	}
+	return n
+}
+
+// Count reads a compact size count of items at least minSize bytes long each,
+// bounded by the remaining data so a corrupt count can not allocate too much.
+func (r *Reader) Count(minSize int) int {
+	n := r.VarInt()
+	if r.err == nil && n > uint64(len(r.data)/minSize) {
+		r.err = ErrUnexpectedEnd
+		return 0
+	}
+	return int(n)
+}
+
+// VarBytes reads a compact size length followed by that many bytes.
+func (r *Reader) VarBytes() []byte {
+	n := r.Count(1)
+	b := r.Read(n)
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
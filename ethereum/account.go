@@ -0,0 +1,30 @@
+package ethereum
+
+import (
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/bip44"
+)
+
+// DerivationPath returns the default ethereum path m/44'/60'/0'/0/index.
+func DerivationPath(index uint32) (bip32.DerivationPath, error) {
+	path := bip44.NewAccountPath(bip44.BIP44Purpose, bip44.EthereumCoinType, 0)
+	return path.Address(bip44.ExternalChain, index).DerivationPath()
+}
+
+// DeriveKey derives the private key at m/44'/60'/0'/0/index from the seed.
+func DeriveKey(seed []byte, index uint32) (*bip32.PrivateKey, error) {
+	account, err := bip44.DeriveAccount(seed, bip44.Network{Coin: bip44.EthereumCoinType}, bip44.BIP44Purpose, 0)
+	if err != nil {
+		return nil, err
+	}
+	return account.AddressKey(bip44.ExternalChain, index)
+}
+
+// DeriveAddress derives the address at m/44'/60'/0'/0/index from the seed.
+func DeriveAddress(seed []byte, index uint32) (Address, error) {
+	key, err := DeriveKey(seed, index)
+	if err != nil {
+		return Address{}, err
+	}
+	return AddressFromExtendedPrivateKey(key)
+}
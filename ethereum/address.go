@@ -0,0 +1,128 @@
+// Package ethereum derives, encodes and signs for ethereum accounts.
+package ethereum
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// AddressLength is the length of an address in bytes.
+const AddressLength = 20
+
+var (
+	ErrInvalidAddressLength = errors.New("address must be 20 bytes")
+	ErrInvalidAddressHex    = errors.New("address is not valid hex")
+	ErrInvalidChecksum      = errors.New("mixed case address has an invalid EIP-55 checksum")
+	ErrInvalidPublicKey     = errors.New("public key must be 65 bytes uncompressed")
+)
+
+// Address is the last 20 bytes of the Keccak-256 hash of the uncompressed public key.
+type Address [AddressLength]byte
+
+// BytesToAddress returns the address of the last 20 bytes of b, left padded when b is shorter.
+func BytesToAddress(b []byte) Address {
+	var a Address
+	if len(b) > AddressLength {
+		b = b[len(b)-AddressLength:]
+	}
+	copy(a[AddressLength-len(b):], b)
+	return a
+}
+
+// ParseAddress decodes a hex address with an optional 0x prefix. An all lower or all upper
+// case address is accepted as is, a mixed case address must have a valid EIP-55 checksum.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	hexStr := strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(hexStr) != 2*AddressLength {
+		return a, ErrInvalidAddressLength
+	}
+	b, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return a, ErrInvalidAddressHex
+	}
+	copy(a[:], b)
+
+	if hexStr != strings.ToLower(hexStr) && hexStr != strings.ToUpper(hexStr) && a.Hex()[2:] != hexStr {
+		return a, ErrInvalidChecksum
+	}
+	return a, nil
+}
+
+// ValidateAddress checks the address, see ParseAddress.
+func ValidateAddress(s string) error {
+	_, err := ParseAddress(s)
+	return err
+}
+
+// IsChecksumAddress reports whether s is the EIP-55 encoding of an address.
+func IsChecksumAddress(s string) bool {
+	a, err := ParseAddress(s)
+	return err == nil && a.Hex() == s
+}
+
+// Bytes returns the 20 bytes of the address.
+func (a Address) Bytes() []byte {
+	return a[:]
+}
+
+// Hex returns the EIP-55 mixed case checksum encoding of the address:
+// a hex letter is upper case when the matching nibble of Keccak-256(lower case hex) is at least 8.
+func (a Address) Hex() string {
+	lower := hex.EncodeToString(a[:])
+	hash := Keccak256([]byte(lower))
+
+	result := []byte(lower)
+	for i, c := range result {
+		if c < 'a' {
+			continue
+		}
+		nibble := hash[i/2] >> 4
+		if i%2 == 1 {
+			nibble = hash[i/2] & 0xf
+		}
+		if nibble >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
+
+func (a Address) String() string {
+	return a.Hex()
+}
+
+// PubkeyToAddress returns the address of a 65 bytes uncompressed public key.
+func PubkeyToAddress(pubKey []byte) (Address, error) {
+	if len(pubKey) != secp256k1.LenUncompressed || pubKey[0] != 0x4 {
+		return Address{}, ErrInvalidPublicKey
+	}
+	return BytesToAddress(Keccak256(pubKey[1:])[12:]), nil
+}
+
+// AddressFromPublicKey returns the address of a secp256k1 public key.
+func AddressFromPublicKey(ctx *secp256k1.Context, pubKey *secp256k1.PublicKey) (Address, error) {
+	_, serialized, err := secp256k1.EcPubkeySerialize(ctx, pubKey, secp256k1.EcUncompressed)
+	if err != nil {
+		return Address{}, err
+	}
+	return PubkeyToAddress(serialized)
+}
+
+// AddressFromExtendedPublicKey returns the address of a bip32 public key.
+func AddressFromExtendedPublicKey(key *bip32.PublicKey) (Address, error) {
+	uncompressed, err := key.Uncompressed()
+	if err != nil {
+		return Address{}, err
+	}
+	return PubkeyToAddress(uncompressed)
+}
+
+// AddressFromExtendedPrivateKey returns the address of a bip32 private key.
+func AddressFromExtendedPrivateKey(key *bip32.PrivateKey) (Address, error) {
+	return AddressFromExtendedPublicKey(key.ToPublicKey())
+}
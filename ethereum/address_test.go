@@ -0,0 +1,135 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// seed of the mnemonic "abandon abandon ... about"
+const testSeed = "5eb00bbddcf069084889a8ab9155568165f5c453ccb85e70811aaed6f6da5fc19a5ac40b389cd370d086206dec8aa6c43daea6690f20ad3d8d48b2d2ce9e38e4"
+
+func TestAddressHex(t *testing.T) {
+	// https://eips.ethereum.org/EIPS/eip-55, the all caps and all lower vectors are
+	// their own checksum encoding
+	tests := []string{
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x27b1fdb04752bbc536007a920d24acb045561c26",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	}
+	for _, tt := range tests {
+		a, err := ParseAddress(tt)
+		if err != nil {
+			t.Fatalf("ParseAddress(%s) error = %v", tt, err)
+		}
+		if a.Hex() != tt {
+			t.Errorf("Hex() = %s, want %s", a.Hex(), tt)
+		}
+		if !IsChecksumAddress(tt) {
+			t.Errorf("IsChecksumAddress(%s) = false", tt)
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		err  error
+	}{
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", nil},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", nil},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil},
+		{"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED", nil},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", ErrInvalidChecksum},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", ErrInvalidAddressLength},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAzz", ErrInvalidAddressHex},
+	}
+	for _, tt := range tests {
+		if err := ValidateAddress(tt.addr); err != tt.err {
+			t.Errorf("ValidateAddress(%s) = %v, want %v", tt.addr, err, tt.err)
+		}
+	}
+}
+
+func TestAddressFromPublicKey(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	tests := []struct {
+		privateKey string
+		want       string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000001", "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"},
+		{"0000000000000000000000000000000000000000000000000000000000000002", "0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF"},
+	}
+	for _, tt := range tests {
+		seckey, _ := hex.DecodeString(tt.privateKey)
+		_, pubKey, err := secp256k1.EcPubkeyCreate(ctx, seckey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := AddressFromPublicKey(ctx, pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Hex() != tt.want {
+			t.Errorf("AddressFromPublicKey() = %s, want %s", a.Hex(), tt.want)
+		}
+
+		chainCode := make([]byte, 32)
+		key, err := bip32.NewMasterKeyFromKeyAndChainCode(seckey, chainCode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a, _ := AddressFromExtendedPrivateKey(key); a.Hex() != tt.want {
+			t.Errorf("AddressFromExtendedPrivateKey() = %s, want %s", a.Hex(), tt.want)
+		}
+	}
+
+	if _, err := PubkeyToAddress(make([]byte, 33)); err != ErrInvalidPublicKey {
+		t.Errorf("PubkeyToAddress() error = %v, want %v", err, ErrInvalidPublicKey)
+	}
+}
+
+func TestDeriveAddress(t *testing.T) {
+	seed, _ := hex.DecodeString(testSeed)
+	tests := []struct {
+		index uint32
+		path  string
+		want  string
+	}{
+		{0, "m/44'/60'/0'/0/0", "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"},
+		{1, "m/44'/60'/0'/0/1", "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0"},
+		{2, "m/44'/60'/0'/0/2", "0xb6716976A3ebe8D39aCEB04372f22Ff8e6802D7A"},
+	}
+	for _, tt := range tests {
+		path, err := DerivationPath(tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path.String() != tt.path {
+			t.Errorf("DerivationPath() = %s, want %s", path, tt.path)
+		}
+		a, err := DeriveAddress(seed, tt.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Hex() != tt.want {
+			t.Errorf("DeriveAddress(%d) = %s, want %s", tt.index, a.Hex(), tt.want)
+		}
+	}
+
+	if _, err := DeriveAddress(seed, bip32.HardenedKeyZeroIndex); err == nil {
+		t.Errorf("DeriveAddress() of a hardened index should fail")
+	}
+}
@@ -0,0 +1,12 @@
+package ethereum
+
+import "golang.org/x/crypto/sha3"
+
+// Keccak256 returns the legacy Keccak-256 hash of the data, which differs from the standardized SHA3-256 in padding.
+func Keccak256(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
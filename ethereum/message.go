@@ -0,0 +1,30 @@
+package ethereum
+
+import (
+	"strconv"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// MessageHash returns the EIP-191 personal_sign hash
+// Keccak256("\x19Ethereum Signed Message:\n" || len(message) || message).
+func MessageHash(message []byte) []byte {
+	prefix := "\x19Ethereum Signed Message:\n" + strconv.Itoa(len(message))
+	return Keccak256([]byte(prefix), message)
+}
+
+// SignMessage signs the message as personal_sign does.
+func SignMessage(ctx *secp256k1.Context, message []byte, seckey []byte) ([]byte, error) {
+	return SignHash(ctx, MessageHash(message), seckey)
+}
+
+// RecoverMessage returns the address that signed the message.
+func RecoverMessage(ctx *secp256k1.Context, message []byte, sig []byte) (Address, error) {
+	return Ecrecover(ctx, MessageHash(message), sig)
+}
+
+// VerifyMessage reports whether the address signed the message.
+func VerifyMessage(ctx *secp256k1.Context, address Address, message []byte, sig []byte) bool {
+	signer, err := RecoverMessage(ctx, message, sig)
+	return err == nil && signer == address
+}
@@ -0,0 +1,56 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+func TestSignMessage(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	// web3.eth.accounts.sign example
+	seckey, _ := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	signer, _ := ParseAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	message := []byte("Some data")
+
+	if hash := MessageHash(message); hex.EncodeToString(hash) != "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655" {
+		t.Errorf("MessageHash() = %x", hash)
+	}
+	sig, err := SignMessage(ctx, message, seckey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c"
+	if hex.EncodeToString(sig) != want {
+		t.Errorf("SignMessage() = %x, want %s", sig, want)
+	}
+
+	if a, err := RecoverMessage(ctx, message, sig); err != nil || a != signer {
+		t.Errorf("RecoverMessage() = %s, %v, want %s", a, err, signer)
+	}
+	if !VerifyMessage(ctx, signer, message, sig) {
+		t.Errorf("VerifyMessage() = false")
+	}
+	if VerifyMessage(ctx, signer, []byte("Some other data"), sig) {
+		t.Errorf("VerifyMessage() of another message = true")
+	}
+
+	// v as the bare recovery id
+	sig[64] -= 27
+	if a, err := RecoverMessage(ctx, message, sig); err != nil || a != signer {
+		t.Errorf("RecoverMessage() = %s, %v, want %s", a, err, signer)
+	}
+	sig[64] = 29
+	if _, err := RecoverMessage(ctx, message, sig); err != ErrInvalidSignature {
+		t.Errorf("RecoverMessage() error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := RecoverMessage(ctx, message, sig[:64]); err != ErrInvalidSignatureLength {
+		t.Errorf("RecoverMessage() error = %v, want %v", err, ErrInvalidSignatureLength)
+	}
+}
@@ -0,0 +1,183 @@
+// Package rlp implements the recursive length prefix encoding used by ethereum transactions.
+package rlp
+
+import (
+	"errors"
+	"math/big"
+)
+
+var (
+	ErrUnexpectedEnd  = errors.New("rlp: unexpected end of input")
+	ErrTrailingBytes  = errors.New("rlp: trailing bytes after the item")
+	ErrNonCanonical   = errors.New("rlp: non canonical encoding")
+	ErrExpectedString = errors.New("rlp: expected a string")
+	ErrExpectedList   = errors.New("rlp: expected a list")
+	ErrUintOverflow   = errors.New("rlp: integer overflows uint64")
+	ErrNegativeInt    = errors.New("rlp: negative integers can not be encoded")
+)
+
+const (
+	shortStringOffset = 0x80
+	longStringOffset  = 0xb7
+	shortListOffset   = 0xc0
+	longListOffset    = 0xf7
+	// maxShortLength is the longest payload with the length in the prefix byte
+	maxShortLength = 55
+)
+
+// EncodeBytes encodes a byte string.
+func EncodeBytes(b []byte) []byte {
+	if len(b) == 1 && b[0] < shortStringOffset {
+		return []byte{b[0]}
+	}
+	return append(encodeLength(len(b), shortStringOffset), b...)
+}
+
+// EncodeUint encodes an integer as its big-endian bytes without leading zeros.
+func EncodeUint(i uint64) []byte {
+	return EncodeBytes(new(big.Int).SetUint64(i).Bytes())
+}
+
+// EncodeBigInt encodes a non negative integer, a nil integer is encoded as zero.
+func EncodeBigInt(i *big.Int) ([]byte, error) {
+	if i == nil {
+		return EncodeBytes(nil), nil
+	}
+	if i.Sign() < 0 {
+		return nil, ErrNegativeInt
+	}
+	return EncodeBytes(i.Bytes()), nil
+}
+
+// EncodeList encodes a list of already encoded items.
+func EncodeList(items ...[]byte) []byte {
+	var payload []byte
+	for _, item := range items {
+		payload = append(payload, item...)
+	}
+	return append(encodeLength(len(payload), shortListOffset), payload...)
+}
+
+func encodeLength(length int, offset byte) []byte {
+	if length <= maxShortLength {
+		return []byte{offset + byte(length)}
+	}
+	lengthBytes := new(big.Int).SetUint64(uint64(length)).Bytes()
+	return append([]byte{offset + maxShortLength + byte(len(lengthBytes))}, lengthBytes...)
+}
+
+// Item is a decoded byte string or list.
+type Item struct {
+	IsList bool
+	Bytes  []byte
+	List   []Item
+}
+
+// Decode decodes exactly one item.
+func Decode(b []byte) (Item, error) {
+	item, rest, err := decodeItem(b)
+	if err != nil {
+		return Item{}, err
+	}
+	if len(rest) != 0 {
+		return Item{}, ErrTrailingBytes
+	}
+	return item, nil
+}
+
+// Uint64 decodes the string as a canonical integer.
+func (it Item) Uint64() (uint64, error) {
+	if it.IsList {
+		return 0, ErrExpectedString
+	}
+	if len(it.Bytes) > 8 {
+		return 0, ErrUintOverflow
+	}
+	if len(it.Bytes) > 0 && it.Bytes[0] == 0 {
+		return 0, ErrNonCanonical
+	}
+	var i uint64
+	for _, b := range it.Bytes {
+		i = i<<8 | uint64(b)
+	}
+	return i, nil
+}
+
+// BigInt decodes the string as a canonical integer.
+func (it Item) BigInt() (*big.Int, error) {
+	if it.IsList {
+		return nil, ErrExpectedString
+	}
+	if len(it.Bytes) > 0 && it.Bytes[0] == 0 {
+		return nil, ErrNonCanonical
+	}
+	return new(big.Int).SetBytes(it.Bytes), nil
+}
+
+func decodeItem(b []byte) (Item, []byte, error) {
+	if len(b) == 0 {
+		return Item{}, nil, ErrUnexpectedEnd
+	}
+	prefix := b[0]
+	switch {
+	case prefix < shortStringOffset:
+		return Item{Bytes: b[:1]}, b[1:], nil
+	case prefix < shortListOffset:
+		payload, rest, err := decodePayload(b, shortStringOffset)
+		if err != nil {
+			return Item{}, nil, err
+		}
+		if len(payload) == 1 && payload[0] < shortStringOffset {
+			// a single byte below 0x80 is its own encoding
+			return Item{}, nil, ErrNonCanonical
+		}
+		return Item{Bytes: payload}, rest, nil
+	default:
+		payload, rest, err := decodePayload(b, shortListOffset)
+		if err != nil {
+			return Item{}, nil, err
+		}
+		list := Item{IsList: true, List: []Item{}}
+		for len(payload) > 0 {
+			var item Item
+			item, payload, err = decodeItem(payload)
+			if err != nil {
+				return Item{}, nil, err
+			}
+			list.List = append(list.List, item)
+		}
+		return list, rest, nil
+	}
+}
+
+// decodePayload splits the payload of a string or list with the prefix offset from the rest of the input.
+func decodePayload(b []byte, offset byte) ([]byte, []byte, error) {
+	prefix := b[0] - offset
+	b = b[1:]
+	if prefix <= maxShortLength {
+		if len(b) < int(prefix) {
+			return nil, nil, ErrUnexpectedEnd
+		}
+		return b[:prefix], b[prefix:], nil
+	}
+
+	lengthSize := int(prefix - maxShortLength)
+	if len(b) < lengthSize {
+		return nil, nil, ErrUnexpectedEnd
+	}
+	if b[0] == 0 || lengthSize > 8 {
+		return nil, nil, ErrNonCanonical
+	}
+	var length uint64
+	for _, c := range b[:lengthSize] {
+		length = length<<8 | uint64(c)
+	}
+	if length <= maxShortLength {
+		return nil, nil, ErrNonCanonical
+	}
+	b = b[lengthSize:]
+	if uint64(len(b)) < length {
+		return nil, nil, ErrUnexpectedEnd
+	}
+	return b[:length], b[length:], nil
+}
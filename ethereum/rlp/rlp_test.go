@@ -0,0 +1,86 @@
+package rlp
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		encoded []byte
+		want    string
+	}{
+		{"empty string", EncodeBytes(nil), "80"},
+		{"single byte", EncodeBytes([]byte{0x7f}), "7f"},
+		{"byte 0x80", EncodeBytes([]byte{0x80}), "8180"},
+		{"dog", EncodeBytes([]byte("dog")), "83646f67"},
+		{"zero", EncodeUint(0), "80"},
+		{"15", EncodeUint(15), "0f"},
+		{"1024", EncodeUint(1024), "820400"},
+		{"empty list", EncodeList(), "c0"},
+		{"cat dog", EncodeList(EncodeBytes([]byte("cat")), EncodeBytes([]byte("dog"))), "c88363617483646f67"},
+		{"set of three", EncodeList(EncodeList(), EncodeList(EncodeList()), EncodeList(EncodeList(), EncodeList(EncodeList()))), "c7c0c1c0c3c0c1c0"},
+		{"long string", EncodeBytes([]byte("Lorem ipsum dolor sit amet, consectetur adipisicing elit")),
+			"b8384c6f72656d20697073756d20646f6c6f722073697420616d65742c20636f6e7365637465747572206164697069736963696e6720656c6974"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.encoded); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	if got, _ := EncodeBigInt(new(big.Int).Lsh(big.NewInt(1), 64)); hex.EncodeToString(got) != "89010000000000000000" {
+		t.Errorf("EncodeBigInt(2^64) = %x", got)
+	}
+	if _, err := EncodeBigInt(big.NewInt(-1)); err != ErrNegativeInt {
+		t.Errorf("EncodeBigInt(-1) error = %v, want %v", err, ErrNegativeInt)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	long := strings.Repeat("ab", 1024)
+	encodedLong := hex.EncodeToString(EncodeList(EncodeBytes(mustDecodeHex(long)), EncodeUint(1)))
+	item, err := Decode(mustDecodeHex(encodedLong))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !item.IsList || len(item.List) != 2 || hex.EncodeToString(item.List[0].Bytes) != long {
+		t.Fatalf("Decode() = %+v", item)
+	}
+	if i, err := item.List[1].Uint64(); err != nil || i != 1 {
+		t.Errorf("Uint64() = %d, %v", i, err)
+	}
+
+	invalid := []struct {
+		encoded string
+		err     error
+	}{
+		{"", ErrUnexpectedEnd},
+		{"83646f", ErrUnexpectedEnd},
+		{"8100", ErrNonCanonical},
+		{"b800", ErrNonCanonical},
+		{"b90038" + strings.Repeat("00", 56), ErrNonCanonical},
+		{"c883636174", ErrUnexpectedEnd},
+		{"8000", ErrTrailingBytes},
+	}
+	for _, tt := range invalid {
+		if _, err := Decode(mustDecodeHex(tt.encoded)); err != tt.err {
+			t.Errorf("Decode(%s) error = %v, want %v", tt.encoded, err, tt.err)
+		}
+	}
+
+	if _, err := (Item{Bytes: []byte{0, 1}}).Uint64(); err != ErrNonCanonical {
+		t.Errorf("Uint64() error = %v, want %v", err, ErrNonCanonical)
+	}
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,49 @@
+package ethereum
+
+import (
+	"errors"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// SignatureLength is the length of an r || s || v signature.
+const SignatureLength = 65
+
+var ErrInvalidSignatureLength = errors.New("signature must be 65 bytes r || s || v")
+
+// SignHash signs a 32 bytes hash and returns the 65 bytes r || s || v signature, v is 27 + recovery id.
+func SignHash(ctx *secp256k1.Context, hash []byte, seckey []byte) ([]byte, error) {
+	_, sig, err := secp256k1.EcdsaSignRecoverable(ctx, hash, seckey)
+	if err != nil {
+		return nil, err
+	}
+	_, compact, recid, err := secp256k1.EcdsaRecoverableSignatureSerializeCompact(ctx, sig)
+	if err != nil {
+		return nil, err
+	}
+	return append(compact, byte(27+recid)), nil
+}
+
+// Ecrecover returns the address that signed the hash, v may be the recovery id or 27 + recovery id.
+func Ecrecover(ctx *secp256k1.Context, hash []byte, sig []byte) (Address, error) {
+	if len(sig) != SignatureLength {
+		return Address{}, ErrInvalidSignatureLength
+	}
+	recid := int(sig[64])
+	if recid >= 27 {
+		recid -= 27
+	}
+	if recid != 0 && recid != 1 {
+		return Address{}, ErrInvalidSignature
+	}
+
+	_, recoverable, err := secp256k1.EcdsaRecoverableSignatureParseCompact(ctx, sig[:64], recid)
+	if err != nil {
+		return Address{}, err
+	}
+	_, pubKey, err := secp256k1.EcdsaRecover(ctx, recoverable, hash)
+	if err != nil {
+		return Address{}, err
+	}
+	return AddressFromPublicKey(ctx, pubKey)
+}
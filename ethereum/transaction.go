@@ -0,0 +1,398 @@
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/dubuqingfeng/signer/ethereum/rlp"
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// TxType is the EIP-2718 transaction type.
+type TxType byte
+
+const (
+	LegacyTxType     TxType = 0x0
+	AccessListTxType TxType = 0x1 // EIP-2930
+	DynamicFeeTxType TxType = 0x2 // EIP-1559
+)
+
+var (
+	ErrUnsupportedTxType  = errors.New("unsupported transaction type")
+	ErrUnsignedTx         = errors.New("transaction is not signed")
+	ErrInvalidSignature   = errors.New("invalid transaction signature")
+	ErrInvalidChainID     = errors.New("chain id is required for typed transactions")
+	ErrInvalidTransaction = errors.New("invalid transaction encoding")
+	ErrNegativeQuantity   = errors.New("transaction quantities must not be negative")
+)
+
+// secp256k1N is the curve order, secp256k1HalfN is n/2, signatures with a higher s are malleable.
+var (
+	secp256k1N, _  = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// AccessTuple is an address and the storage keys a transaction plans to access.
+type AccessTuple struct {
+	Address     Address
+	StorageKeys [][32]byte
+}
+
+// AccessList is the EIP-2930 access list.
+type AccessList []AccessTuple
+
+// Transaction is a legacy, access list or dynamic fee transaction.
+type Transaction struct {
+	Type TxType
+	// ChainID is required by typed transactions, a legacy transaction without it is signed without
+	// EIP-155 replay protection.
+	ChainID *big.Int
+	Nonce   uint64
+	// GasPrice is used by legacy and access list transactions.
+	GasPrice *big.Int
+	// GasTipCap and GasFeeCap are the max priority fee and max fee per gas of dynamic fee transactions.
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *Address // nil for contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+
+	// V is the recovery id for typed transactions, 27 + recovery id for unprotected legacy transactions
+	// and chainID * 2 + 35 + recovery id for EIP-155 transactions.
+	V, R, S *big.Int
+}
+
+// SigningHash returns the Keccak-256 hash signed by the sender.
+func (tx *Transaction) SigningHash() ([]byte, error) {
+	fields, err := tx.payloadFields()
+	if err != nil {
+		return nil, err
+	}
+	if tx.Type == LegacyTxType && tx.isProtected() {
+		// EIP-155: chainId, 0, 0
+		fields = append(fields, encodeBigInt(tx.ChainID), rlp.EncodeUint(0), rlp.EncodeUint(0))
+	}
+	return Keccak256(tx.envelope(rlp.EncodeList(fields...))), nil
+}
+
+// Sign signs the transaction with the 32 bytes private key and sets V, R and S.
+func (tx *Transaction) Sign(ctx *secp256k1.Context, seckey []byte) error {
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return err
+	}
+	_, sig, err := secp256k1.EcdsaSignRecoverable(ctx, hash, seckey)
+	if err != nil {
+		return err
+	}
+	_, compact, recid, err := secp256k1.EcdsaRecoverableSignatureSerializeCompact(ctx, sig)
+	if err != nil {
+		return err
+	}
+
+	v := big.NewInt(int64(recid))
+	if tx.Type == LegacyTxType {
+		if tx.isProtected() {
+			v.Add(v, new(big.Int).Lsh(tx.ChainID, 1))
+			v.Add(v, big.NewInt(35))
+		} else {
+			v.Add(v, big.NewInt(27))
+		}
+	}
+	tx.V = v
+	tx.R = new(big.Int).SetBytes(compact[:32])
+	tx.S = new(big.Int).SetBytes(compact[32:])
+	return nil
+}
+
+// MarshalBinary returns the raw signed transaction, rlp(fields) for legacy transactions and
+// type || rlp(fields) for typed transactions.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.V == nil || tx.R == nil || tx.S == nil {
+		return nil, ErrUnsignedTx
+	}
+	if tx.V.Sign() < 0 || tx.R.Sign() < 0 || tx.S.Sign() < 0 {
+		return nil, ErrInvalidSignature
+	}
+	fields, err := tx.payloadFields()
+	if err != nil {
+		return nil, err
+	}
+	fields = append(fields, encodeBigInt(tx.V), encodeBigInt(tx.R), encodeBigInt(tx.S))
+	return tx.envelope(rlp.EncodeList(fields...)), nil
+}
+
+// Hash returns the transaction hash, the Keccak-256 hash of the raw signed transaction.
+func (tx *Transaction) Hash() ([]byte, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return Keccak256(raw), nil
+}
+
+// Sender recovers the address that signed the transaction.
+func (tx *Transaction) Sender(ctx *secp256k1.Context) (Address, error) {
+	if tx.V == nil || tx.R == nil || tx.S == nil {
+		return Address{}, ErrUnsignedTx
+	}
+	recid, err := tx.recoveryID()
+	if err != nil {
+		return Address{}, err
+	}
+	if tx.R.Sign() <= 0 || tx.R.Cmp(secp256k1N) >= 0 || tx.S.Sign() <= 0 || tx.S.Cmp(secp256k1HalfN) > 0 {
+		return Address{}, ErrInvalidSignature
+	}
+
+	hash, err := tx.SigningHash()
+	if err != nil {
+		return Address{}, err
+	}
+	compact := make([]byte, secp256k1.LenCompactSig)
+	tx.R.FillBytes(compact[:32])
+	tx.S.FillBytes(compact[32:])
+	_, sig, err := secp256k1.EcdsaRecoverableSignatureParseCompact(ctx, compact, recid)
+	if err != nil {
+		return Address{}, err
+	}
+	_, pubKey, err := secp256k1.EcdsaRecover(ctx, sig, hash)
+	if err != nil {
+		return Address{}, err
+	}
+	return AddressFromPublicKey(ctx, pubKey)
+}
+
+// DecodeTransaction decodes a raw signed transaction.
+func DecodeTransaction(raw []byte) (*Transaction, error) {
+	if len(raw) == 0 {
+		return nil, ErrInvalidTransaction
+	}
+	tx := &Transaction{Type: LegacyTxType}
+	if raw[0] < 0x80 {
+		tx.Type = TxType(raw[0])
+		raw = raw[1:]
+	}
+
+	item, err := rlp.Decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !item.IsList {
+		return nil, rlp.ErrExpectedList
+	}
+	d := &fieldDecoder{fields: item.List}
+
+	switch tx.Type {
+	case LegacyTxType:
+		if len(d.fields) != 9 {
+			return nil, ErrInvalidTransaction
+		}
+		tx.Nonce = d.uint64()
+		tx.GasPrice = d.bigInt()
+		tx.Gas = d.uint64()
+		tx.To = d.address()
+		tx.Value = d.bigInt()
+		tx.Data = d.bytes()
+	case AccessListTxType, DynamicFeeTxType:
+		if (tx.Type == AccessListTxType && len(d.fields) != 11) || (tx.Type == DynamicFeeTxType && len(d.fields) != 12) {
+			return nil, ErrInvalidTransaction
+		}
+		tx.ChainID = d.bigInt()
+		tx.Nonce = d.uint64()
+		if tx.Type == AccessListTxType {
+			tx.GasPrice = d.bigInt()
+		} else {
+			tx.GasTipCap = d.bigInt()
+			tx.GasFeeCap = d.bigInt()
+		}
+		tx.Gas = d.uint64()
+		tx.To = d.address()
+		tx.Value = d.bigInt()
+		tx.Data = d.bytes()
+		tx.AccessList = d.accessList()
+	default:
+		return nil, ErrUnsupportedTxType
+	}
+	tx.V = d.bigInt()
+	tx.R = d.bigInt()
+	tx.S = d.bigInt()
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if tx.Type == LegacyTxType && tx.V.Cmp(big.NewInt(35)) >= 0 {
+		// EIP-155: v = chainID * 2 + 35 + recovery id
+		chainID := new(big.Int).Sub(tx.V, big.NewInt(35))
+		tx.ChainID = chainID.Rsh(chainID, 1)
+	}
+	return tx, nil
+}
+
+// payloadFields returns the encoded fields shared by the signing payload and the signed transaction.
+func (tx *Transaction) payloadFields() ([][]byte, error) {
+	for _, i := range []*big.Int{tx.ChainID, tx.GasPrice, tx.GasTipCap, tx.GasFeeCap, tx.Value} {
+		if i != nil && i.Sign() < 0 {
+			return nil, ErrNegativeQuantity
+		}
+	}
+
+	var fields [][]byte
+	switch tx.Type {
+	case LegacyTxType:
+		fields = append(fields, rlp.EncodeUint(tx.Nonce), encodeBigInt(tx.GasPrice))
+	case AccessListTxType, DynamicFeeTxType:
+		if tx.ChainID == nil || tx.ChainID.Sign() <= 0 {
+			return nil, ErrInvalidChainID
+		}
+		fields = append(fields, encodeBigInt(tx.ChainID), rlp.EncodeUint(tx.Nonce))
+		if tx.Type == AccessListTxType {
+			fields = append(fields, encodeBigInt(tx.GasPrice))
+		} else {
+			fields = append(fields, encodeBigInt(tx.GasTipCap), encodeBigInt(tx.GasFeeCap))
+		}
+	default:
+		return nil, ErrUnsupportedTxType
+	}
+
+	to := rlp.EncodeBytes(nil)
+	if tx.To != nil {
+		to = rlp.EncodeBytes(tx.To[:])
+	}
+	fields = append(fields, rlp.EncodeUint(tx.Gas), to, encodeBigInt(tx.Value), rlp.EncodeBytes(tx.Data))
+	if tx.Type != LegacyTxType {
+		fields = append(fields, tx.AccessList.encode())
+	}
+	return fields, nil
+}
+
+// envelope prefixes the payload of a typed transaction with its type.
+func (tx *Transaction) envelope(payload []byte) []byte {
+	if tx.Type == LegacyTxType {
+		return payload
+	}
+	return append([]byte{byte(tx.Type)}, payload...)
+}
+
+func (tx *Transaction) isProtected() bool {
+	return tx.ChainID != nil && tx.ChainID.Sign() > 0
+}
+
+func (tx *Transaction) recoveryID() (int, error) {
+	v := new(big.Int).Set(tx.V)
+	if tx.Type == LegacyTxType {
+		if tx.isProtected() {
+			v.Sub(v, new(big.Int).Lsh(tx.ChainID, 1))
+			v.Sub(v, big.NewInt(35))
+		} else {
+			v.Sub(v, big.NewInt(27))
+		}
+	}
+	if !v.IsInt64() || (v.Int64() != 0 && v.Int64() != 1) {
+		return 0, ErrInvalidSignature
+	}
+	return int(v.Int64()), nil
+}
+
+func (l AccessList) encode() []byte {
+	tuples := make([][]byte, 0, len(l))
+	for _, tuple := range l {
+		keys := make([][]byte, 0, len(tuple.StorageKeys))
+		for _, key := range tuple.StorageKeys {
+			keys = append(keys, rlp.EncodeBytes(key[:]))
+		}
+		tuples = append(tuples, rlp.EncodeList(rlp.EncodeBytes(tuple.Address[:]), rlp.EncodeList(keys...)))
+	}
+	return rlp.EncodeList(tuples...)
+}
+
+// encodeBigInt encodes a quantity already checked to be non negative.
+func encodeBigInt(i *big.Int) []byte {
+	encoded, _ := rlp.EncodeBigInt(i)
+	return encoded
+}
+
+// fieldDecoder decodes the transaction fields in order and keeps the first error.
+type fieldDecoder struct {
+	fields []rlp.Item
+	err    error
+}
+
+func (d *fieldDecoder) next() rlp.Item {
+	item := d.fields[0]
+	d.fields = d.fields[1:]
+	return item
+}
+
+func (d *fieldDecoder) setErr(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *fieldDecoder) uint64() uint64 {
+	i, err := d.next().Uint64()
+	d.setErr(err)
+	return i
+}
+
+func (d *fieldDecoder) bigInt() *big.Int {
+	i, err := d.next().BigInt()
+	d.setErr(err)
+	return i
+}
+
+func (d *fieldDecoder) bytes() []byte {
+	item := d.next()
+	if item.IsList {
+		d.setErr(rlp.ErrExpectedString)
+	}
+	return item.Bytes
+}
+
+func (d *fieldDecoder) address() *Address {
+	b := d.bytes()
+	switch len(b) {
+	case 0:
+		return nil
+	case AddressLength:
+		a := BytesToAddress(b)
+		return &a
+	}
+	d.setErr(ErrInvalidAddressLength)
+	return nil
+}
+
+func (d *fieldDecoder) accessList() AccessList {
+	item := d.next()
+	if !item.IsList {
+		d.setErr(rlp.ErrExpectedList)
+		return nil
+	}
+	list := AccessList{}
+	for _, tuple := range item.List {
+		if !tuple.IsList || len(tuple.List) != 2 || !tuple.List[1].IsList {
+			d.setErr(ErrInvalidTransaction)
+			return nil
+		}
+		tupleDecoder := &fieldDecoder{fields: tuple.List[:1]}
+		address := tupleDecoder.address()
+		if tupleDecoder.err != nil || address == nil {
+			d.setErr(ErrInvalidAddressLength)
+			return nil
+		}
+		accessTuple := AccessTuple{Address: *address}
+		for _, key := range tuple.List[1].List {
+			if key.IsList || len(key.Bytes) != 32 {
+				d.setErr(ErrInvalidTransaction)
+				return nil
+			}
+			var storageKey [32]byte
+			copy(storageKey[:], key.Bytes)
+			accessTuple.StorageKeys = append(accessTuple.StorageKeys, storageKey)
+		}
+		list = append(list, accessTuple)
+	}
+	return list
+}
@@ -0,0 +1,165 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+func TestTransactionSign(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	seckey, _ := hex.DecodeString("4646464646464646464646464646464646464646464646464646464646464646")
+	sender, _ := ParseAddress("0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F")
+	to, _ := ParseAddress("0x3535353535353535353535353535353535353535")
+	ether, _ := new(big.Int).SetString("1000000000000000000", 10)
+
+	tests := []struct {
+		name        string
+		tx          *Transaction
+		signingHash string
+		raw         string
+	}{
+		{
+			// https://eips.ethereum.org/EIPS/eip-155
+			name: "eip-155",
+			tx: &Transaction{
+				Type: LegacyTxType, ChainID: big.NewInt(1), Nonce: 9, GasPrice: big.NewInt(20000000000),
+				Gas: 21000, To: &to, Value: ether,
+			},
+			signingHash: "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53",
+			raw:         "f86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+		},
+		{
+			name: "eip-2930",
+			tx: &Transaction{
+				Type: AccessListTxType, ChainID: big.NewInt(1), Nonce: 3, GasPrice: big.NewInt(30000000000),
+				Gas: 50000, To: &to, Value: big.NewInt(12345), Data: []byte{0xde, 0xad, 0xbe, 0xef},
+				AccessList: AccessList{{Address: to, StorageKeys: [][32]byte{{31: 1}, {31: 2}}}},
+			},
+			raw: "01f8c801038506fc23ac0082c35094353535353535353535353535353535353535353582303984deadbeeff85bf859943535353535353535353535353535353535353535f842a00000000000000000000000000000000000000000000000000000000000000001a0000000000000000000000000000000000000000000000000000000000000000280a0b4626b737072aa8b00b227ef86314cf1abaf2e124e510cdb2585212515f9c6c2a0662cb95ba9dea8f9b30a532c925662e5f691a0b3f809df33a1a61386a6fea55c",
+		},
+		{
+			name: "eip-1559",
+			tx: &Transaction{
+				Type: DynamicFeeTxType, ChainID: big.NewInt(5), Nonce: 7, GasTipCap: big.NewInt(2000000000),
+				GasFeeCap: big.NewInt(100000000000), Gas: 21000, To: &to, Value: ether,
+			},
+			raw: "02f8730507847735940085174876e800825208943535353535353535353535353535353535353535880de0b6b3a764000080c080a0746314ee8bd338e25ebe3f31e30c027a10f9f16dc3cc9d3da2bda76bac891cf1a017b92c20f57fb7eb8d4d7d60c112c22b7c9c5dfe0f22ac80db53c7935b15bf86",
+		},
+		{
+			name: "eip-1559 contract creation",
+			tx: &Transaction{
+				Type: DynamicFeeTxType, ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(10),
+				Gas: 100000, Data: []byte{0x60, 0x00},
+			},
+			raw: "02f8510180010a830186a08080826000c080a0f20f4d8853d45f6be704f1b0a7a7f093dc922e2750750351eac76ae967f698c6a00af39546e4657fc7acd3e33dc392ad566675e76f12f0071a3a89fd58dedf7f9a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.signingHash != "" {
+				hash, err := tt.tx.SigningHash()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if hex.EncodeToString(hash) != tt.signingHash {
+					t.Errorf("SigningHash() = %x, want %s", hash, tt.signingHash)
+				}
+			}
+			if err := tt.tx.Sign(ctx, seckey); err != nil {
+				t.Fatal(err)
+			}
+			raw, err := tt.tx.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(raw) != tt.raw {
+				t.Errorf("MarshalBinary() = %x, want %s", raw, tt.raw)
+			}
+
+			decoded, err := DecodeTransaction(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reencoded, err := decoded.MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(reencoded) != tt.raw {
+				t.Errorf("DecodeTransaction() round trip = %x, want %s", reencoded, tt.raw)
+			}
+			if from, err := decoded.Sender(ctx); err != nil || from != sender {
+				t.Errorf("Sender() = %s, %v, want %s", from, err, sender)
+			}
+		})
+	}
+}
+
+func TestTransactionUnprotected(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	seckey, _ := hex.DecodeString("4646464646464646464646464646464646464646464646464646464646464646")
+	tx := &Transaction{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000, Value: big.NewInt(1)}
+	if err := tx.Sign(ctx, seckey); err != nil {
+		t.Fatal(err)
+	}
+	if tx.V.Int64() != 27 && tx.V.Int64() != 28 {
+		t.Errorf("V = %d, want 27 or 28", tx.V)
+	}
+	raw, _ := tx.MarshalBinary()
+	decoded, err := DecodeTransaction(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ChainID != nil {
+		t.Errorf("ChainID = %d, want nil", decoded.ChainID)
+	}
+	if from, err := decoded.Sender(ctx); err != nil || from.Hex() != "0x9d8A62f656a8d1615C1294fd71e9CFb3E4855A4F" {
+		t.Errorf("Sender() = %s, %v", from, err)
+	}
+
+	// a high s is malleable and rejected
+	decoded.S.Sub(secp256k1N, decoded.S)
+	if _, err := decoded.Sender(ctx); err != ErrInvalidSignature {
+		t.Errorf("Sender() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestTransactionInvalid(t *testing.T) {
+	if _, err := (&Transaction{Type: DynamicFeeTxType}).SigningHash(); err != ErrInvalidChainID {
+		t.Errorf("SigningHash() error = %v, want %v", err, ErrInvalidChainID)
+	}
+	if _, err := (&Transaction{Type: 3, ChainID: big.NewInt(1)}).SigningHash(); err != ErrUnsupportedTxType {
+		t.Errorf("SigningHash() error = %v, want %v", err, ErrUnsupportedTxType)
+	}
+	if _, err := (&Transaction{Value: big.NewInt(-1)}).SigningHash(); err != ErrNegativeQuantity {
+		t.Errorf("SigningHash() error = %v, want %v", err, ErrNegativeQuantity)
+	}
+	if _, err := (&Transaction{}).MarshalBinary(); err != ErrUnsignedTx {
+		t.Errorf("MarshalBinary() error = %v, want %v", err, ErrUnsignedTx)
+	}
+
+	invalid := []string{
+		"",
+		"03c0",
+		"02c3010203",
+		"f84b098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276",
+	}
+	for _, raw := range invalid {
+		b, _ := hex.DecodeString(raw)
+		if _, err := DecodeTransaction(b); err == nil {
+			t.Errorf("DecodeTransaction(%s) should fail", raw)
+		}
+	}
+}
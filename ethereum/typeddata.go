@@ -0,0 +1,440 @@
+package ethereum
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var (
+	ErrUnknownType      = errors.New("eip712: unknown type")
+	ErrInvalidTypeValue = errors.New("eip712: value does not match its type")
+	ErrMissingDomain    = errors.New("eip712: missing domain")
+)
+
+// domainType is the EIP-712 domain struct name.
+const domainType = "EIP712Domain"
+
+// domainFields are the domain fields in the order the specification lists them, used when
+// the types do not declare EIP712Domain.
+var domainFields = []TypedDataField{
+	{Name: "name", Type: "string"},
+	{Name: "version", Type: "string"},
+	{Name: "chainId", Type: "uint256"},
+	{Name: "verifyingContract", Type: "address"},
+	{Name: "salt", Type: "bytes32"},
+}
+
+var (
+	arrayTypeRegexp = regexp.MustCompile(`^(.+)\[(\d*)\]$`)
+	intTypeRegexp   = regexp.MustCompile(`^(u?)int(\d*)$`)
+	bytesTypeRegexp = regexp.MustCompile(`^bytes(\d+)$`)
+)
+
+// TypedDataField is a member of a struct type.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is the eth_signTypedData_v4 payload, it can be decoded from its JSON form.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// ParseTypedData decodes the JSON typed data, numbers are kept as json.Number.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var td TypedData
+	if err := decoder.Decode(&td); err != nil {
+		return nil, err
+	}
+	return &td, nil
+}
+
+// SigningHash returns Keccak256("\x19\x01" || domainSeparator || hashStruct(message)).
+func (td *TypedData) SigningHash() ([]byte, error) {
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, err
+	}
+	return Keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash), nil
+}
+
+// DomainSeparator returns hashStruct(domain).
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	if td.Domain == nil {
+		return nil, ErrMissingDomain
+	}
+	return td.HashStruct(domainType, td.Domain)
+}
+
+// HashStruct returns Keccak256(typeHash || encodeData(data)) of a struct type.
+func (td *TypedData) HashStruct(typeName string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := td.EncodeData(typeName, data)
+	if err != nil {
+		return nil, err
+	}
+	return Keccak256(encoded), nil
+}
+
+// TypeHash returns Keccak256(encodeType(typeName)).
+func (td *TypedData) TypeHash(typeName string) ([]byte, error) {
+	encoded, err := td.EncodeType(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return Keccak256([]byte(encoded)), nil
+}
+
+// EncodeType returns the type followed by the struct types it references sorted by name,
+// such as "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+func (td *TypedData) EncodeType(typeName string) (string, error) {
+	if _, err := td.fields(typeName); err != nil {
+		return "", err
+	}
+	deps := map[string]bool{}
+	td.dependencies(typeName, deps)
+	delete(deps, typeName)
+
+	names := make([]string, 0, len(deps))
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range append([]string{typeName}, names...) {
+		fields, _ := td.fields(name)
+		b.WriteString(name)
+		b.WriteByte('(')
+		for i, field := range fields {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(field.Type)
+			b.WriteByte(' ')
+			b.WriteString(field.Name)
+		}
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// EncodeData returns typeHash || the 32 bytes encoding of each member, a missing member is encoded as zero.
+func (td *TypedData) EncodeData(typeName string, data map[string]interface{}) ([]byte, error) {
+	fields, err := td.fields(typeName)
+	if err != nil {
+		return nil, err
+	}
+	typeHash, err := td.TypeHash(typeName)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := typeHash
+	for _, field := range fields {
+		value, err := td.encodeValue(field.Type, data[field.Name])
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Name, err)
+		}
+		encoded = append(encoded, value...)
+	}
+	return encoded, nil
+}
+
+// fields returns the members of a struct type, the domain type is derived from the domain when not declared.
+func (td *TypedData) fields(typeName string) ([]TypedDataField, error) {
+	if fields, ok := td.Types[typeName]; ok {
+		return fields, nil
+	}
+	if typeName != domainType {
+		return nil, fmt.Errorf("%w %q", ErrUnknownType, typeName)
+	}
+	var fields []TypedDataField
+	for _, field := range domainFields {
+		if _, ok := td.Domain[field.Name]; ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func (td *TypedData) dependencies(typeName string, deps map[string]bool) {
+	if deps[typeName] {
+		return
+	}
+	fields, err := td.fields(typeName)
+	if err != nil {
+		return
+	}
+	deps[typeName] = true
+	for _, field := range fields {
+		td.dependencies(baseType(field.Type), deps)
+	}
+}
+
+// encodeValue returns the 32 bytes encoding of a member value.
+func (td *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if match := arrayTypeRegexp.FindStringSubmatch(typ); match != nil {
+		return td.encodeArray(match[1], match[2], value)
+	}
+	if _, ok := td.Types[typ]; ok || typ == domainType {
+		if value == nil {
+			return make([]byte, 32), nil
+		}
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, ErrInvalidTypeValue
+		}
+		return td.HashStruct(typ, data)
+	}
+	return encodeAtomic(typ, value)
+}
+
+// encodeArray returns the Keccak-256 hash of the concatenated member encodings.
+func (td *TypedData) encodeArray(elemType, length string, value interface{}) ([]byte, error) {
+	var items []interface{}
+	switch v := value.(type) {
+	case nil:
+	case []interface{}:
+		items = v
+	case []map[string]interface{}:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case []string:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	default:
+		return nil, ErrInvalidTypeValue
+	}
+	if length != "" {
+		if n, err := strconv.Atoi(length); err != nil || n != len(items) {
+			return nil, ErrInvalidTypeValue
+		}
+	}
+
+	var encoded []byte
+	for _, item := range items {
+		b, err := td.encodeValue(elemType, item)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, b...)
+	}
+	return Keccak256(encoded), nil
+}
+
+// encodeAtomic encodes the atomic and dynamic types.
+func encodeAtomic(typ string, value interface{}) ([]byte, error) {
+	switch typ {
+	case "string":
+		switch v := value.(type) {
+		case nil:
+			return Keccak256(nil), nil
+		case string:
+			return Keccak256([]byte(v)), nil
+		}
+		return nil, ErrInvalidTypeValue
+	case "bytes":
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return Keccak256(b), nil
+	case "bool":
+		encoded := make([]byte, 32)
+		switch v := value.(type) {
+		case nil:
+		case bool:
+			if v {
+				encoded[31] = 1
+			}
+		default:
+			return nil, ErrInvalidTypeValue
+		}
+		return encoded, nil
+	case "address":
+		encoded := make([]byte, 32)
+		switch v := value.(type) {
+		case nil:
+		case Address:
+			copy(encoded[12:], v[:])
+		case string:
+			a, err := ParseAddress(v)
+			if err != nil {
+				return nil, err
+			}
+			copy(encoded[12:], a[:])
+		default:
+			return nil, ErrInvalidTypeValue
+		}
+		return encoded, nil
+	}
+
+	if match := bytesTypeRegexp.FindStringSubmatch(typ); match != nil {
+		size, _ := strconv.Atoi(match[1])
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if size < 1 || size > 32 || len(b) > size {
+			return nil, ErrInvalidTypeValue
+		}
+		// bytesN is right padded
+		encoded := make([]byte, 32)
+		copy(encoded, b)
+		return encoded, nil
+	}
+
+	if match := intTypeRegexp.FindStringSubmatch(typ); match != nil {
+		bits := 256
+		if match[2] != "" {
+			bits, _ = strconv.Atoi(match[2])
+		}
+		if bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("%w %q", ErrUnknownType, typ)
+		}
+		i, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeInt(i, match[1] == "u", bits)
+	}
+	return nil, fmt.Errorf("%w %q", ErrUnknownType, typ)
+}
+
+// encodeInt returns the 32 bytes two's complement encoding of an integer in the range of the type.
+func encodeInt(i *big.Int, unsigned bool, bits int) ([]byte, error) {
+	var min, max *big.Int
+	if unsigned {
+		min = new(big.Int)
+		max = new(big.Int).Lsh(big.NewInt(1), uint(bits))
+	} else {
+		max = new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		min = new(big.Int).Neg(max)
+	}
+	if i.Cmp(min) < 0 || i.Cmp(max) >= 0 {
+		return nil, ErrInvalidTypeValue
+	}
+
+	encoded := make([]byte, 32)
+	if i.Sign() < 0 {
+		i = new(big.Int).Add(i, new(big.Int).Lsh(big.NewInt(1), 256))
+	}
+	i.FillBytes(encoded)
+	return encoded, nil
+}
+
+// toBigInt converts a JSON number, a decimal or 0x hex string or a Go integer.
+func toBigInt(value interface{}) (*big.Int, error) {
+	switch v := value.(type) {
+	case nil:
+		return new(big.Int), nil
+	case *big.Int:
+		return v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, ErrInvalidTypeValue
+		}
+		return big.NewInt(int64(v)), nil
+	case json.Number:
+		return parseInteger(string(v))
+	case string:
+		return parseInteger(v)
+	}
+	return nil, ErrInvalidTypeValue
+}
+
+func parseInteger(s string) (*big.Int, error) {
+	i, ok := new(big.Int), false
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		i, ok = i.SetString(s[2:], 16)
+	} else {
+		i, ok = i.SetString(s, 10)
+	}
+	if !ok {
+		return nil, ErrInvalidTypeValue
+	}
+	return i, nil
+}
+
+// toBytes converts a 0x hex string or a byte slice.
+func toBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		if !strings.HasPrefix(v, "0x") {
+			return nil, ErrInvalidTypeValue
+		}
+		b, err := hex.DecodeString(v[2:])
+		if err != nil {
+			return nil, ErrInvalidTypeValue
+		}
+		return b, nil
+	}
+	return nil, ErrInvalidTypeValue
+}
+
+// baseType strips the array suffixes of a type.
+func baseType(typ string) string {
+	for {
+		match := arrayTypeRegexp.FindStringSubmatch(typ)
+		if match == nil {
+			return typ
+		}
+		typ = match[1]
+	}
+}
+
+// SignTypedData signs the typed data as eth_signTypedData_v4 does.
+func SignTypedData(ctx *secp256k1.Context, td *TypedData, seckey []byte) ([]byte, error) {
+	hash, err := td.SigningHash()
+	if err != nil {
+		return nil, err
+	}
+	return SignHash(ctx, hash, seckey)
+}
+
+// RecoverTypedData returns the address that signed the typed data.
+func RecoverTypedData(ctx *secp256k1.Context, td *TypedData, sig []byte) (Address, error) {
+	hash, err := td.SigningHash()
+	if err != nil {
+		return Address{}, err
+	}
+	return Ecrecover(ctx, hash, sig)
+}
+
+// VerifyTypedData reports whether the address signed the typed data.
+func VerifyTypedData(ctx *secp256k1.Context, address Address, td *TypedData, sig []byte) bool {
+	signer, err := RecoverTypedData(ctx, td, sig)
+	return err == nil && signer == address
+}
@@ -0,0 +1,210 @@
+package ethereum
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// mailTypedData is the example of https://eips.ethereum.org/EIPS/eip-712
+const mailTypedData = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+func TestTypedDataMail(t *testing.T) {
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer secp256k1.ContextDestroy(ctx)
+
+	td, err := ParseTypedData([]byte(mailTypedData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encodedType, _ := td.EncodeType("Mail")
+	if encodedType != "Mail(Person from,Person to,string contents)Person(string name,address wallet)" {
+		t.Errorf("EncodeType() = %s", encodedType)
+	}
+	typeHash, _ := td.TypeHash("Mail")
+	if hex.EncodeToString(typeHash) != "a0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2" {
+		t.Errorf("TypeHash() = %x", typeHash)
+	}
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(domainSeparator) != "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f" {
+		t.Errorf("DomainSeparator() = %x", domainSeparator)
+	}
+	messageHash, err := td.HashStruct(td.PrimaryType, td.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(messageHash) != "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e" {
+		t.Errorf("HashStruct() = %x", messageHash)
+	}
+	hash, err := td.SigningHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(hash) != "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2" {
+		t.Errorf("SigningHash() = %x", hash)
+	}
+
+	seckey := Keccak256([]byte("cow"))
+	signer, _ := ParseAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	sig, err := SignTypedData(ctx, td, seckey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b915621c"
+	if hex.EncodeToString(sig) != want {
+		t.Errorf("SignTypedData() = %x, want %s", sig, want)
+	}
+	if a, err := RecoverTypedData(ctx, td, sig); err != nil || a != signer {
+		t.Errorf("RecoverTypedData() = %s, %v, want %s", a, err, signer)
+	}
+	if !VerifyTypedData(ctx, signer, td, sig) {
+		t.Errorf("VerifyTypedData() = false")
+	}
+
+	td.Message["contents"] = "Hello, Alice!"
+	if VerifyTypedData(ctx, signer, td, sig) {
+		t.Errorf("VerifyTypedData() of another message = true")
+	}
+}
+
+// nestedTypedData uses struct arrays, fixed size and nested arrays, negative integers and
+// dynamic bytes, with a domain of a salt and no verifying contract
+const nestedTypedData = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "salt", "type": "bytes32"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallets", "type": "address[]"}
+    ],
+    "Group": [
+      {"name": "name", "type": "string"},
+      {"name": "members", "type": "Person[]"}
+    ],
+    "Order": [
+      {"name": "maker", "type": "Person"},
+      {"name": "groups", "type": "Group[2]"},
+      {"name": "amounts", "type": "int64[][]"},
+      {"name": "payload", "type": "bytes"},
+      {"name": "tag", "type": "bytes4"},
+      {"name": "active", "type": "bool"}
+    ]
+  },
+  "primaryType": "Order",
+  "domain": {
+    "name": "Orders",
+    "chainId": "0x89",
+    "salt": "0x00000000000000000000000000000000000000000000000000000000000000ff"
+  },
+  "message": {
+    "maker": {"name": "Cow", "wallets": ["0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", "0xDeaDbeefdEAdbeefdEadbEEFdeadbeEFdEaDbeeF"]},
+    "groups": [
+      {"name": "Farm", "members": [{"name": "Bob", "wallets": ["0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"]}, {"name": "Alice", "wallets": []}]},
+      {"name": "Empty", "members": []}
+    ],
+    "amounts": [[1, -2, 3], [], ["0x7fffffffffffffff"]],
+    "payload": "0xdeadbeef",
+    "tag": "0xcafe",
+    "active": true
+  }
+}`
+
+func TestTypedDataNested(t *testing.T) {
+	td, err := ParseTypedData([]byte(nestedTypedData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encodedType, _ := td.EncodeType("Order")
+	want := "Order(Person maker,Group[2] groups,int64[][] amounts,bytes payload,bytes4 tag,bool active)" +
+		"Group(string name,Person[] members)Person(string name,address[] wallets)"
+	if encodedType != want {
+		t.Errorf("EncodeType() = %s, want %s", encodedType, want)
+	}
+	domainSeparator, _ := td.DomainSeparator()
+	if hex.EncodeToString(domainSeparator) != "43edbafa8bacb48894ee4ce8febb4e3005a2016bf58700256f5eb860ba3bdfb3" {
+		t.Errorf("DomainSeparator() = %x", domainSeparator)
+	}
+	hash, err := td.SigningHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(hash) != "2b40fa7f7134310775b574f0eb859110acfe14ca9b47f65f33b7369cfc318f47" {
+		t.Errorf("SigningHash() = %x", hash)
+	}
+
+	// the domain type is derived from the domain when it is not declared
+	delete(td.Types, "EIP712Domain")
+	if derived, _ := td.DomainSeparator(); hex.EncodeToString(derived) != hex.EncodeToString(domainSeparator) {
+		t.Errorf("DomainSeparator() = %x, want %x", derived, domainSeparator)
+	}
+}
+
+func TestTypedDataInvalid(t *testing.T) {
+	td, _ := ParseTypedData([]byte(mailTypedData))
+	td.Types["Mail"] = append(td.Types["Mail"], TypedDataField{Name: "attachment", Type: "Attachment"})
+	if _, err := td.SigningHash(); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("SigningHash() error = %v, want %v", err, ErrUnknownType)
+	}
+
+	tests := []struct {
+		typ   string
+		value interface{}
+	}{
+		{"uint8", 256},
+		{"int8", -129},
+		{"uint256", "0xzz"},
+		{"bytes4", "0x0102030405"},
+		{"bytes", "0102"},
+		{"bool", "true"},
+		{"address", "0x01"},
+		{"string[2]", []interface{}{"a"}},
+	}
+	for _, tt := range tests {
+		if _, err := (&TypedData{}).encodeValue(tt.typ, tt.value); err == nil {
+			t.Errorf("encodeValue(%s, %v) should fail", tt.typ, tt.value)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/bitcoin"
+)
+
+// Bip32Derivation is the master key fingerprint and the derivation path of a public key.
+type Bip32Derivation struct {
+	PubKey      []byte
+	Fingerprint [4]byte
+	Path        []uint32
+}
+
+// XPub is a global extended public key with the master key fingerprint and path it derives from.
+type XPub struct {
+	ExtendedKey []byte // the 78 bytes serialization
+	Fingerprint [4]byte
+	Path        []uint32
+}
+
+// MasterFingerprint returns the fingerprint of a master key, the first 4 bytes of the hash160 of its public key.
+func MasterFingerprint(key *bip32.PrivateKey) ([4]byte, error) {
+	var fp [4]byte
+	hash, err := bip32.Hash160(key.ToPublicKeyBytes())
+	if err != nil {
+		return fp, err
+	}
+	copy(fp[:], hash)
+	return fp, nil
+}
+
+// DerivationsFor returns the derivations of the input from the master key fingerprint.
+func (in *Input) DerivationsFor(fingerprint [4]byte) []*Bip32Derivation {
+	return derivationsFor(in.Bip32Derivations, fingerprint)
+}
+
+// DerivationsFor returns the derivations of the output from the master key fingerprint.
+func (out *Output) DerivationsFor(fingerprint [4]byte) []*Bip32Derivation {
+	return derivationsFor(out.Bip32Derivations, fingerprint)
+}
+
+// AddBip32Derivation adds the derivation, replacing the one of the same public key.
+func (in *Input) AddBip32Derivation(d *Bip32Derivation) {
+	in.Bip32Derivations = addDerivation(in.Bip32Derivations, d)
+}
+
+// AddBip32Derivation adds the derivation, replacing the one of the same public key.
+func (out *Output) AddBip32Derivation(d *Bip32Derivation) {
+	out.Bip32Derivations = addDerivation(out.Bip32Derivations, d)
+}
+
+func derivationsFor(derivations []*Bip32Derivation, fingerprint [4]byte) []*Bip32Derivation {
+	var matched []*Bip32Derivation
+	for _, d := range derivations {
+		if d.Fingerprint == fingerprint {
+			matched = append(matched, d)
+		}
+	}
+	return matched
+}
+
+func addDerivation(derivations []*Bip32Derivation, d *Bip32Derivation) []*Bip32Derivation {
+	for i, existing := range derivations {
+		if bytes.Equal(existing.PubKey, d.PubKey) {
+			derivations[i] = d
+			return derivations
+		}
+	}
+	return append(derivations, d)
+}
+
+func parseBip32Derivation(pr pair) (*Bip32Derivation, error) {
+	fp, path, err := parseKeyOrigin(pr.value)
+	if err != nil {
+		return nil, err
+	}
+	return &Bip32Derivation{PubKey: pr.keyData(), Fingerprint: fp, Path: path}, nil
+}
+
+func parseXPub(pr pair) (*XPub, error) {
+	if len(pr.keyData()) != bip32.SerializedKeyLen {
+		return nil, ErrInvalidKey
+	}
+	fp, path, err := parseKeyOrigin(pr.value)
+	if err != nil {
+		return nil, err
+	}
+	return &XPub{ExtendedKey: pr.keyData(), Fingerprint: fp, Path: path}, nil
+}
+
+// parseKeyOrigin decodes the fingerprint followed by the little endian path indexes.
+func parseKeyOrigin(value []byte) ([4]byte, []uint32, error) {
+	var fp [4]byte
+	if len(value) < len(fp) || len(value)%4 != 0 {
+		return fp, nil, ErrInvalidValue
+	}
+	copy(fp[:], value)
+	path := make([]uint32, 0, len(value)/4-1)
+	for i := 4; i < len(value); i += 4 {
+		path = append(path, binary.LittleEndian.Uint32(value[i:]))
+	}
+	return fp, path, nil
+}
+
+func serializeKeyOrigin(fp [4]byte, path []uint32) []byte {
+	b := append([]byte{}, fp[:]...)
+	for _, index := range path {
+		b = bitcoin.AppendUint32(b, index)
+	}
+	return b
+}
+
+func appendDerivations(pairs []pair, keyType byte, derivations []*Bip32Derivation) []pair {
+	sorted := append([]*Bip32Derivation{}, derivations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].PubKey, sorted[j].PubKey) < 0 })
+	for _, d := range sorted {
+		pairs = append(pairs, pair{append([]byte{keyType}, d.PubKey...), serializeKeyOrigin(d.Fingerprint, d.Path)})
+	}
+	return pairs
+}
@@ -0,0 +1,149 @@
+package psbt
+
+import (
+	"bytes"
+	"errors"
+)
+
+var ErrNoPackets = errors.New("no packets to combine")
+
+// Combine merges packets of the same transaction, e.g. signed by different signers,
+// into a new packet. The first packet wins when packets disagree on a field.
+func Combine(packets ...*Packet) (*Packet, error) {
+	if len(packets) == 0 {
+		return nil, ErrNoPackets
+	}
+	combined, err := packets[0].clone()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := combined.UnsignedTx()
+	if err != nil {
+		return nil, err
+	}
+	for _, packet := range packets[1:] {
+		other, err := packet.clone()
+		if err != nil {
+			return nil, err
+		}
+		otherTx, err := other.UnsignedTx()
+		if err != nil {
+			return nil, err
+		}
+		if other.Version != combined.Version || otherTx.TxHash() != tx.TxHash() {
+			return nil, ErrTransactionMismatch
+		}
+		combined.merge(other)
+	}
+	return combined, nil
+}
+
+// clone returns a deep copy through the serialization.
+func (p *Packet) clone() (*Packet, error) {
+	b, err := p.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return Parse(b)
+}
+
+func (p *Packet) merge(other *Packet) {
+	if p.TxModifiable == nil {
+		p.TxModifiable = other.TxModifiable
+	}
+	for _, xpub := range other.XPubs {
+		found := false
+		for _, existing := range p.XPubs {
+			found = found || bytes.Equal(existing.ExtendedKey, xpub.ExtendedKey)
+		}
+		if !found {
+			p.XPubs = append(p.XPubs, xpub)
+		}
+	}
+	p.Unknowns = mergeUnknowns(p.Unknowns, other.Unknowns)
+
+	for i, in := range p.Inputs {
+		in.merge(other.Inputs[i])
+	}
+	for i, out := range p.Outputs {
+		out.merge(other.Outputs[i])
+	}
+}
+
+func (in *Input) merge(other *Input) {
+	if in.NonWitnessUtxo == nil {
+		in.NonWitnessUtxo = other.NonWitnessUtxo
+	}
+	if in.WitnessUtxo == nil {
+		in.WitnessUtxo = other.WitnessUtxo
+	}
+	if in.SighashType == 0 {
+		in.SighashType = other.SighashType
+	}
+	if in.RedeemScript == nil {
+		in.RedeemScript = other.RedeemScript
+	}
+	if in.WitnessScript == nil {
+		in.WitnessScript = other.WitnessScript
+	}
+	if in.FinalScriptSig == nil {
+		in.FinalScriptSig = other.FinalScriptSig
+	}
+	if in.FinalScriptWitness == nil {
+		in.FinalScriptWitness = other.FinalScriptWitness
+	}
+	if in.RequiredTimeLocktime == nil {
+		in.RequiredTimeLocktime = other.RequiredTimeLocktime
+	}
+	if in.RequiredHeightLocktime == nil {
+		in.RequiredHeightLocktime = other.RequiredHeightLocktime
+	}
+	for _, sig := range other.PartialSigs {
+		found := false
+		for _, existing := range in.PartialSigs {
+			found = found || bytes.Equal(existing.PubKey, sig.PubKey)
+		}
+		if !found {
+			in.PartialSigs = append(in.PartialSigs, sig)
+		}
+	}
+	in.Bip32Derivations = mergeDerivations(in.Bip32Derivations, other.Bip32Derivations)
+	in.Unknowns = mergeUnknowns(in.Unknowns, other.Unknowns)
+}
+
+func (out *Output) merge(other *Output) {
+	if out.RedeemScript == nil {
+		out.RedeemScript = other.RedeemScript
+	}
+	if out.WitnessScript == nil {
+		out.WitnessScript = other.WitnessScript
+	}
+	out.Bip32Derivations = mergeDerivations(out.Bip32Derivations, other.Bip32Derivations)
+	out.Unknowns = mergeUnknowns(out.Unknowns, other.Unknowns)
+}
+
+func mergeDerivations(derivations, other []*Bip32Derivation) []*Bip32Derivation {
+	for _, d := range other {
+		found := false
+		for _, existing := range derivations {
+			found = found || bytes.Equal(existing.PubKey, d.PubKey)
+		}
+		if !found {
+			derivations = append(derivations, d)
+		}
+	}
+	return derivations
+}
+
+func mergeUnknowns(unknowns, other []*Unknown) []*Unknown {
+	for _, u := range other {
+		found := false
+		for _, existing := range unknowns {
+			found = found || bytes.Equal(existing.Key, u.Key)
+		}
+		if !found {
+			unknowns = append(unknowns, u)
+		}
+	}
+	return unknowns
+}
@@ -0,0 +1,119 @@
+package psbt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dubuqingfeng/signer/bitcoin"
+)
+
+var (
+	ErrUnsupportedScript = errors.New("unsupported script to finalize")
+	ErrMissingSignature  = errors.New("not enough partial signatures to finalize")
+	ErrNotFinalized      = errors.New("input is not finalized")
+)
+
+// FinalizeInput builds the final script and witness of a P2PKH, P2WPKH, P2SH-P2WPKH or
+// multisig (bare, P2SH, P2WSH, P2SH-P2WSH) input from its partial signatures, and
+// removes the fields only signers need.
+func (p *Packet) FinalizeInput(idx int) error {
+	if idx < 0 || idx >= len(p.Inputs) {
+		return ErrInputIndex
+	}
+	in := p.Inputs[idx]
+	if in.isFinalized() {
+		return nil
+	}
+	s, err := in.resolve()
+	if err != nil {
+		return err
+	}
+
+	var scriptSig []byte
+	var witness [][]byte
+	if s.keyHash != nil {
+		var sig *PartialSig
+		for _, candidate := range in.PartialSigs {
+			if bytes.Equal(hash160(candidate.PubKey), s.keyHash) {
+				sig = candidate
+				break
+			}
+		}
+		if sig == nil {
+			return ErrMissingSignature
+		}
+		if s.witness {
+			witness = [][]byte{sig.Signature, sig.PubKey}
+		} else {
+			scriptSig = pushData(pushData(nil, sig.Signature), sig.PubKey)
+		}
+	} else {
+		m, pubKeys, ok := parseMultisig(s.scriptCode)
+		if !ok {
+			return ErrUnsupportedScript
+		}
+		// signatures in the order of the public keys, OP_CHECKMULTISIG pops an extra item
+		sigs := [][]byte{{}}
+		for _, pubKey := range pubKeys {
+			for _, sig := range in.PartialSigs {
+				if len(sigs) <= m && bytes.Equal(sig.PubKey, pubKey) {
+					sigs = append(sigs, sig.Signature)
+				}
+			}
+		}
+		if len(sigs) <= m {
+			return ErrMissingSignature
+		}
+		if s.witness {
+			witness = append(sigs, s.scriptCode)
+		} else {
+			for _, sig := range sigs {
+				scriptSig = pushData(scriptSig, sig)
+			}
+		}
+	}
+	if s.redeemScript != nil {
+		scriptSig = pushData(scriptSig, s.redeemScript)
+	}
+
+	in.FinalScriptSig, in.FinalScriptWitness = scriptSig, witness
+	in.PartialSigs, in.SighashType, in.RedeemScript, in.WitnessScript, in.Bip32Derivations = nil, 0, nil, nil, nil
+	return nil
+}
+
+// Finalize finalizes every input.
+func (p *Packet) Finalize() error {
+	for i := range p.Inputs {
+		if err := p.FinalizeInput(i); err != nil {
+			return fmt.Errorf("input %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// IsComplete reports whether every input is finalized.
+func (p *Packet) IsComplete() bool {
+	for _, in := range p.Inputs {
+		if !in.isFinalized() {
+			return false
+		}
+	}
+	return true
+}
+
+// Extract returns the signed transaction of a finalized packet.
+func (p *Packet) Extract() (*bitcoin.Transaction, error) {
+	tx, err := p.UnsignedTx()
+	if err != nil {
+		return nil, err
+	}
+	for i, in := range p.Inputs {
+		if !in.isFinalized() {
+			return nil, fmt.Errorf("input %d: %w", i, ErrNotFinalized)
+		}
+		tx.TxIn[i].SignatureScript = append([]byte{}, in.FinalScriptSig...)
+		tx.TxIn[i].Witness = in.FinalScriptWitness
+	}
+	return tx, nil
+}
@@ -0,0 +1,80 @@
+package psbt
+
+import (
+	"github.com/dubuqingfeng/signer/bitcoin"
+)
+
+// pair is a key-value entry of a map, the key starts with its compact size type.
+type pair struct {
+	key   []byte
+	value []byte
+}
+
+func (p pair) keyType() uint64 {
+	return bitcoin.NewReader(p.key).VarInt()
+}
+
+// keyData returns the key without its type.
+func (p pair) keyData() []byte {
+	r := bitcoin.NewReader(p.key)
+	r.VarInt()
+	return r.Read(r.Len())
+}
+
+// appendMap appends the pairs and the map separator.
+func appendMap(b []byte, pairs []pair) []byte {
+	for _, p := range pairs {
+		b = bitcoin.AppendVarBytes(b, p.key)
+		b = bitcoin.AppendVarBytes(b, p.value)
+	}
+	return append(b, 0x00)
+}
+
+// readMap reads the pairs up to the separator and rejects duplicate keys.
+func readMap(r *bitcoin.Reader) ([]pair, error) {
+	var pairs []pair
+	seen := make(map[string]bool)
+	for {
+		key := r.VarBytes()
+		if r.Err() != nil {
+			return nil, r.Err()
+		}
+		if len(key) == 0 {
+			return pairs, nil
+		}
+		value := r.VarBytes()
+		if r.Err() != nil {
+			return nil, r.Err()
+		}
+		if seen[string(key)] {
+			return nil, ErrDuplicateKey
+		}
+		seen[string(key)] = true
+		pairs = append(pairs, pair{key: key, value: value})
+	}
+}
+
+// parseWitness decodes a witness stack, a count followed by the items.
+func parseWitness(data []byte) ([][]byte, error) {
+	r := bitcoin.NewReader(data)
+	n := r.Count(1)
+	witness := make([][]byte, 0, n)
+	for i := 0; i < n && r.Err() == nil; i++ {
+		witness = append(witness, r.VarBytes())
+	}
+	if r.Err() != nil {
+		return nil, r.Err()
+	}
+	if r.Len() != 0 {
+		return nil, ErrInvalidValue
+	}
+	return witness, nil
+}
+
+func serializeWitness(witness [][]byte) []byte {
+	b := bitcoin.AppendVarInt(nil, uint64(len(witness)))
+	for _, item := range witness {
+		b = bitcoin.AppendVarBytes(b, item)
+	}
+	return b
+}
@@ -0,0 +1,759 @@
+// Package psbt implements partially signed bitcoin transactions, BIP-174 version 0 and BIP-370 version 2.
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dubuqingfeng/signer/bitcoin"
+)
+
+const (
+	// Version0 keeps the unsigned transaction in a global field (BIP-174).
+	Version0 uint32 = 0
+	// Version2 keeps the transaction fields in the inputs and outputs (BIP-370).
+	Version2 uint32 = 2
+
+	magic = "psbt\xff"
+
+	// lockTimeThreshold separates block heights from unix times in lock times.
+	lockTimeThreshold = 500000000
+
+	// unknownType marks version 2 key types with key data in version 0 packets, they
+	// are not the version 2 fields and predate them in the BIP-174 test vectors.
+	unknownType = ^uint64(0)
+
+	// maxCount bounds the version 2 input and output counts, a map is at least its separator byte.
+	maxCount = 1 << 24
+)
+
+// global key types
+const (
+	globalUnsignedTx       = 0x00
+	globalXPub             = 0x01
+	globalTxVersion        = 0x02
+	globalFallbackLocktime = 0x03
+	globalInputCount       = 0x04
+	globalOutputCount      = 0x05
+	globalTxModifiable     = 0x06
+	globalVersion          = 0xfb
+)
+
+// input key types
+const (
+	inNonWitnessUtxo         = 0x00
+	inWitnessUtxo            = 0x01
+	inPartialSig             = 0x02
+	inSighashType            = 0x03
+	inRedeemScript           = 0x04
+	inWitnessScript          = 0x05
+	inBip32Derivation        = 0x06
+	inFinalScriptSig         = 0x07
+	inFinalScriptWitness     = 0x08
+	inPreviousTxid           = 0x0e
+	inOutputIndex            = 0x0f
+	inSequence               = 0x10
+	inRequiredTimeLocktime   = 0x11
+	inRequiredHeightLocktime = 0x12
+)
+
+// output key types
+const (
+	outRedeemScript    = 0x00
+	outWitnessScript   = 0x01
+	outBip32Derivation = 0x02
+	outAmount          = 0x03
+	outScript          = 0x04
+)
+
+var (
+	ErrInvalidMagic        = errors.New("invalid psbt magic bytes")
+	ErrUnexpectedEnd       = bitcoin.ErrUnexpectedEnd
+	ErrNonCanonicalSize    = bitcoin.ErrNonCanonicalVarInt
+	ErrTrailingBytes       = errors.New("trailing bytes after the psbt")
+	ErrDuplicateKey        = errors.New("duplicate key")
+	ErrInvalidKey          = errors.New("invalid key data for the key type")
+	ErrInvalidValue        = errors.New("invalid value for the key type")
+	ErrUnsupportedVersion  = errors.New("unsupported psbt version")
+	ErrFieldVersion        = errors.New("field is not allowed in this psbt version")
+	ErrMissingUnsignedTx   = errors.New("missing unsigned transaction")
+	ErrMissingField        = errors.New("missing required field")
+	ErrSignedTx            = errors.New("unsigned transaction has signature scripts or witnesses")
+	ErrUtxoMismatch        = errors.New("non-witness utxo does not match the previous outpoint")
+	ErrLockTimeConflict    = errors.New("inputs require both height and time lock times")
+	ErrTransactionMismatch = errors.New("packets are for different transactions")
+)
+
+// Unknown is a key-value pair the package does not interpret, kept as is.
+type Unknown struct {
+	Key   []byte // the key type followed by the key data
+	Value []byte
+}
+
+// PartialSig is a DER signature followed by the sighash type byte.
+type PartialSig struct {
+	PubKey    []byte
+	Signature []byte
+}
+
+// Packet is a partially signed transaction. The transaction fields are kept in the
+// packet, inputs and outputs for both versions, version 0 serializes them as the
+// unsigned transaction.
+type Packet struct {
+	Version uint32
+
+	TxVersion int32
+	// FallbackLocktime is the lock time when no input requires one, the lock time of version 0.
+	FallbackLocktime *uint32
+	// TxModifiable is the BIP-370 bit field of the inputs, outputs and sighash single flags.
+	TxModifiable *uint8
+
+	XPubs    []*XPub
+	Inputs   []*Input
+	Outputs  []*Output
+	Unknowns []*Unknown
+}
+
+// Input is the per input map.
+type Input struct {
+	PreviousOutPoint bitcoin.OutPoint
+	// Sequence defaults to the final sequence when nil.
+	Sequence               *uint32
+	RequiredTimeLocktime   *uint32
+	RequiredHeightLocktime *uint32
+
+	NonWitnessUtxo *bitcoin.Transaction
+	WitnessUtxo    *bitcoin.TxOut
+	PartialSigs    []*PartialSig
+	// SighashType defaults to SigHashAll when zero.
+	SighashType      bitcoin.SigHashType
+	RedeemScript     []byte
+	WitnessScript    []byte
+	Bip32Derivations []*Bip32Derivation
+
+	FinalScriptSig     []byte
+	FinalScriptWitness [][]byte
+
+	Unknowns []*Unknown
+}
+
+// Output is the per output map.
+type Output struct {
+	Amount int64
+	Script []byte
+
+	RedeemScript     []byte
+	WitnessScript    []byte
+	Bip32Derivations []*Bip32Derivation
+
+	Unknowns []*Unknown
+}
+
+// New returns a version 0 packet spending the inputs of an unsigned transaction.
+func New(tx *bitcoin.Transaction) (*Packet, error) {
+	for _, in := range tx.TxIn {
+		if len(in.SignatureScript) != 0 || len(in.Witness) != 0 {
+			return nil, ErrSignedTx
+		}
+	}
+	lockTime := tx.LockTime
+	p := &Packet{Version: Version0, TxVersion: tx.Version, FallbackLocktime: &lockTime}
+	for _, in := range tx.TxIn {
+		sequence := in.Sequence
+		p.Inputs = append(p.Inputs, &Input{PreviousOutPoint: in.PreviousOutPoint, Sequence: &sequence})
+	}
+	for _, out := range tx.TxOut {
+		p.Outputs = append(p.Outputs, &Output{Amount: out.Value, Script: append([]byte{}, out.PkScript...)})
+	}
+	return p, nil
+}
+
+// Parse decodes the binary serialization.
+func Parse(data []byte) (*Packet, error) {
+	if !bytes.HasPrefix(data, []byte(magic)) {
+		return nil, ErrInvalidMagic
+	}
+	r := bitcoin.NewReader(data[len(magic):])
+	globals, err := readMap(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &Packet{}
+	tx, inCount, outCount, err := p.parseGlobals(globals)
+	if err != nil {
+		return nil, err
+	}
+	if inCount+outCount > r.Len() {
+		return nil, ErrUnexpectedEnd
+	}
+
+	for i := 0; i < inCount; i++ {
+		pairs, err := readMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		in, err := parseInput(pairs, p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+		if tx != nil {
+			sequence := tx.TxIn[i].Sequence
+			in.PreviousOutPoint, in.Sequence = tx.TxIn[i].PreviousOutPoint, &sequence
+		}
+		p.Inputs = append(p.Inputs, in)
+	}
+	for i := 0; i < outCount; i++ {
+		pairs, err := readMap(r)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %w", i, err)
+		}
+		out, err := parseOutput(pairs, p.Version)
+		if err != nil {
+			return nil, fmt.Errorf("output %d: %w", i, err)
+		}
+		if tx != nil {
+			out.Amount, out.Script = tx.TxOut[i].Value, tx.TxOut[i].PkScript
+		}
+		p.Outputs = append(p.Outputs, out)
+	}
+	if r.Len() != 0 {
+		return nil, ErrTrailingBytes
+	}
+
+	for i, in := range p.Inputs {
+		if in.NonWitnessUtxo != nil && !in.utxoMatches() {
+			return nil, fmt.Errorf("input %d: %w", i, ErrUtxoMismatch)
+		}
+	}
+	return p, nil
+}
+
+// ParseBase64 decodes the base64 serialization.
+func ParseBase64(s string) (*Packet, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Serialize returns the binary serialization.
+func (p *Packet) Serialize() ([]byte, error) {
+	globals, err := p.globalPairs()
+	if err != nil {
+		return nil, err
+	}
+	b := appendMap([]byte(magic), globals)
+	for _, in := range p.Inputs {
+		b = appendMap(b, in.pairs(p.Version))
+	}
+	for _, out := range p.Outputs {
+		b = appendMap(b, out.pairs(p.Version))
+	}
+	return b, nil
+}
+
+// Base64 returns the base64 serialization.
+func (p *Packet) Base64() (string, error) {
+	b, err := p.Serialize()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// UnsignedTx returns the transaction without signature scripts and witnesses.
+func (p *Packet) UnsignedTx() (*bitcoin.Transaction, error) {
+	lockTime, err := p.LockTime()
+	if err != nil {
+		return nil, err
+	}
+	tx := bitcoin.NewTransaction(p.TxVersion)
+	tx.LockTime = lockTime
+	for _, in := range p.Inputs {
+		tx.AddTxIn(in.PreviousOutPoint).Sequence = in.sequence()
+	}
+	for _, out := range p.Outputs {
+		tx.AddTxOut(out.Amount, out.Script)
+	}
+	return tx, nil
+}
+
+// LockTime returns the transaction lock time, the highest required lock time of the
+// type every input supports, or the fallback lock time when no input requires one.
+func (p *Packet) LockTime() (uint32, error) {
+	var height, time uint32
+	heightOK, timeOK, required := true, true, false
+	for _, in := range p.Inputs {
+		if in.RequiredHeightLocktime == nil && in.RequiredTimeLocktime == nil {
+			continue
+		}
+		required = true
+		if in.RequiredHeightLocktime == nil {
+			heightOK = false
+		} else if *in.RequiredHeightLocktime > height {
+			height = *in.RequiredHeightLocktime
+		}
+		if in.RequiredTimeLocktime == nil {
+			timeOK = false
+		} else if *in.RequiredTimeLocktime > time {
+			time = *in.RequiredTimeLocktime
+		}
+	}
+	switch {
+	case !required:
+		if p.FallbackLocktime != nil {
+			return *p.FallbackLocktime, nil
+		}
+		return 0, nil
+	case heightOK:
+		return height, nil
+	case timeOK:
+		return time, nil
+	default:
+		return 0, ErrLockTimeConflict
+	}
+}
+
+// ConvertVersion switches the packet between version 0 and version 2. Converting to
+// version 0 fixes the lock time and drops the fields version 0 can not keep.
+func (p *Packet) ConvertVersion(version uint32) error {
+	switch version {
+	case Version0:
+		lockTime, err := p.LockTime()
+		if err != nil {
+			return err
+		}
+		p.FallbackLocktime, p.TxModifiable = &lockTime, nil
+		for _, in := range p.Inputs {
+			in.RequiredTimeLocktime, in.RequiredHeightLocktime = nil, nil
+		}
+	case Version2:
+	default:
+		return ErrUnsupportedVersion
+	}
+	p.Version = version
+	return nil
+}
+
+func (p *Packet) parseGlobals(pairs []pair) (tx *bitcoin.Transaction, inCount, outCount int, err error) {
+	for _, pr := range pairs {
+		if pr.keyType() != globalVersion {
+			continue
+		}
+		if len(pr.keyData()) != 0 {
+			return nil, 0, 0, ErrInvalidKey
+		}
+		if p.Version, err = parseUint32(pr.value); err != nil {
+			return nil, 0, 0, err
+		}
+		if p.Version != Version0 && p.Version != Version2 {
+			return nil, 0, 0, ErrUnsupportedVersion
+		}
+	}
+
+	hasTxVersion, hasInCount, hasOutCount := false, false, false
+	for _, pr := range pairs {
+		typ := pr.keyType()
+		switch typ {
+		case globalTxVersion, globalFallbackLocktime, globalInputCount, globalOutputCount, globalTxModifiable:
+			if p.Version != Version2 {
+				if len(pr.keyData()) != 0 {
+					typ = unknownType
+					break
+				}
+				return nil, 0, 0, ErrFieldVersion
+			}
+			fallthrough
+		case globalUnsignedTx:
+			if len(pr.keyData()) != 0 {
+				return nil, 0, 0, ErrInvalidKey
+			}
+			if typ == globalUnsignedTx && p.Version != Version0 {
+				return nil, 0, 0, ErrFieldVersion
+			}
+		}
+
+		switch typ {
+		case globalUnsignedTx:
+			if tx, err = bitcoin.DeserializeTransactionNoWitness(pr.value); err != nil {
+				return nil, 0, 0, err
+			}
+			for _, in := range tx.TxIn {
+				if len(in.SignatureScript) != 0 {
+					return nil, 0, 0, ErrSignedTx
+				}
+			}
+			lockTime := tx.LockTime
+			p.TxVersion, p.FallbackLocktime = tx.Version, &lockTime
+			inCount, outCount = len(tx.TxIn), len(tx.TxOut)
+		case globalXPub:
+			xpub, err := parseXPub(pr)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			p.XPubs = append(p.XPubs, xpub)
+		case globalTxVersion:
+			version, err := parseUint32(pr.value)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			p.TxVersion, hasTxVersion = int32(version), true
+		case globalFallbackLocktime:
+			lockTime, err := parseUint32(pr.value)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			p.FallbackLocktime = &lockTime
+		case globalInputCount:
+			if inCount, err = parseCount(pr.value); err != nil {
+				return nil, 0, 0, err
+			}
+			hasInCount = true
+		case globalOutputCount:
+			if outCount, err = parseCount(pr.value); err != nil {
+				return nil, 0, 0, err
+			}
+			hasOutCount = true
+		case globalTxModifiable:
+			if len(pr.value) != 1 {
+				return nil, 0, 0, ErrInvalidValue
+			}
+			modifiable := pr.value[0]
+			p.TxModifiable = &modifiable
+		case globalVersion:
+		default:
+			p.Unknowns = append(p.Unknowns, &Unknown{Key: pr.key, Value: pr.value})
+		}
+	}
+
+	if p.Version == Version0 && tx == nil {
+		return nil, 0, 0, ErrMissingUnsignedTx
+	}
+	if p.Version == Version2 && !(hasTxVersion && hasInCount && hasOutCount) {
+		return nil, 0, 0, ErrMissingField
+	}
+	return tx, inCount, outCount, nil
+}
+
+func parseInput(pairs []pair, version uint32) (*Input, error) {
+	in := &Input{}
+	hasTxid, hasIndex := false, false
+	for _, pr := range pairs {
+		typ := pr.keyType()
+		switch typ {
+		case inPartialSig, inBip32Derivation:
+			if !validPubKey(pr.keyData()) {
+				return nil, ErrInvalidKey
+			}
+		case inPreviousTxid, inOutputIndex, inSequence, inRequiredTimeLocktime, inRequiredHeightLocktime:
+			if version != Version2 {
+				if len(pr.keyData()) != 0 {
+					typ = unknownType
+					break
+				}
+				return nil, ErrFieldVersion
+			}
+			fallthrough
+		case inNonWitnessUtxo, inWitnessUtxo, inSighashType, inRedeemScript, inWitnessScript, inFinalScriptSig, inFinalScriptWitness:
+			if len(pr.keyData()) != 0 {
+				return nil, ErrInvalidKey
+			}
+		}
+
+		var err error
+		switch typ {
+		case inNonWitnessUtxo:
+			in.NonWitnessUtxo, err = bitcoin.DeserializeTransaction(pr.value)
+		case inWitnessUtxo:
+			in.WitnessUtxo, err = parseTxOut(pr.value)
+		case inPartialSig:
+			if len(pr.value) == 0 {
+				return nil, ErrInvalidValue
+			}
+			in.PartialSigs = append(in.PartialSigs, &PartialSig{PubKey: pr.keyData(), Signature: pr.value})
+		case inSighashType:
+			var hashType uint32
+			hashType, err = parseUint32(pr.value)
+			in.SighashType = bitcoin.SigHashType(hashType)
+		case inRedeemScript:
+			in.RedeemScript = pr.value
+		case inWitnessScript:
+			in.WitnessScript = pr.value
+		case inBip32Derivation:
+			var d *Bip32Derivation
+			if d, err = parseBip32Derivation(pr); err == nil {
+				in.Bip32Derivations = append(in.Bip32Derivations, d)
+			}
+		case inFinalScriptSig:
+			in.FinalScriptSig = pr.value
+		case inFinalScriptWitness:
+			in.FinalScriptWitness, err = parseWitness(pr.value)
+		case inPreviousTxid:
+			if len(pr.value) != len(in.PreviousOutPoint.Hash) {
+				return nil, ErrInvalidValue
+			}
+			copy(in.PreviousOutPoint.Hash[:], pr.value)
+			hasTxid = true
+		case inOutputIndex:
+			in.PreviousOutPoint.Index, err = parseUint32(pr.value)
+			hasIndex = true
+		case inSequence:
+			in.Sequence, err = parseUint32Ptr(pr.value)
+		case inRequiredTimeLocktime:
+			in.RequiredTimeLocktime, err = parseUint32Ptr(pr.value)
+			if err == nil && *in.RequiredTimeLocktime < lockTimeThreshold {
+				err = ErrInvalidValue
+			}
+		case inRequiredHeightLocktime:
+			in.RequiredHeightLocktime, err = parseUint32Ptr(pr.value)
+			if err == nil && (*in.RequiredHeightLocktime == 0 || *in.RequiredHeightLocktime >= lockTimeThreshold) {
+				err = ErrInvalidValue
+			}
+		default:
+			in.Unknowns = append(in.Unknowns, &Unknown{Key: pr.key, Value: pr.value})
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if version == Version2 && !(hasTxid && hasIndex) {
+		return nil, ErrMissingField
+	}
+	return in, nil
+}
+
+func parseOutput(pairs []pair, version uint32) (*Output, error) {
+	out := &Output{}
+	hasAmount, hasScript := false, false
+	for _, pr := range pairs {
+		typ := pr.keyType()
+		switch typ {
+		case outBip32Derivation:
+			if !validPubKey(pr.keyData()) {
+				return nil, ErrInvalidKey
+			}
+		case outAmount, outScript:
+			if version != Version2 {
+				if len(pr.keyData()) != 0 {
+					typ = unknownType
+					break
+				}
+				return nil, ErrFieldVersion
+			}
+			fallthrough
+		case outRedeemScript, outWitnessScript:
+			if len(pr.keyData()) != 0 {
+				return nil, ErrInvalidKey
+			}
+		}
+
+		switch typ {
+		case outRedeemScript:
+			out.RedeemScript = pr.value
+		case outWitnessScript:
+			out.WitnessScript = pr.value
+		case outBip32Derivation:
+			d, err := parseBip32Derivation(pr)
+			if err != nil {
+				return nil, err
+			}
+			out.Bip32Derivations = append(out.Bip32Derivations, d)
+		case outAmount:
+			if len(pr.value) != 8 {
+				return nil, ErrInvalidValue
+			}
+			out.Amount = int64(binary.LittleEndian.Uint64(pr.value))
+			hasAmount = true
+		case outScript:
+			out.Script, hasScript = pr.value, true
+		default:
+			out.Unknowns = append(out.Unknowns, &Unknown{Key: pr.key, Value: pr.value})
+		}
+	}
+	if version == Version2 && !(hasAmount && hasScript) {
+		return nil, ErrMissingField
+	}
+	return out, nil
+}
+
+func (p *Packet) globalPairs() ([]pair, error) {
+	var pairs []pair
+	switch p.Version {
+	case Version0:
+		tx, err := p.UnsignedTx()
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair{[]byte{globalUnsignedTx}, tx.SerializeNoWitness()})
+	case Version2:
+	default:
+		return nil, ErrUnsupportedVersion
+	}
+	for _, xpub := range p.XPubs {
+		pairs = append(pairs, pair{append([]byte{globalXPub}, xpub.ExtendedKey...), serializeKeyOrigin(xpub.Fingerprint, xpub.Path)})
+	}
+	if p.Version == Version2 {
+		pairs = append(pairs, pair{[]byte{globalTxVersion}, bitcoin.AppendUint32(nil, uint32(p.TxVersion))})
+		if p.FallbackLocktime != nil {
+			pairs = append(pairs, pair{[]byte{globalFallbackLocktime}, bitcoin.AppendUint32(nil, *p.FallbackLocktime)})
+		}
+		pairs = append(pairs,
+			pair{[]byte{globalInputCount}, bitcoin.AppendVarInt(nil, uint64(len(p.Inputs)))},
+			pair{[]byte{globalOutputCount}, bitcoin.AppendVarInt(nil, uint64(len(p.Outputs)))})
+		if p.TxModifiable != nil {
+			pairs = append(pairs, pair{[]byte{globalTxModifiable}, []byte{*p.TxModifiable}})
+		}
+		pairs = append(pairs, pair{[]byte{globalVersion}, bitcoin.AppendUint32(nil, p.Version)})
+	}
+	return sortPairs(appendUnknowns(pairs, p.Unknowns)), nil
+}
+
+func (in *Input) pairs(version uint32) []pair {
+	var pairs []pair
+	if in.NonWitnessUtxo != nil {
+		pairs = append(pairs, pair{[]byte{inNonWitnessUtxo}, in.NonWitnessUtxo.Serialize()})
+	}
+	if in.WitnessUtxo != nil {
+		pairs = append(pairs, pair{[]byte{inWitnessUtxo}, serializeTxOut(in.WitnessUtxo)})
+	}
+	// ordered by public key hash like bitcoin core
+	sigs := append([]*PartialSig{}, in.PartialSigs...)
+	sort.SliceStable(sigs, func(i, j int) bool { return bytes.Compare(hash160(sigs[i].PubKey), hash160(sigs[j].PubKey)) < 0 })
+	for _, sig := range sigs {
+		pairs = append(pairs, pair{append([]byte{inPartialSig}, sig.PubKey...), sig.Signature})
+	}
+	if in.SighashType != 0 {
+		pairs = append(pairs, pair{[]byte{inSighashType}, bitcoin.AppendUint32(nil, uint32(in.SighashType))})
+	}
+	if in.RedeemScript != nil {
+		pairs = append(pairs, pair{[]byte{inRedeemScript}, in.RedeemScript})
+	}
+	if in.WitnessScript != nil {
+		pairs = append(pairs, pair{[]byte{inWitnessScript}, in.WitnessScript})
+	}
+	pairs = appendDerivations(pairs, inBip32Derivation, in.Bip32Derivations)
+	if in.FinalScriptSig != nil {
+		pairs = append(pairs, pair{[]byte{inFinalScriptSig}, in.FinalScriptSig})
+	}
+	if in.FinalScriptWitness != nil {
+		pairs = append(pairs, pair{[]byte{inFinalScriptWitness}, serializeWitness(in.FinalScriptWitness)})
+	}
+	if version == Version2 {
+		pairs = append(pairs,
+			pair{[]byte{inPreviousTxid}, append([]byte{}, in.PreviousOutPoint.Hash[:]...)},
+			pair{[]byte{inOutputIndex}, bitcoin.AppendUint32(nil, in.PreviousOutPoint.Index)})
+		if in.Sequence != nil {
+			pairs = append(pairs, pair{[]byte{inSequence}, bitcoin.AppendUint32(nil, *in.Sequence)})
+		}
+		if in.RequiredTimeLocktime != nil {
+			pairs = append(pairs, pair{[]byte{inRequiredTimeLocktime}, bitcoin.AppendUint32(nil, *in.RequiredTimeLocktime)})
+		}
+		if in.RequiredHeightLocktime != nil {
+			pairs = append(pairs, pair{[]byte{inRequiredHeightLocktime}, bitcoin.AppendUint32(nil, *in.RequiredHeightLocktime)})
+		}
+	}
+	return sortPairs(appendUnknowns(pairs, in.Unknowns))
+}
+
+func (out *Output) pairs(version uint32) []pair {
+	var pairs []pair
+	if out.RedeemScript != nil {
+		pairs = append(pairs, pair{[]byte{outRedeemScript}, out.RedeemScript})
+	}
+	if out.WitnessScript != nil {
+		pairs = append(pairs, pair{[]byte{outWitnessScript}, out.WitnessScript})
+	}
+	pairs = appendDerivations(pairs, outBip32Derivation, out.Bip32Derivations)
+	if version == Version2 {
+		pairs = append(pairs,
+			pair{[]byte{outAmount}, bitcoin.AppendUint64(nil, uint64(out.Amount))},
+			pair{[]byte{outScript}, out.Script})
+	}
+	return sortPairs(appendUnknowns(pairs, out.Unknowns))
+}
+
+// sequence returns the input sequence, final when it is not set.
+func (in *Input) sequence() uint32 {
+	if in.Sequence == nil {
+		return bitcoin.MaxSequence
+	}
+	return *in.Sequence
+}
+
+// utxoMatches reports whether the non-witness utxo is the transaction of the previous outpoint.
+func (in *Input) utxoMatches() bool {
+	return in.NonWitnessUtxo.TxHash() == in.PreviousOutPoint.Hash &&
+		int(in.PreviousOutPoint.Index) < len(in.NonWitnessUtxo.TxOut)
+}
+
+// isFinalized reports whether the input has its final scripts.
+func (in *Input) isFinalized() bool {
+	return in.FinalScriptSig != nil || in.FinalScriptWitness != nil
+}
+
+func appendUnknowns(pairs []pair, unknowns []*Unknown) []pair {
+	for _, u := range unknowns {
+		pairs = append(pairs, pair{u.Key, u.Value})
+	}
+	return pairs
+}
+
+// sortPairs orders the pairs by key type, pairs of the same type keep their order.
+func sortPairs(pairs []pair) []pair {
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].keyType() < pairs[j].keyType() })
+	return pairs
+}
+
+func parseTxOut(data []byte) (*bitcoin.TxOut, error) {
+	r := bitcoin.NewReader(data)
+	out := &bitcoin.TxOut{Value: int64(r.Uint64())}
+	out.PkScript = r.VarBytes()
+	if r.Err() != nil || r.Len() != 0 {
+		return nil, ErrInvalidValue
+	}
+	return out, nil
+}
+
+func serializeTxOut(out *bitcoin.TxOut) []byte {
+	return bitcoin.AppendVarBytes(bitcoin.AppendUint64(nil, uint64(out.Value)), out.PkScript)
+}
+
+func parseUint32(value []byte) (uint32, error) {
+	if len(value) != 4 {
+		return 0, ErrInvalidValue
+	}
+	return binary.LittleEndian.Uint32(value), nil
+}
+
+func parseUint32Ptr(value []byte) (*uint32, error) {
+	n, err := parseUint32(value)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func parseCount(value []byte) (int, error) {
+	r := bitcoin.NewReader(value)
+	n := r.VarInt()
+	if r.Err() != nil || r.Len() != 0 || n > maxCount {
+		return 0, ErrInvalidValue
+	}
+	return int(n), nil
+}
+
+// validPubKey checks the length and prefix of a compressed or uncompressed public key.
+func validPubKey(key []byte) bool {
+	switch len(key) {
+	case 33:
+		return key[0] == 0x02 || key[0] == 0x03
+	case 65:
+		return key[0] == 0x04
+	}
+	return false
+}
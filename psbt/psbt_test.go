@@ -0,0 +1,509 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/bitcoin"
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// testdata/bip174.json are the BIP-174 test vectors, as collected by btcd's psbt package.
+type bip174Vectors struct {
+	Valid       []string
+	ValidBase64 []string
+	Invalid     []struct {
+		Comment string
+		Hex     string
+	}
+	Signer    map[string]string
+	Finalizer map[string]string
+	Updater   map[string]string
+}
+
+// bip174Master is the master key of the BIP-174 signer example, fingerprint d90c6a4f.
+const bip174Master = "tprv8ZgxMBicQKsPd9TeAdPADNnSyH9SSUUbTVeFszDE23Ki6TBB5nCefAdHkK8Fm3qMQR6sHwA56zqRmKmxnHk37JkiFzvncDqoKmPWubu7hDF"
+
+func loadVectors(t *testing.T) *bip174Vectors {
+	t.Helper()
+	file, err := os.ReadFile("testdata/bip174.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v bip174Vectors
+	if err := json.Unmarshal(file, &v); err != nil {
+		t.Fatal(err)
+	}
+	return &v
+}
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func mustParseHex(t *testing.T, s string) *Packet {
+	t.Helper()
+	p, err := Parse(mustDecodeHex(t, s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func serializeHex(t *testing.T, p *Packet) string {
+	t.Helper()
+	b, err := p.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func newContext(t *testing.T) *secp256k1.Context {
+	t.Helper()
+	ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ctx
+}
+
+// decodeWIF returns the private key of a compressed WIF key.
+func decodeWIF(t *testing.T, wif string) []byte {
+	t.Helper()
+	b, err := bip32.Base58CheckDecode(wif)
+	if err != nil || len(b) != 34 {
+		t.Fatalf("invalid WIF %s: %v", wif, err)
+	}
+	return b[1:33]
+}
+
+func TestParseValid(t *testing.T) {
+	v := loadVectors(t)
+	for i, s := range v.Valid {
+		p := mustParseHex(t, s)
+		if got := serializeHex(t, p); got != s {
+			t.Errorf("#%d: Serialize() = %s, want %s", i, got, s)
+		}
+	}
+	// taproot packets, their fields are kept as unknowns
+	for i, s := range v.ValidBase64 {
+		p, err := ParseBase64(s)
+		if err != nil {
+			t.Fatalf("#%d: ParseBase64() error = %v", i, err)
+		}
+		if got, err := p.Base64(); err != nil || got != s {
+			t.Errorf("#%d: Base64() = %s, %v, want %s", i, got, err, s)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	v := loadVectors(t)
+	for i, tt := range v.Invalid {
+		if _, err := Parse(mustDecodeHex(t, tt.Hex)); err == nil {
+			t.Errorf("#%d %s: Parse() succeeded", i, tt.Comment)
+		}
+	}
+
+	errorTests := []struct {
+		index int
+		err   error
+	}{
+		{0, ErrInvalidMagic},
+		{2, ErrSignedTx},
+		{3, ErrMissingUnsignedTx},
+		{4, ErrDuplicateKey},
+		{5, ErrInvalidKey},
+		{7, ErrInvalidKey},
+	}
+	for _, tt := range errorTests {
+		if _, err := Parse(mustDecodeHex(t, v.Invalid[tt.index].Hex)); !errors.Is(err, tt.err) {
+			t.Errorf("#%d: Parse() error = %v, want %v", tt.index, err, tt.err)
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	v := loadVectors(t)
+	p := mustParseHex(t, v.Updater["COPsbtHex"])
+
+	var err error
+	if p.Inputs[0].NonWitnessUtxo, err = bitcoin.DeserializeTransaction(mustDecodeHex(t, v.Updater["NonWitnessUtxo"])); err != nil {
+		t.Fatal(err)
+	}
+	if p.Inputs[1].WitnessUtxo, err = parseTxOut(mustDecodeHex(t, v.Updater["WitnessUtxo"])); err != nil {
+		t.Fatal(err)
+	}
+	p.Inputs[0].RedeemScript = mustDecodeHex(t, v.Updater["Input1RedeemScript"])
+	p.Inputs[1].RedeemScript = mustDecodeHex(t, v.Updater["Input2RedeemScript"])
+	p.Inputs[1].WitnessScript = mustDecodeHex(t, v.Updater["Input2WitnessScript"])
+
+	fp := [4]byte{0xd9, 0x0c, 0x6a, 0x4f}
+	derivation := func(pubKey string, index uint32) *Bip32Derivation {
+		h := uint32(bip32.HardenedKeyZeroIndex)
+		return &Bip32Derivation{PubKey: mustDecodeHex(t, pubKey), Fingerprint: fp, Path: []uint32{h, h, h + index}}
+	}
+	p.Inputs[0].AddBip32Derivation(derivation("029583bf39ae0a609747ad199addd634fa6108559d6c5cd39b4c2183f1ab96e07f", 0))
+	p.Inputs[0].AddBip32Derivation(derivation("02dab61ff49a14db6a7d02b0cd1fbb78fc4b18312b5b4e54dae4dba2fbfef536d7", 1))
+	p.Inputs[1].AddBip32Derivation(derivation("03089dc10c7ac6db54f91329af617333db388cead0c231f723379d1b99030b02dc", 2))
+	p.Inputs[1].AddBip32Derivation(derivation("023add904f3d6dcf59ddb906b0dee23529b7ffb9ed50e5e86151926860221f0e73", 3))
+	p.Outputs[0].AddBip32Derivation(derivation("03a9a4c37f5996d3aa25dbac6b570af0650394492942460b354753ed9eeca58771", 4))
+	p.Outputs[1].AddBip32Derivation(derivation("027f6399757d2eff55a136ad02c684b1838b6556e5f1b6b34282a94b6b50051096", 5))
+	for _, in := range p.Inputs {
+		in.SighashType = bitcoin.SigHashAll
+	}
+
+	if got := serializeHex(t, p); got != v.Updater["UOPsbtHex4"] {
+		t.Errorf("Serialize() = %s, want %s", got, v.Updater["UOPsbtHex4"])
+	}
+	if got := p.Inputs[1].DerivationsFor(fp); len(got) != 2 {
+		t.Errorf("DerivationsFor() = %d derivations, want 2", len(got))
+	}
+	if got := p.Inputs[1].DerivationsFor([4]byte{}); len(got) != 0 {
+		t.Errorf("DerivationsFor() of another fingerprint = %d derivations, want 0", len(got))
+	}
+}
+
+func TestSignInput(t *testing.T) {
+	v := loadVectors(t)
+	ctx := newContext(t)
+
+	signer1, err := ParseBase64(v.Signer["signer1PsbtB64"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signers := []struct {
+		packet *Packet
+		keys   []string
+		want   string
+	}{
+		{signer1, []string{v.Signer["signer1Privkey1"], v.Signer["signer1Privkey2"]}, v.Signer["signer1Result"]},
+		{mustParseHex(t, v.Signer["signer2Psbt"]), []string{v.Signer["signer2Privkey1"], v.Signer["signer2Privkey2"]}, v.Signer["signer2Result"]},
+	}
+	for i, tt := range signers {
+		for _, wif := range tt.keys {
+			seckey := decodeWIF(t, wif)
+			signed := 0
+			for idx := range tt.packet.Inputs {
+				err := tt.packet.SignInput(ctx, idx, seckey)
+				if err == nil {
+					signed++
+				} else if !errors.Is(err, ErrKeyNotInScript) {
+					t.Fatalf("#%d: SignInput(%d) error = %v", i, idx, err)
+				}
+			}
+			if signed != 1 {
+				t.Errorf("#%d: key signed %d inputs, want 1", i, signed)
+			}
+		}
+		if got := serializeHex(t, tt.packet); got != tt.want {
+			t.Errorf("#%d: Serialize() = %s, want %s", i, got, tt.want)
+		}
+	}
+
+	if err := signer1.SignInput(ctx, 2, decodeWIF(t, v.Signer["signer1Privkey1"])); err != ErrInputIndex {
+		t.Errorf("SignInput() error = %v, want %v", err, ErrInputIndex)
+	}
+}
+
+func TestSignWithMasterKey(t *testing.T) {
+	v := loadVectors(t)
+	ctx := newContext(t)
+	master, err := bip32.NewMasterKeyFromExtendKey(bip174Master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fp, err := MasterFingerprint(master); err != nil || hex.EncodeToString(fp[:]) != "d90c6a4f" {
+		t.Fatalf("MasterFingerprint() = %x, %v", fp, err)
+	}
+
+	p, err := ParseBase64(v.Signer["signer1PsbtB64"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := p.SignWithMasterKey(ctx, master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed != 4 {
+		t.Errorf("SignWithMasterKey() = %d signatures, want 4", signed)
+	}
+	// the master key holds the keys of both signers
+	if got := serializeHex(t, p); got != v.Finalizer["finalize"] {
+		t.Errorf("Serialize() = %s, want %s", got, v.Finalizer["finalize"])
+	}
+
+	p.Inputs[0].Bip32Derivations[0].PubKey = p.Inputs[0].Bip32Derivations[1].PubKey
+	if _, err := p.SignWithMasterKey(ctx, master); !errors.Is(err, ErrDerivationMismatch) {
+		t.Errorf("SignWithMasterKey() error = %v, want %v", err, ErrDerivationMismatch)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	v := loadVectors(t)
+	signer1 := mustParseHex(t, v.Signer["signer1Result"])
+	signer2 := mustParseHex(t, v.Signer["signer2Result"])
+
+	combined, err := Combine(signer1, signer2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := serializeHex(t, combined); got != v.Finalizer["finalize"] {
+		t.Errorf("Combine() = %s, want %s", got, v.Finalizer["finalize"])
+	}
+	if got := serializeHex(t, signer1); got != v.Signer["signer1Result"] {
+		t.Errorf("Combine() modified its input")
+	}
+
+	if _, err := Combine(signer1, mustParseHex(t, v.Finalizer["twoOfThree"])); err != ErrTransactionMismatch {
+		t.Errorf("Combine() error = %v, want %v", err, ErrTransactionMismatch)
+	}
+	if _, err := Combine(); err != ErrNoPackets {
+		t.Errorf("Combine() error = %v, want %v", err, ErrNoPackets)
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	v := loadVectors(t)
+	p, err := ParseBase64(v.Finalizer["finalizeb64"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Extract(); !errors.Is(err, ErrNotFinalized) {
+		t.Errorf("Extract() error = %v, want %v", err, ErrNotFinalized)
+	}
+	if err := p.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsComplete() {
+		t.Errorf("IsComplete() = false")
+	}
+	if got, err := p.Base64(); err != nil || got != v.Finalizer["resultb64"] {
+		t.Errorf("Finalize() = %s, %v, want %s", got, err, v.Finalizer["resultb64"])
+	}
+
+	tx, err := p.Extract()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(tx.Serialize()); got != v.Finalizer["network"] {
+		t.Errorf("Extract() = %s, want %s", got, v.Finalizer["network"])
+	}
+
+	// a P2WSH 2-of-3 multisig input
+	p = mustParseHex(t, v.Finalizer["twoOfThree"])
+	if err := p.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	if w := p.Inputs[0].FinalScriptWitness; len(w) != 4 || len(w[0]) != 0 || p.Inputs[0].FinalScriptSig != nil {
+		t.Errorf("FinalScriptWitness = %x, FinalScriptSig = %x", w, p.Inputs[0].FinalScriptSig)
+	}
+
+	// one of the two signatures is missing
+	p = mustParseHex(t, v.Signer["signer1Result"])
+	if err := p.FinalizeInput(0); err != ErrMissingSignature {
+		t.Errorf("FinalizeInput() error = %v, want %v", err, ErrMissingSignature)
+	}
+}
+
+func TestSingleKeyInputs(t *testing.T) {
+	ctx := newContext(t)
+	seckey := bytes.Repeat([]byte{0x11}, 32)
+	pubKey, err := compressedPubKey(ctx, seckey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyHash := hash160(pubKey)
+	p2wpkh := append([]byte{op0, 20}, keyHash...)
+
+	prev := bitcoin.NewTransaction(2)
+	prev.AddTxIn(bitcoin.OutPoint{Index: 0})
+	prev.AddTxOut(10000, bitcoin.P2PKHScript(keyHash))
+	prev.AddTxOut(20000, p2wpkh)
+	prev.AddTxOut(30000, append(append([]byte{bitcoin.OpHash160, 20}, hash160(p2wpkh)...), opEqual))
+
+	tx := bitcoin.NewTransaction(2)
+	for i := uint32(0); i < 3; i++ {
+		tx.AddTxIn(bitcoin.OutPoint{Hash: prev.TxHash(), Index: i})
+	}
+	tx.AddTxOut(59000, p2wpkh)
+	p, err := New(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Inputs[0].NonWitnessUtxo = prev
+	p.Inputs[1].WitnessUtxo = prev.TxOut[1]
+	p.Inputs[2].WitnessUtxo = prev.TxOut[2]
+
+	if err := p.SignInput(ctx, 2, seckey); err != ErrScriptMismatch {
+		t.Errorf("SignInput() without redeem script error = %v, want %v", err, ErrScriptMismatch)
+	}
+	p.Inputs[2].RedeemScript = p2wpkh
+	for i := range p.Inputs {
+		if err := p.SignInput(ctx, i, seckey); err != nil {
+			t.Fatalf("SignInput(%d) error = %v", i, err)
+		}
+	}
+	if err := p.SignInput(ctx, 0, bytes.Repeat([]byte{0x22}, 32)); err != ErrKeyNotInScript {
+		t.Errorf("SignInput() with another key error = %v, want %v", err, ErrKeyNotInScript)
+	}
+	if err := p.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := p.Extract()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	in := signed.TxIn
+	if len(in[0].SignatureScript) == 0 || in[0].Witness != nil {
+		t.Errorf("P2PKH input = %x, %x", in[0].SignatureScript, in[0].Witness)
+	}
+	if in[1].SignatureScript == nil || len(in[1].SignatureScript) != 0 || len(in[1].Witness) != 2 {
+		t.Errorf("P2WPKH input = %x, %x", in[1].SignatureScript, in[1].Witness)
+	}
+	if !bytes.Equal(in[2].SignatureScript, pushData(nil, p2wpkh)) || len(in[2].Witness) != 2 {
+		t.Errorf("P2SH-P2WPKH input = %x, %x", in[2].SignatureScript, in[2].Witness)
+	}
+
+	sigHash, err := tx.WitnessV0SigHash(1, bitcoin.P2PKHScript(keyHash), 20000, bitcoin.SigHashAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := in[1].Witness[0]
+	if !bitcoin.VerifyECDSA(ctx, sigHash, sig[:len(sig)-1], pubKey) {
+		t.Errorf("P2WPKH signature does not verify")
+	}
+	if err := p.SignInput(ctx, 0, seckey); err != ErrFinalized {
+		t.Errorf("SignInput() of a finalized input error = %v, want %v", err, ErrFinalized)
+	}
+}
+
+func TestVersion2(t *testing.T) {
+	v := loadVectors(t)
+	// the unknown input fields of vectors 5 and 6 have the version 2 output index key type
+	packets := []string{v.Valid[0], v.Valid[1], v.Valid[2], v.Valid[3], v.Valid[4], v.Valid[7], v.Finalizer["finalize"], v.Finalizer["twoOfThree"]}
+	for i, s := range packets {
+		p := mustParseHex(t, s)
+		tx, err := p.UnsignedTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.ConvertVersion(Version2); err != nil {
+			t.Fatal(err)
+		}
+		v2 := mustParseHex(t, serializeHex(t, p))
+		if v2.Version != Version2 || len(v2.Inputs) != len(tx.TxIn) || len(v2.Outputs) != len(tx.TxOut) {
+			t.Fatalf("#%d: version 2 packet = %+v", i, v2)
+		}
+		v2Tx, err := v2.UnsignedTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(v2Tx.SerializeNoWitness(), tx.SerializeNoWitness()) {
+			t.Errorf("#%d: version 2 UnsignedTx() = %x, want %x", i, v2Tx.SerializeNoWitness(), tx.SerializeNoWitness())
+		}
+		if err := v2.ConvertVersion(Version0); err != nil {
+			t.Fatal(err)
+		}
+		if got := serializeHex(t, v2); got != s {
+			t.Errorf("#%d: version 0 round trip = %s, want %s", i, got, s)
+		}
+	}
+}
+
+func TestLockTime(t *testing.T) {
+	u32 := func(n uint32) *uint32 { return &n }
+	tests := []struct {
+		fallback *uint32
+		times    []*uint32
+		heights  []*uint32
+		want     uint32
+		err      error
+	}{
+		{nil, []*uint32{nil, nil}, []*uint32{nil, nil}, 0, nil},
+		{u32(10), []*uint32{nil, nil}, []*uint32{nil, nil}, 10, nil},
+		{u32(10), []*uint32{nil, nil}, []*uint32{u32(20), u32(30)}, 30, nil},
+		{nil, []*uint32{u32(500000001), nil}, []*uint32{nil, nil}, 500000001, nil},
+		// heights are chosen when both are possible
+		{nil, []*uint32{u32(500000001), nil}, []*uint32{u32(20), nil}, 20, nil},
+		{nil, []*uint32{u32(500000001), u32(500000002)}, []*uint32{u32(20), nil}, 500000002, nil},
+		{nil, []*uint32{u32(500000001), nil}, []*uint32{nil, u32(20)}, 0, ErrLockTimeConflict},
+	}
+	for i, tt := range tests {
+		p := &Packet{Version: Version2, TxVersion: 2, FallbackLocktime: tt.fallback}
+		for j := range tt.times {
+			p.Inputs = append(p.Inputs, &Input{
+				PreviousOutPoint:       bitcoin.OutPoint{Index: uint32(j)},
+				RequiredTimeLocktime:   tt.times[j],
+				RequiredHeightLocktime: tt.heights[j],
+			})
+		}
+		got, err := p.LockTime()
+		if got != tt.want || err != tt.err {
+			t.Errorf("#%d: LockTime() = %d, %v, want %d, %v", i, got, err, tt.want, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		parsed := mustParseHex(t, serializeHex(t, p))
+		if got, _ := parsed.LockTime(); got != tt.want {
+			t.Errorf("#%d: parsed LockTime() = %d, want %d", i, got, tt.want)
+		}
+	}
+}
+
+func TestParseVersionFields(t *testing.T) {
+	v := loadVectors(t)
+	serialize := func(p *Packet) []byte {
+		b, err := p.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	v0 := mustParseHex(t, v.Valid[0])
+	v0.Inputs[0].Unknowns = append(v0.Inputs[0].Unknowns, &Unknown{Key: []byte{inPreviousTxid}, Value: make([]byte, 32)})
+	if _, err := Parse(serialize(v0)); !errors.Is(err, ErrFieldVersion) {
+		t.Errorf("version 0 with a previous txid: Parse() error = %v, want %v", err, ErrFieldVersion)
+	}
+
+	v2 := mustParseHex(t, v.Valid[0])
+	if err := v2.ConvertVersion(Version2); err != nil {
+		t.Fatal(err)
+	}
+	v2.Unknowns = append(v2.Unknowns, &Unknown{Key: []byte{globalUnsignedTx}, Value: []byte{}})
+	if _, err := Parse(serialize(v2)); !errors.Is(err, ErrFieldVersion) {
+		t.Errorf("version 2 with an unsigned tx: Parse() error = %v, want %v", err, ErrFieldVersion)
+	}
+
+	v2 = mustParseHex(t, v.Valid[0])
+	if err := v2.ConvertVersion(Version2); err != nil {
+		t.Fatal(err)
+	}
+	v2.Inputs[0].Unknowns = append(v2.Inputs[0].Unknowns, &Unknown{Key: []byte{inRequiredHeightLocktime}, Value: bitcoin.AppendUint32(nil, 500000000)})
+	if _, err := Parse(serialize(v2)); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("version 2 with a time as height: Parse() error = %v, want %v", err, ErrInvalidValue)
+	}
+
+	// only the version 2 global
+	if _, err := Parse(append([]byte(magic), 0x01, globalVersion, 0x04, 0x02, 0x00, 0x00, 0x00, 0x00)); err != ErrMissingField {
+		t.Errorf("version 2 without fields: Parse() error = %v, want %v", err, ErrMissingField)
+	}
+	if _, err := Parse(append([]byte(magic), 0x01, globalVersion, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00)); err != ErrUnsupportedVersion {
+		t.Errorf("version 1: Parse() error = %v, want %v", err, ErrUnsupportedVersion)
+	}
+}
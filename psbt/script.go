@@ -0,0 +1,138 @@
+package psbt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/bitcoin"
+)
+
+const (
+	op0             = 0x00
+	op1             = 0x51
+	op16            = 0x60
+	opEqual         = 0x87
+	opCheckMultiSig = 0xae
+)
+
+func isP2PKH(script []byte) bool {
+	return len(script) == 25 && script[0] == bitcoin.OpDup && script[1] == bitcoin.OpHash160 && script[2] == 20 &&
+		script[23] == bitcoin.OpEqualVerify && script[24] == bitcoin.OpCheckSig
+}
+
+func isP2SH(script []byte) bool {
+	return len(script) == 23 && script[0] == bitcoin.OpHash160 && script[1] == 20 && script[22] == opEqual
+}
+
+func isP2WPKH(script []byte) bool {
+	return len(script) == 22 && script[0] == op0 && script[1] == 20
+}
+
+func isP2WSH(script []byte) bool {
+	return len(script) == 34 && script[0] == op0 && script[1] == 32
+}
+
+// parseMultisig returns the required signatures and the public keys of an OP_CHECKMULTISIG script.
+func parseMultisig(script []byte) (int, [][]byte, bool) {
+	if len(script) < 3 || script[len(script)-1] != opCheckMultiSig {
+		return 0, nil, false
+	}
+	m, n := int(script[0])-op1+1, int(script[len(script)-2])-op1+1
+	if m < 1 || m > 16 || n < m || n > 16 {
+		return 0, nil, false
+	}
+	pushes, ok := scriptPushes(script[1 : len(script)-2])
+	if !ok || len(pushes) != n {
+		return 0, nil, false
+	}
+	for _, key := range pushes {
+		if !validPubKey(key) {
+			return 0, nil, false
+		}
+	}
+	return m, pushes, true
+}
+
+// nextOp splits the first opcode and the data it pushes off the script.
+func nextOp(script []byte) (op byte, data []byte, rest []byte, ok bool) {
+	op, script = script[0], script[1:]
+	n := 0
+	switch {
+	case op < bitcoin.OpPushData1:
+		n = int(op)
+	case op == bitcoin.OpPushData1 && len(script) >= 1:
+		n, script = int(script[0]), script[1:]
+	case op == bitcoin.OpPushData2 && len(script) >= 2:
+		n, script = int(binary.LittleEndian.Uint16(script)), script[2:]
+	case op == bitcoin.OpPushData4 && len(script) >= 4:
+		n, script = int(binary.LittleEndian.Uint32(script)), script[4:]
+	case op > bitcoin.OpPushData4:
+		return op, nil, script, true
+	default:
+		return op, nil, nil, false
+	}
+	if n < 0 || len(script) < n {
+		return op, nil, nil, false
+	}
+	return op, script[:n], script[n:], true
+}
+
+// scriptPushes returns the pushed data of a script made of non-empty pushes only.
+func scriptPushes(script []byte) ([][]byte, bool) {
+	var pushes [][]byte
+	for len(script) > 0 {
+		op, data, rest, ok := nextOp(script)
+		if !ok || op == op0 || op > bitcoin.OpPushData4 {
+			return nil, false
+		}
+		pushes, script = append(pushes, data), rest
+	}
+	return pushes, true
+}
+
+// scriptHasKey reports whether the script pushes the public key.
+func scriptHasKey(script []byte, pubKey []byte) bool {
+	for len(script) > 0 {
+		_, data, rest, ok := nextOp(script)
+		if !ok {
+			return false
+		}
+		if bytes.Equal(data, pubKey) {
+			return true
+		}
+		script = rest
+	}
+	return false
+}
+
+// pushData appends the minimal push of the data.
+func pushData(script []byte, data []byte) []byte {
+	switch n := len(data); {
+	case n == 0:
+		return append(script, op0)
+	case n < bitcoin.OpPushData1:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, bitcoin.OpPushData1, byte(n))
+	case n <= 0xffff:
+		script = append(script, bitcoin.OpPushData2, byte(n), byte(n>>8))
+	default:
+		script = bitcoin.AppendUint32(append(script, bitcoin.OpPushData4), uint32(n))
+	}
+	return append(script, data...)
+}
+
+func hash160(data []byte) []byte {
+	hash, err := bip32.Hash160(data)
+	if err != nil {
+		return nil
+	}
+	return hash
+}
+
+func sha256Hash(data []byte) []byte {
+	hash := sha256.Sum256(data)
+	return hash[:]
+}
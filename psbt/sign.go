@@ -0,0 +1,173 @@
+package psbt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/dubuqingfeng/signer/bip32"
+	"github.com/dubuqingfeng/signer/bitcoin"
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var (
+	ErrInputIndex         = errors.New("input index out of range")
+	ErrMissingUtxo        = errors.New("input has no utxo to sign")
+	ErrScriptMismatch     = errors.New("redeem or witness script does not match the spent script")
+	ErrKeyNotInScript     = errors.New("public key is not in the spent script")
+	ErrFinalized          = errors.New("input is already finalized")
+	ErrDerivationMismatch = errors.New("derived public key does not match the bip32 derivation")
+)
+
+// spend is the script an input spends, resolved through its redeem and witness scripts.
+type spend struct {
+	utxo         *bitcoin.TxOut
+	redeemScript []byte
+	witness      bool
+	// scriptCode is the script signatures commit to: the previous output script,
+	// the redeem script or the witness script.
+	scriptCode []byte
+	// keyHash is the public key hash of P2PKH and P2WPKH scripts.
+	keyHash []byte
+}
+
+// SignInput adds the partial signature of the private key, whose compressed public key
+// must be in the spent script. Legacy inputs need the non-witness utxo.
+func (p *Packet) SignInput(ctx *secp256k1.Context, idx int, seckey []byte) error {
+	if idx < 0 || idx >= len(p.Inputs) {
+		return ErrInputIndex
+	}
+	in := p.Inputs[idx]
+	if in.isFinalized() {
+		return ErrFinalized
+	}
+	s, err := in.resolve()
+	if err != nil {
+		return err
+	}
+	if !s.witness && in.NonWitnessUtxo == nil {
+		return ErrMissingUtxo
+	}
+
+	pubKey, err := compressedPubKey(ctx, seckey)
+	if err != nil {
+		return err
+	}
+	if s.keyHash != nil && !bytes.Equal(hash160(pubKey), s.keyHash) ||
+		s.keyHash == nil && !scriptHasKey(s.scriptCode, pubKey) {
+		return ErrKeyNotInScript
+	}
+
+	tx, err := p.UnsignedTx()
+	if err != nil {
+		return err
+	}
+	hashType := in.SighashType
+	if hashType == 0 {
+		hashType = bitcoin.SigHashAll
+	}
+	var sig []byte
+	if s.witness {
+		sig, err = tx.SignWitnessV0Input(ctx, idx, s.scriptCode, s.utxo.Value, hashType, seckey)
+	} else {
+		sig, err = tx.SignLegacyInput(ctx, idx, s.scriptCode, hashType, seckey)
+	}
+	if err != nil {
+		return err
+	}
+	in.AddPartialSig(&PartialSig{PubKey: pubKey, Signature: sig})
+	return nil
+}
+
+// SignWithMasterKey signs every input with the keys of its bip32 derivations from the
+// master key and returns the number of signatures added.
+func (p *Packet) SignWithMasterKey(ctx *secp256k1.Context, master *bip32.PrivateKey) (int, error) {
+	fp, err := MasterFingerprint(master)
+	if err != nil {
+		return 0, err
+	}
+	signed := 0
+	for i, in := range p.Inputs {
+		if in.isFinalized() {
+			continue
+		}
+		for _, d := range in.DerivationsFor(fp) {
+			key, err := master.DeriveWithDerivationPath(bip32.NewDerivationPath(d.Path...))
+			if err != nil {
+				return signed, fmt.Errorf("input %d: %w", i, err)
+			}
+			if !bytes.Equal(key.ToPublicKeyBytes(), d.PubKey) {
+				return signed, fmt.Errorf("input %d: %w", i, ErrDerivationMismatch)
+			}
+			if err := p.SignInput(ctx, i, key.Data); err != nil {
+				return signed, fmt.Errorf("input %d: %w", i, err)
+			}
+			signed++
+		}
+	}
+	return signed, nil
+}
+
+// AddPartialSig adds the signature, replacing the one of the same public key.
+func (in *Input) AddPartialSig(sig *PartialSig) {
+	for i, existing := range in.PartialSigs {
+		if bytes.Equal(existing.PubKey, sig.PubKey) {
+			in.PartialSigs[i] = sig
+			return
+		}
+	}
+	in.PartialSigs = append(in.PartialSigs, sig)
+}
+
+// utxo returns the output the input spends.
+func (in *Input) utxo() (*bitcoin.TxOut, error) {
+	switch {
+	case in.WitnessUtxo != nil:
+		return in.WitnessUtxo, nil
+	case in.NonWitnessUtxo != nil:
+		if !in.utxoMatches() {
+			return nil, ErrUtxoMismatch
+		}
+		return in.NonWitnessUtxo.TxOut[in.PreviousOutPoint.Index], nil
+	}
+	return nil, ErrMissingUtxo
+}
+
+func (in *Input) resolve() (*spend, error) {
+	utxo, err := in.utxo()
+	if err != nil {
+		return nil, err
+	}
+	s := &spend{utxo: utxo}
+	script := utxo.PkScript
+	if isP2SH(script) {
+		if in.RedeemScript == nil || !bytes.Equal(hash160(in.RedeemScript), script[2:22]) {
+			return nil, ErrScriptMismatch
+		}
+		s.redeemScript, script = in.RedeemScript, in.RedeemScript
+	}
+	switch {
+	case isP2WPKH(script):
+		s.witness, s.keyHash = true, script[2:]
+		s.scriptCode = bitcoin.P2PKHScript(s.keyHash)
+	case isP2WSH(script):
+		if in.WitnessScript == nil || !bytes.Equal(sha256Hash(in.WitnessScript), script[2:]) {
+			return nil, ErrScriptMismatch
+		}
+		s.witness, s.scriptCode = true, in.WitnessScript
+	case isP2PKH(script):
+		s.keyHash, s.scriptCode = script[3:23], script
+	default:
+		s.scriptCode = script
+	}
+	return s, nil
+}
+
+func compressedPubKey(ctx *secp256k1.Context, seckey []byte) ([]byte, error) {
+	_, pk, err := secp256k1.EcPubkeyCreate(ctx, seckey)
+	if err != nil {
+		return nil, err
+	}
+	_, pubKey, err := secp256k1.EcPubkeySerialize(ctx, pk, secp256k1.EcCompressed)
+	return pubKey, err
+}
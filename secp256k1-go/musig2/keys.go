@@ -0,0 +1,166 @@
+// Package musig2 implements BIP-327 MuSig2 multi-signatures: several
+// signers aggregate their keys into one x-only public key and jointly
+// produce a BIP-340 Schnorr signature for it, in two rounds.
+package musig2
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+	"github.com/dubuqingfeng/signer/secp256k1-go/signer"
+)
+
+// PublicKeyLength is the length of the compressed public keys of the
+// signers.
+const PublicKeyLength = 33
+
+var (
+	ErrInvalidPublicKey = errors.New("invalid public key")
+	ErrNoPublicKeys     = errors.New("at least one public key is required")
+	ErrInvalidTweak     = errors.New("the tweak must be 32 bytes and less than n")
+	ErrInfinity         = errors.New("the result of tweaking cannot be infinity")
+)
+
+// ContributionError reports that a signer, or the aggregator when Signer is
+// -1, sent an invalid public key, nonce or partial signature.
+type ContributionError struct {
+	Signer int
+	Err    error
+}
+
+func (e *ContributionError) Error() string {
+	if e.Signer < 0 {
+		return fmt.Sprintf("aggregator: %v", e.Err)
+	}
+	return fmt.Sprintf("signer %d: %v", e.Signer, e.Err)
+}
+
+func (e *ContributionError) Unwrap() error {
+	return e.Err
+}
+
+// KeySort sorts the public keys lexicographically, which signers may use to
+// agree on an order independent of who joined first.
+func KeySort(pubkeys [][]byte) [][]byte {
+	sorted := append([][]byte(nil), pubkeys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	return sorted
+}
+
+// KeyAggContext holds the aggregate public key of a set of signers and the
+// tweaks applied to it so far.
+type KeyAggContext struct {
+	pubkeys [][]byte
+	q       *secp256k1.PublicKey
+	gacc    *big.Int
+	tacc    *big.Int
+}
+
+// KeyAgg aggregates the 33 bytes compressed public keys of the signers, in
+// the order given. An invalid key is reported as a *ContributionError.
+func KeyAgg(pubkeys [][]byte) (*KeyAggContext, error) {
+	if len(pubkeys) == 0 {
+		return nil, ErrNoPublicKeys
+	}
+	points := make([]*secp256k1.PublicKey, len(pubkeys))
+	for i, pk := range pubkeys {
+		p, ok := cpoint(pk)
+		if !ok {
+			return nil, &ContributionError{Signer: i, Err: ErrInvalidPublicKey}
+		}
+		points[i] = p
+	}
+
+	c := &KeyAggContext{
+		pubkeys: append([][]byte(nil), pubkeys...),
+		gacc:    big.NewInt(1),
+		tacc:    new(big.Int),
+	}
+	terms := make([]*secp256k1.PublicKey, len(points))
+	for i, p := range points {
+		terms[i] = pointMul(p, c.coefficient(pubkeys[i]))
+	}
+	c.q = pointAdd(terms...)
+	if c.q == nil {
+		return nil, ErrInfinity
+	}
+	return c, nil
+}
+
+// coefficient is KeyAggCoeff, the factor applied to pk in the aggregate.
+// The second distinct key gets 1, which saves a multiplication.
+func (c *KeyAggContext) coefficient(pk []byte) *big.Int {
+	second := make([]byte, PublicKeyLength)
+	for _, other := range c.pubkeys[1:] {
+		if !bytes.Equal(other, c.pubkeys[0]) {
+			second = other
+			break
+		}
+	}
+	if bytes.Equal(pk, second) {
+		return big.NewInt(1)
+	}
+	list := secp256k1.TaggedHash("KeyAgg list", c.pubkeys...)
+	return hashToScalar(secp256k1.TaggedHash("KeyAgg coefficient", list, pk))
+}
+
+// has reports whether pk is one of the aggregated keys.
+func (c *KeyAggContext) has(pk []byte) bool {
+	for _, other := range c.pubkeys {
+		if bytes.Equal(other, pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyTweak returns the context for the aggregate key tweaked by the 32
+// bytes tweak. An x-only tweak is applied to the key with an even Y, as
+// taproot does; a plain tweak is used for BIP-32 derivation.
+func (c *KeyAggContext) ApplyTweak(tweak []byte, xonly bool) (*KeyAggContext, error) {
+	if len(tweak) != 32 {
+		return nil, ErrInvalidTweak
+	}
+	t := new(big.Int).SetBytes(tweak)
+	if t.Cmp(curveOrder) >= 0 {
+		return nil, ErrInvalidTweak
+	}
+
+	q := c.q
+	g := big.NewInt(1)
+	if xonly && !hasEvenY(q) {
+		q = pointNegate(q)
+		g.Sub(curveOrder, g)
+	}
+	q, _ = cpoint(cbytes(q))
+	if _, err := secp256k1.EcPubkeyTweakAdd(signer.Context(), q, tweak); err != nil {
+		return nil, ErrInfinity
+	}
+
+	gacc := new(big.Int).Mul(g, c.gacc)
+	tacc := new(big.Int).Mul(g, c.tacc)
+	tacc.Add(tacc, t)
+	return &KeyAggContext{
+		pubkeys: c.pubkeys,
+		q:       q,
+		gacc:    gacc.Mod(gacc, curveOrder),
+		tacc:    tacc.Mod(tacc, curveOrder),
+	}, nil
+}
+
+// PublicKey returns the 33 bytes compressed aggregate key.
+func (c *KeyAggContext) PublicKey() []byte {
+	return cbytes(c.q)
+}
+
+// XOnlyPublicKey returns the 32 bytes x-only aggregate key, which the final
+// signature verifies against.
+func (c *KeyAggContext) XOnlyPublicKey() []byte {
+	return xbytes(c.q)
+}
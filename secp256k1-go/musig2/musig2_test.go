@@ -0,0 +1,208 @@
+package musig2
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+	"github.com/dubuqingfeng/signer/secp256k1-go/signer"
+)
+
+func schnorrVerify(t *testing.T, sig, msg, xonly []byte) bool {
+	t.Helper()
+	_, pk, err := secp256k1.XOnlyPubkeyParse(signer.Context(), xonly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ok, _ := secp256k1.SchnorrVerify(signer.Context(), sig, msg, pk)
+	return ok == 1
+}
+
+// party is a signer in the simulation, which only talks to the others
+// through channels.
+type party struct {
+	sk       []byte
+	pk       []byte
+	pubnonce chan PubNonce
+	psig     chan []byte
+}
+
+// runSession signs msg with every party in its own goroutine, as they would
+// over the network, and returns the aggregate signature.
+func runSession(t *testing.T, parties []*party, tweak []byte, msg []byte) ([]byte, *KeyAggContext) {
+	t.Helper()
+	pubkeys := make([][]byte, len(parties))
+	for i, p := range parties {
+		pubkeys[i] = p.pk
+	}
+	pubkeys = KeySort(pubkeys)
+
+	keyAggFor := func() (*KeyAggContext, error) {
+		keyAgg, err := KeyAgg(pubkeys)
+		if err != nil || tweak == nil {
+			return keyAgg, err
+		}
+		return keyAgg.ApplyTweak(tweak, true)
+	}
+
+	// Each party broadcasts its nonce, then its partial signature
+	errs := make(chan error, len(parties))
+	for _, p := range parties {
+		go func(p *party) {
+			keyAgg, err := keyAggFor()
+			if err != nil {
+				errs <- err
+				return
+			}
+			secnonce, pubnonce, err := NonceGen(p.pk, &NonceGenOptions{
+				SecretKey: p.sk,
+				AggPubKey: keyAgg.XOnlyPublicKey(),
+				Msg:       msg,
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			p.pubnonce <- pubnonce
+			pubnonces := make([]PubNonce, len(parties))
+			for i, other := range parties {
+				pubnonces[i] = <-other.pubnonce
+				other.pubnonce <- pubnonces[i]
+			}
+			aggnonce, err := NonceAgg(pubnonces)
+			if err != nil {
+				errs <- err
+				return
+			}
+			session, err := NewSession(aggnonce, keyAgg, msg)
+			if err != nil {
+				errs <- err
+				return
+			}
+			psig, err := session.Sign(secnonce, p.sk)
+			if err != nil {
+				errs <- err
+				return
+			}
+			p.psig <- psig
+			errs <- nil
+		}(p)
+	}
+
+	// The aggregator only observes the broadcast nonces and partial
+	// signatures
+	for range parties {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	pubnonces := make([]PubNonce, len(parties))
+	psigs := make([][]byte, len(parties))
+	for i, p := range parties {
+		pubnonces[i] = <-p.pubnonce
+		psigs[i] = <-p.psig
+	}
+	keyAgg, err := keyAggFor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggnonce, err := NonceAgg(pubnonces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := NewSession(aggnonce, keyAgg, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range parties {
+		if ok, err := session.Verify(psigs[i], pubnonces[i], p.pk); err != nil || !ok {
+			t.Errorf("Verify() of party %d = %v, %v, want true", i, ok, err)
+		}
+	}
+	sig, err := session.Aggregate(psigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig, keyAgg
+}
+
+func newParties(t *testing.T, n int) []*party {
+	t.Helper()
+	parties := make([]*party, n)
+	for i := range parties {
+		key, err := signer.GeneratePrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		parties[i] = &party{
+			sk: key.Serialize(),
+			pk: key.PubKey().SerializeCompressed(),
+			// Holds the nonce until every party has read it
+			pubnonce: make(chan PubNonce, 1),
+			psig:     make(chan []byte, 1),
+		}
+	}
+	return parties
+}
+
+func TestMultiPartySigning(t *testing.T) {
+	msg := sha256.Sum256([]byte("musig2"))
+	// A taproot output key commits to its internal key with an x-only tweak
+	tweak := sha256.Sum256([]byte("tap tweak"))
+	tests := []struct {
+		name    string
+		parties int
+		tweak   []byte
+	}{
+		{name: "single", parties: 1},
+		{name: "two", parties: 2},
+		{name: "five", parties: 5},
+		{name: "taproot", parties: 3, tweak: tweak[:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, keyAgg := runSession(t, newParties(t, tt.parties), tt.tweak, msg[:])
+			if !schnorrVerify(t, sig, msg[:], keyAgg.XOnlyPublicKey()) {
+				t.Error("the aggregate signature does not verify")
+			}
+			other := sha256.Sum256([]byte("other"))
+			if schnorrVerify(t, sig, other[:], keyAgg.XOnlyPublicKey()) {
+				t.Error("the aggregate signature verifies another message")
+			}
+		})
+	}
+}
+
+func TestSecNonceReuse(t *testing.T) {
+	parties := newParties(t, 2)
+	pubkeys := [][]byte{parties[0].pk, parties[1].pk}
+	keyAgg, err := KeyAgg(pubkeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secnonce, pubnonce0, err := NonceGen(parties[0].pk, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, pubnonce1, err := NonceGen(parties[1].pk, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aggnonce, err := NonceAgg([]PubNonce{pubnonce0, pubnonce1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := NewSession(aggnonce, keyAgg, []byte("msg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := session.Sign(secnonce, parties[1].sk); !errors.Is(err, ErrSecNonceMismatch) {
+		t.Errorf("Sign() with another key error = %v, want %v", err, ErrSecNonceMismatch)
+	}
+	// The failed attempt used the nonce up as well
+	if _, err := session.Sign(secnonce, parties[0].sk); !errors.Is(err, ErrInvalidSecNonce) {
+		t.Errorf("Sign() again error = %v, want %v", err, ErrInvalidSecNonce)
+	}
+}
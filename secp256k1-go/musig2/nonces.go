@@ -0,0 +1,172 @@
+package musig2
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var (
+	ErrInvalidPubNonce = errors.New("invalid public nonce")
+	ErrInvalidAggNonce = errors.New("invalid aggregate nonce")
+	ErrInvalidSecNonce = errors.New("invalid secret nonce, it may have been used already")
+	ErrNoPubNonces     = errors.New("at least one public nonce is required")
+)
+
+// SecNonce is the secret nonce of a signer, k1 || k2 || pk. It is zeroed
+// once used to sign, and must never be stored or sent to anyone.
+type SecNonce [97]byte
+
+// PubNonce is the public nonce a signer sends to the others in the first
+// round, the compressed points k1*G || k2*G.
+type PubNonce [66]byte
+
+// AggNonce is the sum of the public nonces of all signers.
+type AggNonce [66]byte
+
+// NonceGenOptions are optional inputs to NonceGen. Every input that is known
+// makes the nonce more robust against a broken random number generator.
+type NonceGenOptions struct {
+	// SecretKey is the 32 bytes secret key of the signer.
+	SecretKey []byte
+	// AggPubKey is the 32 bytes x-only aggregate public key.
+	AggPubKey []byte
+	// Msg is the message to sign. A nil Msg means the message is not
+	// known yet, whereas an empty one is signed as is.
+	Msg []byte
+	// ExtraIn is any extra data, such as a session id or a counter.
+	ExtraIn []byte
+	// Rand is the source of the 32 random bytes, crypto/rand by default.
+	Rand io.Reader
+}
+
+// NonceGen generates the nonce of the signer with the 33 bytes public key
+// pk for a single signing session.
+func NonceGen(pk []byte, opts *NonceGenOptions) (*SecNonce, PubNonce, error) {
+	var pubnonce PubNonce
+	if opts == nil {
+		opts = &NonceGenOptions{}
+	}
+	if len(pk) != PublicKeyLength {
+		return nil, pubnonce, ErrInvalidPublicKey
+	}
+	r := opts.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+	randBytes := make([]byte, 32)
+	if _, err := io.ReadFull(r, randBytes); err != nil {
+		return nil, pubnonce, err
+	}
+	return nonceGen(randBytes, pk, opts)
+}
+
+// nonceGen is NonceGen with the random bytes drawn already.
+func nonceGen(randBytes, pk []byte, opts *NonceGenOptions) (*SecNonce, PubNonce, error) {
+	var pubnonce PubNonce
+	if len(opts.SecretKey) > 0 {
+		if len(opts.SecretKey) != 32 {
+			return nil, pubnonce, ErrInvalidSecretKey
+		}
+		aux := secp256k1.TaggedHash("MuSig/aux", randBytes)
+		mixed := make([]byte, 32)
+		for i := range mixed {
+			mixed[i] = opts.SecretKey[i] ^ aux[i]
+		}
+		randBytes = mixed
+	}
+
+	var msgPrefixed []byte
+	if opts.Msg == nil {
+		msgPrefixed = []byte{0}
+	} else {
+		msgPrefixed = make([]byte, 9, 9+len(opts.Msg))
+		msgPrefixed[0] = 1
+		binary.BigEndian.PutUint64(msgPrefixed[1:], uint64(len(opts.Msg)))
+		msgPrefixed = append(msgPrefixed, opts.Msg...)
+	}
+	extraLen := make([]byte, 4)
+	binary.BigEndian.PutUint32(extraLen, uint32(len(opts.ExtraIn)))
+
+	secnonce := new(SecNonce)
+	for i := 0; i < 2; i++ {
+		hash := secp256k1.TaggedHash("MuSig/nonce",
+			randBytes,
+			[]byte{byte(len(pk))}, pk,
+			[]byte{byte(len(opts.AggPubKey))}, opts.AggPubKey,
+			msgPrefixed,
+			extraLen, opts.ExtraIn,
+			[]byte{byte(i)},
+		)
+		k := scalarBytes(hashToScalar(hash))
+		p := baseMul(k)
+		if p == nil {
+			return nil, pubnonce, ErrInvalidSecNonce
+		}
+		copy(secnonce[32*i:], k)
+		copy(pubnonce[33*i:], cbytes(p))
+	}
+	copy(secnonce[64:], pk)
+	return secnonce, pubnonce, nil
+}
+
+// ParsePubNonce parses the public nonce received from another signer.
+func ParsePubNonce(b []byte) (PubNonce, error) {
+	var pubnonce PubNonce
+	if len(b) != len(pubnonce) {
+		return pubnonce, ErrInvalidPubNonce
+	}
+	if _, ok := cpoint(b[:33]); !ok {
+		return pubnonce, ErrInvalidPubNonce
+	}
+	if _, ok := cpoint(b[33:]); !ok {
+		return pubnonce, ErrInvalidPubNonce
+	}
+	copy(pubnonce[:], b)
+	return pubnonce, nil
+}
+
+// NonceAgg sums the public nonces of all signers. An invalid nonce is
+// reported as a *ContributionError naming its signer.
+func NonceAgg(pubnonces []PubNonce) (AggNonce, error) {
+	var aggnonce AggNonce
+	if len(pubnonces) == 0 {
+		return aggnonce, ErrNoPubNonces
+	}
+	for j := 0; j < 2; j++ {
+		points := make([]*secp256k1.PublicKey, len(pubnonces))
+		for i, pubnonce := range pubnonces {
+			p, ok := cpoint(pubnonce[33*j : 33*(j+1)])
+			if !ok {
+				return aggnonce, &ContributionError{Signer: i, Err: ErrInvalidPubNonce}
+			}
+			points[i] = p
+		}
+		copy(aggnonce[33*j:], cbytesExt(pointAdd(points...)))
+	}
+	return aggnonce, nil
+}
+
+// values returns k1 and k2, or false if either is out of range.
+func (n *SecNonce) values() (k1, k2 []byte, ok bool) {
+	for i := 0; i < 2; i++ {
+		k := new(big.Int).SetBytes(n[32*i : 32*(i+1)])
+		if k.Sign() == 0 || k.Cmp(curveOrder) >= 0 {
+			return nil, nil, false
+		}
+	}
+	k1 = append([]byte(nil), n[:32]...)
+	k2 = append([]byte(nil), n[32:64]...)
+	return k1, k2, true
+}
+
+// zero wipes the nonce so that it cannot be used twice.
+func (n *SecNonce) zero() {
+	for i := range n {
+		n[i] = 0
+	}
+}
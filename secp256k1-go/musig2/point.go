@@ -0,0 +1,131 @@
+package musig2
+
+import (
+	"math/big"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+	"github.com/dubuqingfeng/signer/secp256k1-go/signer"
+)
+
+// Points are *secp256k1.PublicKey values, with nil standing for the point
+// at infinity which the secp256k1 package cannot represent. Scalars that
+// are public are math/big values, arithmetic on secrets goes through the
+// EcPrivkey functions instead.
+
+// curveOrder is n, the order of the secp256k1 group.
+var curveOrder, _ = new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+
+// hashToScalar reduces a hash modulo n.
+func hashToScalar(hash []byte) *big.Int {
+	s := new(big.Int).SetBytes(hash)
+	return s.Mod(s, curveOrder)
+}
+
+// scalarBytes returns the 32 bytes big endian encoding of s.
+func scalarBytes(s *big.Int) []byte {
+	return s.FillBytes(make([]byte, 32))
+}
+
+// cpoint parses a 33 bytes compressed point.
+func cpoint(b []byte) (*secp256k1.PublicKey, bool) {
+	if len(b) != 33 || (b[0] != 0x02 && b[0] != 0x03) {
+		return nil, false
+	}
+	_, p, err := secp256k1.EcPubkeyParse(signer.Context(), b)
+	return p, err == nil
+}
+
+// cpointExt is cpoint which also accepts 33 zero bytes for infinity.
+func cpointExt(b []byte) (*secp256k1.PublicKey, bool) {
+	if len(b) == 33 && isZero(b) {
+		return nil, true
+	}
+	return cpoint(b)
+}
+
+// cbytes returns the 33 bytes compressed encoding of p.
+func cbytes(p *secp256k1.PublicKey) []byte {
+	_, b, _ := secp256k1.EcPubkeySerialize(signer.Context(), p, secp256k1.EcCompressed)
+	return b
+}
+
+// cbytesExt is cbytes which encodes infinity as 33 zero bytes.
+func cbytesExt(p *secp256k1.PublicKey) []byte {
+	if p == nil {
+		return make([]byte, 33)
+	}
+	return cbytes(p)
+}
+
+// xbytes returns the 32 bytes X coordinate of p.
+func xbytes(p *secp256k1.PublicKey) []byte {
+	return cbytes(p)[1:]
+}
+
+func hasEvenY(p *secp256k1.PublicKey) bool {
+	return cbytes(p)[0] == 0x02
+}
+
+// pointAdd sums the points, any of which may be infinity.
+func pointAdd(points ...*secp256k1.PublicKey) *secp256k1.PublicKey {
+	var terms []*secp256k1.PublicKey
+	for _, p := range points {
+		if p != nil {
+			terms = append(terms, p)
+		}
+	}
+	if len(terms) == 0 {
+		return nil
+	}
+	// Combining only fails when the sum is infinity
+	_, sum, err := secp256k1.EcPubkeyCombine(signer.Context(), terms)
+	if err != nil {
+		return nil
+	}
+	return sum
+}
+
+// pointMul returns s*p without modifying p.
+func pointMul(p *secp256k1.PublicKey, s *big.Int) *secp256k1.PublicKey {
+	if p == nil || s.Sign() == 0 {
+		return nil
+	}
+	q, _ := cpoint(cbytes(p))
+	if _, err := secp256k1.EcPubkeyTweakMul(signer.Context(), q, scalarBytes(s)); err != nil {
+		return nil
+	}
+	return q
+}
+
+// pointNegate returns -p without modifying p.
+func pointNegate(p *secp256k1.PublicKey) *secp256k1.PublicKey {
+	if p == nil {
+		return nil
+	}
+	q, _ := cpoint(cbytes(p))
+	secp256k1.EcPubkeyNegate(signer.Context(), q)
+	return q
+}
+
+// baseMul returns s*G for a 32 bytes scalar, or infinity if s is zero or
+// not below n.
+func baseMul(s []byte) *secp256k1.PublicKey {
+	_, p, err := secp256k1.EcPubkeyCreate(signer.Context(), s)
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+func pointEqual(p, q *secp256k1.PublicKey) bool {
+	return string(cbytesExt(p)) == string(cbytesExt(q))
+}
+
+func isZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
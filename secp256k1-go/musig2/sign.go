@@ -0,0 +1,180 @@
+package musig2
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+	"github.com/dubuqingfeng/signer/secp256k1-go/signer"
+)
+
+var (
+	ErrInvalidSecretKey   = errors.New("invalid secret key")
+	ErrSecNonceMismatch   = errors.New("the secret nonce was not generated for this secret key")
+	ErrSignerNotIncluded  = errors.New("the signer's public key is not in the list of public keys")
+	ErrInvalidPartialSig  = errors.New("invalid partial signature")
+	ErrPartialSigMismatch = errors.New("the partial signature does not verify")
+)
+
+// Session is a signing session for a message, once the aggregate nonce of
+// all signers is known. It holds no secrets and may be shared.
+type Session struct {
+	keyAgg *KeyAggContext
+	msg    []byte
+	r1, r2 *secp256k1.PublicKey
+	b      *big.Int
+	r      *secp256k1.PublicKey
+	e      *big.Int
+}
+
+// NewSession starts the second round of signing msg with the aggregate
+// nonce and the, possibly tweaked, aggregate key.
+func NewSession(aggnonce AggNonce, keyAgg *KeyAggContext, msg []byte) (*Session, error) {
+	r1, ok1 := cpointExt(aggnonce[:33])
+	r2, ok2 := cpointExt(aggnonce[33:])
+	if !ok1 || !ok2 {
+		return nil, &ContributionError{Signer: -1, Err: ErrInvalidAggNonce}
+	}
+	q := keyAgg.XOnlyPublicKey()
+	b := hashToScalar(secp256k1.TaggedHash("MuSig/noncecoef", aggnonce[:], q, msg))
+	r := pointAdd(r1, pointMul(r2, b))
+	if r == nil {
+		// Nobody can force this, so falling back to G is safe
+		r = baseMul(scalarBytes(big.NewInt(1)))
+	}
+	e := hashToScalar(secp256k1.TaggedHash("BIP0340/challenge", xbytes(r), q, msg))
+	return &Session{
+		keyAgg: keyAgg,
+		msg:    append([]byte(nil), msg...),
+		r1:     r1,
+		r2:     r2,
+		b:      b,
+		r:      r,
+		e:      e,
+	}, nil
+}
+
+// g is 1 if the aggregate key has an even Y and n-1 otherwise.
+func (s *Session) g() *big.Int {
+	if hasEvenY(s.keyAgg.q) {
+		return big.NewInt(1)
+	}
+	return new(big.Int).Sub(curveOrder, big.NewInt(1))
+}
+
+// Sign returns the 32 bytes partial signature of the signer with the
+// secret key sk. The secret nonce is zeroed, so that it cannot be used
+// again even if signing fails.
+func (s *Session) Sign(secnonce *SecNonce, sk []byte) ([]byte, error) {
+	k1, k2, ok := secnonce.values()
+	pk := append([]byte(nil), secnonce[64:]...)
+	secnonce.zero()
+	if !ok {
+		return nil, ErrInvalidSecNonce
+	}
+	ctx := signer.Context()
+	if len(sk) != 32 {
+		return nil, ErrInvalidSecretKey
+	}
+	p := baseMul(sk)
+	if p == nil {
+		return nil, ErrInvalidSecretKey
+	}
+	if !bytes.Equal(cbytes(p), pk) {
+		return nil, ErrSecNonceMismatch
+	}
+	if !s.keyAgg.has(pk) {
+		return nil, ErrSignerNotIncluded
+	}
+
+	// Kept to check the result, as a fault while signing can leak sk
+	rs1, rs2 := baseMul(k1), baseMul(k2)
+
+	if !hasEvenY(s.r) {
+		secp256k1.EcPrivkeyNegate(ctx, k1)
+		secp256k1.EcPrivkeyNegate(ctx, k2)
+	}
+	d := append([]byte(nil), sk...)
+	gacc := new(big.Int).Mul(s.g(), s.keyAgg.gacc)
+	if gacc.Mod(gacc, curveOrder).Cmp(big.NewInt(1)) != 0 {
+		secp256k1.EcPrivkeyNegate(ctx, d)
+	}
+
+	// s = k1 + b*k2 + e*a*d, a zero intermediate value only happens with
+	// negligible probability
+	ea := new(big.Int).Mul(s.e, s.keyAgg.coefficient(pk))
+	ea.Mod(ea, curveOrder)
+	if _, err := secp256k1.EcPrivkeyTweakMul(ctx, d, scalarBytes(ea)); err != nil {
+		return nil, ErrInvalidPartialSig
+	}
+	if _, err := secp256k1.EcPrivkeyTweakMul(ctx, k2, scalarBytes(s.b)); err != nil {
+		return nil, ErrInvalidPartialSig
+	}
+	if _, err := secp256k1.EcPrivkeyTweakAdd(ctx, d, k2); err != nil {
+		return nil, ErrInvalidPartialSig
+	}
+	if _, err := secp256k1.EcPrivkeyTweakAdd(ctx, d, k1); err != nil {
+		return nil, ErrInvalidPartialSig
+	}
+	if !s.verify(d, rs1, rs2, p, pk) {
+		return nil, ErrPartialSigMismatch
+	}
+	return d, nil
+}
+
+// Verify checks the partial signature of the signer with the public nonce
+// and the 33 bytes public key pk. An invalid nonce or key is reported as an
+// error, a partial signature that does not verify as false.
+func (s *Session) Verify(psig []byte, pubnonce PubNonce, pk []byte) (bool, error) {
+	rs1, ok1 := cpoint(pubnonce[:33])
+	rs2, ok2 := cpoint(pubnonce[33:])
+	if !ok1 || !ok2 {
+		return false, ErrInvalidPubNonce
+	}
+	p, ok := cpoint(pk)
+	if !ok {
+		return false, ErrInvalidPublicKey
+	}
+	if !s.keyAgg.has(pk) {
+		return false, ErrSignerNotIncluded
+	}
+	return s.verify(psig, rs1, rs2, p, pk), nil
+}
+
+// verify checks s*G == Re + e*a*g*gacc*P, where Re is the effective
+// nonce of the signer.
+func (s *Session) verify(psig []byte, rs1, rs2, p *secp256k1.PublicKey, pk []byte) bool {
+	if len(psig) != 32 || new(big.Int).SetBytes(psig).Cmp(curveOrder) >= 0 {
+		return false
+	}
+	re := pointAdd(rs1, pointMul(rs2, s.b))
+	if !hasEvenY(s.r) {
+		re = pointNegate(re)
+	}
+	f := new(big.Int).Mul(s.e, s.keyAgg.coefficient(pk))
+	f.Mul(f, s.g())
+	f.Mul(f, s.keyAgg.gacc)
+	f.Mod(f, curveOrder)
+	return pointEqual(baseMul(psig), pointAdd(re, pointMul(p, f)))
+}
+
+// Aggregate sums the partial signatures of all signers into the 64 bytes
+// BIP-340 signature of the aggregate key. An out of range partial
+// signature is reported as a *ContributionError; the others are not
+// checked, use Verify to find a signer that misbehaved.
+func (s *Session) Aggregate(psigs [][]byte) ([]byte, error) {
+	sum := new(big.Int)
+	for i, psig := range psigs {
+		v := new(big.Int).SetBytes(psig)
+		if len(psig) != 32 || v.Cmp(curveOrder) >= 0 {
+			return nil, &ContributionError{Signer: i, Err: ErrInvalidPartialSig}
+		}
+		sum.Add(sum, v)
+	}
+	et := new(big.Int).Mul(s.e, s.g())
+	et.Mul(et, s.keyAgg.tacc)
+	sum.Add(sum, et)
+	sum.Mod(sum, curveOrder)
+	return append(xbytes(s.r), scalarBytes(sum)...), nil
+}
@@ -0,0 +1,512 @@
+package musig2
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// The vectors in testdata are the reference test vectors of BIP-327.
+
+type hexBytes []byte
+
+func (h *hexBytes) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*h = nil
+		return nil
+	}
+	v, err := hex.DecodeString(*s)
+	if err != nil {
+		return err
+	}
+	*h = append(hexBytes{}, v...)
+	return nil
+}
+
+type vectorError struct {
+	Type    string `json:"type"`
+	Signer  *int   `json:"signer"`
+	Contrib string `json:"contrib"`
+	Message string `json:"message"`
+}
+
+// valueErrors maps the messages of the reference implementation to the
+// errors of this package.
+var valueErrors = map[string]error{
+	"The tweak must be less than n.":                               ErrInvalidTweak,
+	"The result of tweaking cannot be infinity.":                   ErrInfinity,
+	"The signer's pubkey must be included in the list of pubkeys.": ErrSignerNotIncluded,
+	"first secnonce value is out of range.":                        ErrInvalidSecNonce,
+}
+
+func (v *vectorError) check(t *testing.T, err error) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("error = nil, want %+v", v)
+	}
+	switch v.Type {
+	case "invalid_contribution":
+		var contribErr *ContributionError
+		if !errors.As(err, &contribErr) {
+			t.Fatalf("error = %v, want a *ContributionError", err)
+		}
+		signer := -1
+		if v.Signer != nil {
+			signer = *v.Signer
+		}
+		if contribErr.Signer != signer {
+			t.Errorf("Signer = %d, want %d", contribErr.Signer, signer)
+		}
+	case "value":
+		if want := valueErrors[v.Message]; !errors.Is(err, want) {
+			t.Errorf("error = %v, want %v", err, want)
+		}
+	}
+}
+
+func loadVectors(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func pick(all []hexBytes, indices []int) [][]byte {
+	picked := make([][]byte, len(indices))
+	for i, j := range indices {
+		picked[i] = all[j]
+	}
+	return picked
+}
+
+func pickPubNonces(t *testing.T, all []hexBytes, indices []int) []PubNonce {
+	t.Helper()
+	pubnonces := make([]PubNonce, len(indices))
+	for i, j := range indices {
+		if len(all[j]) != len(pubnonces[i]) {
+			t.Fatalf("pubnonce %d has %d bytes", j, len(all[j]))
+		}
+		copy(pubnonces[i][:], all[j])
+	}
+	return pubnonces
+}
+
+func tweakedKeyAgg(pubkeys [][]byte, tweaks [][]byte, xonly []bool) (*KeyAggContext, error) {
+	keyAgg, err := KeyAgg(pubkeys)
+	if err != nil {
+		return nil, err
+	}
+	for i, tweak := range tweaks {
+		if keyAgg, err = keyAgg.ApplyTweak(tweak, xonly[i]); err != nil {
+			return nil, err
+		}
+	}
+	return keyAgg, nil
+}
+
+func TestKeySortVectors(t *testing.T) {
+	var vectors struct {
+		PubKeys       []hexBytes `json:"pubkeys"`
+		SortedPubKeys []hexBytes `json:"sorted_pubkeys"`
+	}
+	loadVectors(t, "key_sort_vectors.json", &vectors)
+
+	pubkeys := make([][]byte, len(vectors.PubKeys))
+	for i, pk := range vectors.PubKeys {
+		pubkeys[i] = pk
+	}
+	sorted := KeySort(pubkeys)
+	for i, want := range vectors.SortedPubKeys {
+		if !bytes.Equal(sorted[i], want) {
+			t.Errorf("KeySort()[%d] = %x, want %x", i, sorted[i], []byte(want))
+		}
+	}
+	if !bytes.Equal(pubkeys[0], vectors.PubKeys[0]) {
+		t.Error("KeySort() modified its input")
+	}
+}
+
+func TestKeyAggVectors(t *testing.T) {
+	var vectors struct {
+		PubKeys    []hexBytes `json:"pubkeys"`
+		Tweaks     []hexBytes `json:"tweaks"`
+		ValidCases []struct {
+			KeyIndices []int    `json:"key_indices"`
+			Expected   hexBytes `json:"expected"`
+		} `json:"valid_test_cases"`
+		ErrorCases []struct {
+			KeyIndices   []int       `json:"key_indices"`
+			TweakIndices []int       `json:"tweak_indices"`
+			IsXOnly      []bool      `json:"is_xonly"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "key_agg_vectors.json", &vectors)
+
+	for i, tc := range vectors.ValidCases {
+		keyAgg, err := KeyAgg(pick(vectors.PubKeys, tc.KeyIndices))
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if got := keyAgg.XOnlyPublicKey(); !bytes.Equal(got, tc.Expected) {
+			t.Errorf("case %d: XOnlyPublicKey() = %x, want %x", i, got, []byte(tc.Expected))
+		}
+	}
+	for _, tc := range vectors.ErrorCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			_, err := tweakedKeyAgg(pick(vectors.PubKeys, tc.KeyIndices),
+				pick(vectors.Tweaks, tc.TweakIndices), tc.IsXOnly)
+			tc.Error.check(t, err)
+		})
+	}
+}
+
+func TestNonceGenVectors(t *testing.T) {
+	var vectors struct {
+		Cases []struct {
+			Rand     hexBytes `json:"rand_"`
+			SK       hexBytes `json:"sk"`
+			PK       hexBytes `json:"pk"`
+			AggPK    hexBytes `json:"aggpk"`
+			Msg      hexBytes `json:"msg"`
+			ExtraIn  hexBytes `json:"extra_in"`
+			Expected hexBytes `json:"expected"`
+		} `json:"test_cases"`
+	}
+	loadVectors(t, "nonce_gen_vectors.json", &vectors)
+
+	for i, tc := range vectors.Cases {
+		secnonce, pubnonce, err := NonceGen(tc.PK, &NonceGenOptions{
+			SecretKey: tc.SK,
+			AggPubKey: tc.AggPK,
+			Msg:       tc.Msg,
+			ExtraIn:   tc.ExtraIn,
+			Rand:      bytes.NewReader(tc.Rand),
+		})
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(secnonce[:], tc.Expected) {
+			t.Errorf("case %d: secnonce = %x, want %x", i, secnonce[:], []byte(tc.Expected))
+		}
+		k1, k2 := baseMul(secnonce[:32]), baseMul(secnonce[32:64])
+		if want := append(cbytes(k1), cbytes(k2)...); !bytes.Equal(pubnonce[:], want) {
+			t.Errorf("case %d: pubnonce = %x, want %x", i, pubnonce[:], want)
+		}
+	}
+}
+
+func TestNonceAggVectors(t *testing.T) {
+	var vectors struct {
+		PubNonces  []hexBytes `json:"pnonces"`
+		ValidCases []struct {
+			PubNonceIndices []int    `json:"pnonce_indices"`
+			Expected        hexBytes `json:"expected"`
+		} `json:"valid_test_cases"`
+		ErrorCases []struct {
+			PubNonceIndices []int       `json:"pnonce_indices"`
+			Error           vectorError `json:"error"`
+			Comment         string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "nonce_agg_vectors.json", &vectors)
+
+	for i, tc := range vectors.ValidCases {
+		aggnonce, err := NonceAgg(pickPubNonces(t, vectors.PubNonces, tc.PubNonceIndices))
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(aggnonce[:], tc.Expected) {
+			t.Errorf("case %d: NonceAgg() = %x, want %x", i, aggnonce[:], []byte(tc.Expected))
+		}
+	}
+	for _, tc := range vectors.ErrorCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			_, err := NonceAgg(pickPubNonces(t, vectors.PubNonces, tc.PubNonceIndices))
+			tc.Error.check(t, err)
+		})
+	}
+}
+
+func TestSignVerifyVectors(t *testing.T) {
+	var vectors struct {
+		SK         hexBytes   `json:"sk"`
+		PubKeys    []hexBytes `json:"pubkeys"`
+		SecNonces  []hexBytes `json:"secnonces"`
+		PubNonces  []hexBytes `json:"pnonces"`
+		AggNonces  []hexBytes `json:"aggnonces"`
+		Msgs       []hexBytes `json:"msgs"`
+		ValidCases []struct {
+			KeyIndices    []int    `json:"key_indices"`
+			NonceIndices  []int    `json:"nonce_indices"`
+			AggNonceIndex int      `json:"aggnonce_index"`
+			MsgIndex      int      `json:"msg_index"`
+			SignerIndex   int      `json:"signer_index"`
+			Expected      hexBytes `json:"expected"`
+		} `json:"valid_test_cases"`
+		SignErrorCases []struct {
+			KeyIndices    []int       `json:"key_indices"`
+			AggNonceIndex int         `json:"aggnonce_index"`
+			MsgIndex      int         `json:"msg_index"`
+			SecNonceIndex int         `json:"secnonce_index"`
+			Error         vectorError `json:"error"`
+			Comment       string      `json:"comment"`
+		} `json:"sign_error_test_cases"`
+		VerifyFailCases []struct {
+			Sig          hexBytes `json:"sig"`
+			KeyIndices   []int    `json:"key_indices"`
+			NonceIndices []int    `json:"nonce_indices"`
+			MsgIndex     int      `json:"msg_index"`
+			SignerIndex  int      `json:"signer_index"`
+			Comment      string   `json:"comment"`
+		} `json:"verify_fail_test_cases"`
+		VerifyErrorCases []struct {
+			Sig          hexBytes    `json:"sig"`
+			KeyIndices   []int       `json:"key_indices"`
+			NonceIndices []int       `json:"nonce_indices"`
+			MsgIndex     int         `json:"msg_index"`
+			SignerIndex  int         `json:"signer_index"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"verify_error_test_cases"`
+	}
+	loadVectors(t, "sign_verify_vectors.json", &vectors)
+
+	secnonce := func(i int) *SecNonce {
+		var n SecNonce
+		copy(n[:], vectors.SecNonces[i])
+		return &n
+	}
+	session := func(t *testing.T, keyIndices []int, aggnonce []byte, msg []byte) (*Session, error) {
+		t.Helper()
+		keyAgg, err := KeyAgg(pick(vectors.PubKeys, keyIndices))
+		if err != nil {
+			return nil, err
+		}
+		var n AggNonce
+		copy(n[:], aggnonce)
+		return NewSession(n, keyAgg, msg)
+	}
+
+	for i, tc := range vectors.ValidCases {
+		pubnonces := pickPubNonces(t, vectors.PubNonces, tc.NonceIndices)
+		aggnonce, err := NonceAgg(pubnonces)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(aggnonce[:], vectors.AggNonces[tc.AggNonceIndex]) {
+			t.Errorf("case %d: NonceAgg() = %x, want %x", i, aggnonce[:], []byte(vectors.AggNonces[tc.AggNonceIndex]))
+		}
+		s, err := session(t, tc.KeyIndices, aggnonce[:], vectors.Msgs[tc.MsgIndex])
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		psig, err := s.Sign(secnonce(0), vectors.SK)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(psig, tc.Expected) {
+			t.Errorf("case %d: Sign() = %x, want %x", i, psig, []byte(tc.Expected))
+		}
+		ok, err := s.Verify(tc.Expected, pubnonces[tc.SignerIndex], vectors.PubKeys[tc.KeyIndices[tc.SignerIndex]])
+		if err != nil || !ok {
+			t.Errorf("case %d: Verify() = %v, %v, want true", i, ok, err)
+		}
+	}
+	for _, tc := range vectors.SignErrorCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			s, err := session(t, tc.KeyIndices, vectors.AggNonces[tc.AggNonceIndex], vectors.Msgs[tc.MsgIndex])
+			if err == nil {
+				_, err = s.Sign(secnonce(tc.SecNonceIndex), vectors.SK)
+			}
+			tc.Error.check(t, err)
+		})
+	}
+	for _, tc := range vectors.VerifyFailCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			pubnonces := pickPubNonces(t, vectors.PubNonces, tc.NonceIndices)
+			aggnonce, err := NonceAgg(pubnonces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s, err := session(t, tc.KeyIndices, aggnonce[:], vectors.Msgs[tc.MsgIndex])
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := s.Verify(tc.Sig, pubnonces[tc.SignerIndex], vectors.PubKeys[tc.KeyIndices[tc.SignerIndex]])
+			if err != nil || ok {
+				t.Errorf("Verify() = %v, %v, want false", ok, err)
+			}
+		})
+	}
+	for _, tc := range vectors.VerifyErrorCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			// Each contribution is checked as it arrives, before the
+			// session can be set up
+			for i, j := range tc.NonceIndices {
+				if _, err := ParsePubNonce(vectors.PubNonces[j]); err != nil {
+					tc.Error.check(t, &ContributionError{Signer: i, Err: err})
+					return
+				}
+			}
+			_, err := KeyAgg(pick(vectors.PubKeys, tc.KeyIndices))
+			tc.Error.check(t, err)
+		})
+	}
+}
+
+func TestTweakVectors(t *testing.T) {
+	var vectors struct {
+		SK         hexBytes   `json:"sk"`
+		PubKeys    []hexBytes `json:"pubkeys"`
+		SecNonce   hexBytes   `json:"secnonce"`
+		PubNonces  []hexBytes `json:"pnonces"`
+		AggNonce   hexBytes   `json:"aggnonce"`
+		Tweaks     []hexBytes `json:"tweaks"`
+		Msg        hexBytes   `json:"msg"`
+		ValidCases []struct {
+			KeyIndices   []int    `json:"key_indices"`
+			NonceIndices []int    `json:"nonce_indices"`
+			TweakIndices []int    `json:"tweak_indices"`
+			IsXOnly      []bool   `json:"is_xonly"`
+			SignerIndex  int      `json:"signer_index"`
+			Expected     hexBytes `json:"expected"`
+			Comment      string   `json:"comment"`
+		} `json:"valid_test_cases"`
+		ErrorCases []struct {
+			KeyIndices   []int       `json:"key_indices"`
+			TweakIndices []int       `json:"tweak_indices"`
+			IsXOnly      []bool      `json:"is_xonly"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "tweak_vectors.json", &vectors)
+
+	for _, tc := range vectors.ValidCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			keyAgg, err := tweakedKeyAgg(pick(vectors.PubKeys, tc.KeyIndices),
+				pick(vectors.Tweaks, tc.TweakIndices), tc.IsXOnly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pubnonces := pickPubNonces(t, vectors.PubNonces, tc.NonceIndices)
+			aggnonce, err := NonceAgg(pubnonces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(aggnonce[:], vectors.AggNonce) {
+				t.Errorf("NonceAgg() = %x, want %x", aggnonce[:], []byte(vectors.AggNonce))
+			}
+			s, err := NewSession(aggnonce, keyAgg, vectors.Msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var secnonce SecNonce
+			copy(secnonce[:], vectors.SecNonce)
+			psig, err := s.Sign(&secnonce, vectors.SK)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(psig, tc.Expected) {
+				t.Errorf("Sign() = %x, want %x", psig, []byte(tc.Expected))
+			}
+			ok, err := s.Verify(psig, pubnonces[tc.SignerIndex], vectors.PubKeys[tc.KeyIndices[tc.SignerIndex]])
+			if err != nil || !ok {
+				t.Errorf("Verify() = %v, %v, want true", ok, err)
+			}
+		})
+	}
+	for _, tc := range vectors.ErrorCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			_, err := tweakedKeyAgg(pick(vectors.PubKeys, tc.KeyIndices),
+				pick(vectors.Tweaks, tc.TweakIndices), tc.IsXOnly)
+			tc.Error.check(t, err)
+		})
+	}
+}
+
+func TestSigAggVectors(t *testing.T) {
+	var vectors struct {
+		PubKeys    []hexBytes `json:"pubkeys"`
+		PubNonces  []hexBytes `json:"pnonces"`
+		Tweaks     []hexBytes `json:"tweaks"`
+		PSigs      []hexBytes `json:"psigs"`
+		Msg        hexBytes   `json:"msg"`
+		ValidCases []struct {
+			AggNonce     hexBytes `json:"aggnonce"`
+			NonceIndices []int    `json:"nonce_indices"`
+			KeyIndices   []int    `json:"key_indices"`
+			TweakIndices []int    `json:"tweak_indices"`
+			IsXOnly      []bool   `json:"is_xonly"`
+			PSigIndices  []int    `json:"psig_indices"`
+			Expected     hexBytes `json:"expected"`
+		} `json:"valid_test_cases"`
+		ErrorCases []struct {
+			AggNonce     hexBytes    `json:"aggnonce"`
+			KeyIndices   []int       `json:"key_indices"`
+			TweakIndices []int       `json:"tweak_indices"`
+			IsXOnly      []bool      `json:"is_xonly"`
+			PSigIndices  []int       `json:"psig_indices"`
+			Error        vectorError `json:"error"`
+			Comment      string      `json:"comment"`
+		} `json:"error_test_cases"`
+	}
+	loadVectors(t, "sig_agg_vectors.json", &vectors)
+
+	session := func(t *testing.T, aggnonce []byte, keyIndices, tweakIndices []int, xonly []bool) *Session {
+		t.Helper()
+		keyAgg, err := tweakedKeyAgg(pick(vectors.PubKeys, keyIndices), pick(vectors.Tweaks, tweakIndices), xonly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var n AggNonce
+		copy(n[:], aggnonce)
+		s, err := NewSession(n, keyAgg, vectors.Msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	for i, tc := range vectors.ValidCases {
+		aggnonce, err := NonceAgg(pickPubNonces(t, vectors.PubNonces, tc.NonceIndices))
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(aggnonce[:], tc.AggNonce) {
+			t.Errorf("case %d: NonceAgg() = %x, want %x", i, aggnonce[:], []byte(tc.AggNonce))
+		}
+		s := session(t, tc.AggNonce, tc.KeyIndices, tc.TweakIndices, tc.IsXOnly)
+		sig, err := s.Aggregate(pick(vectors.PSigs, tc.PSigIndices))
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !bytes.Equal(sig, tc.Expected) {
+			t.Errorf("case %d: Aggregate() = %x, want %x", i, sig, []byte(tc.Expected))
+		}
+		if !schnorrVerify(t, sig, vectors.Msg, s.keyAgg.XOnlyPublicKey()) {
+			t.Errorf("case %d: the aggregate signature does not verify", i)
+		}
+	}
+	for _, tc := range vectors.ErrorCases {
+		t.Run(tc.Comment, func(t *testing.T) {
+			s := session(t, tc.AggNonce, tc.KeyIndices, tc.TweakIndices, tc.IsXOnly)
+			_, err := s.Aggregate(pick(vectors.PSigs, tc.PSigIndices))
+			tc.Error.check(t, err)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+//go:build cgo && !purego
+
+package secp256k1
+
+// #include "c-secp256k1/include/secp256k1.h"
+// #include "c-secp256k1/include/secp256k1_extrakeys.h"
+// #include "c-secp256k1/include/secp256k1_schnorrsig.h"
+/*
+// The batch helpers loop on the C side so a whole batch costs a single cgo
+// call. Inputs are passed as flat arrays, as cgo forbids passing Go memory
+// that holds Go pointers.
+static void ecdsaVerifyBatch(const secp256k1_context* ctx, int *results,
+        const secp256k1_ecdsa_signature *sigs, const unsigned char *msgs32,
+        const secp256k1_pubkey *pubkeys, size_t n) {
+        size_t i;
+        for (i = 0; i < n; i++) {
+                results[i] = secp256k1_ecdsa_verify(ctx, &sigs[i], msgs32 + 32*i, &pubkeys[i]);
+        }
+}
+static void schnorrsigVerifyBatch(const secp256k1_context* ctx, int *results,
+        const unsigned char *sigs64, const unsigned char *msgs, const size_t *msglens,
+        const secp256k1_xonly_pubkey *pubkeys, size_t n) {
+        size_t i, offset = 0;
+        for (i = 0; i < n; i++) {
+                results[i] = secp256k1_schnorrsig_verify(ctx, sigs64 + 64*i,
+                        msglens[i] == 0 ? NULL : msgs + offset, msglens[i], &pubkeys[i]);
+                offset += msglens[i];
+        }
+}
+*/
+import "C"
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EcdsaVerifyBatch verifies sigs[i] of msgs32[i] by pubkeys[i] for every
+// entry in a single call into libsecp256k1, returning the EcdsaVerify
+// return code of each entry. The slices must have the same length.
+func EcdsaVerifyBatch(ctx *Context, sigs []*EcdsaSignature, msgs32 [][]byte, pubkeys []*PublicKey) ([]int, error) {
+	n := len(sigs)
+	if len(msgs32) != n || len(pubkeys) != n {
+		return nil, errors.New(ErrorBatchSize)
+	}
+	if n == 0 {
+		return []int{}, nil
+	}
+
+	cSigs := make([]C.secp256k1_ecdsa_signature, n)
+	cMsgs := make([]byte, 0, n*LenMsgHash)
+	cPubkeys := make([]C.secp256k1_pubkey, n)
+	for i := 0; i < n; i++ {
+		if len(msgs32[i]) != LenMsgHash {
+			return nil, errors.New(ErrorMsg32Size)
+		}
+		cSigs[i] = *sigs[i].sig
+		cMsgs = append(cMsgs, msgs32[i]...)
+		cPubkeys[i] = *pubkeys[i].pk
+	}
+
+	cResults := make([]C.int, n)
+	C.ecdsaVerifyBatch(ctx.ctx, &cResults[0], &cSigs[0], cBuf(cMsgs), &cPubkeys[0], C.size_t(n))
+	return batchResults(cResults), nil
+}
+
+// SchnorrVerifyBatch verifies the BIP-340 signatures sigs[i] of msgs[i] by
+// pubkeys[i] for every entry in a single call into libsecp256k1, returning
+// the SchnorrVerify return code of each entry. The slices must have the
+// same length.
+func SchnorrVerifyBatch(ctx *Context, sigs [][]byte, msgs [][]byte, pubkeys []*XOnlyPublicKey) ([]int, error) {
+	n := len(sigs)
+	if len(msgs) != n || len(pubkeys) != n {
+		return nil, errors.New(ErrorBatchSize)
+	}
+	if n == 0 {
+		return []int{}, nil
+	}
+
+	cSigs := make([]byte, 0, n*LenSchnorrSig)
+	var cMsgs []byte
+	cMsgLens := make([]C.size_t, n)
+	cPubkeys := make([]C.secp256k1_xonly_pubkey, n)
+	for i := 0; i < n; i++ {
+		if len(sigs[i]) != LenSchnorrSig {
+			return nil, errors.New(ErrorSchnorrSigSize)
+		}
+		cSigs = append(cSigs, sigs[i]...)
+		cMsgs = append(cMsgs, msgs[i]...)
+		cMsgLens[i] = C.size_t(len(msgs[i]))
+		cPubkeys[i] = *pubkeys[i].pk
+	}
+
+	cResults := make([]C.int, n)
+	C.schnorrsigVerifyBatch(ctx.ctx, &cResults[0], cBuf(cSigs), msgBuf(cMsgs), &cMsgLens[0], &cPubkeys[0], C.size_t(n))
+	return batchResults(cResults), nil
+}
+
+func batchResults(cResults []C.int) []int {
+	results := make([]int, len(cResults))
+	for i, r := range cResults {
+		results[i] = int(r)
+	}
+	return results
+}
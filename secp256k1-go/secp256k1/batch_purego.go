@@ -0,0 +1,49 @@
+//go:build !cgo || purego
+
+package secp256k1
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EcdsaVerifyBatch verifies sigs[i] of msgs32[i] by pubkeys[i] for every
+// entry, returning the EcdsaVerify return code of each entry. The slices
+// must have the same length.
+func EcdsaVerifyBatch(ctx *Context, sigs []*EcdsaSignature, msgs32 [][]byte, pubkeys []*PublicKey) ([]int, error) {
+	n := len(sigs)
+	if len(msgs32) != n || len(pubkeys) != n {
+		return nil, errors.New(ErrorBatchSize)
+	}
+	for i := 0; i < n; i++ {
+		if len(msgs32[i]) != LenMsgHash {
+			return nil, errors.New(ErrorMsg32Size)
+		}
+	}
+
+	results := make([]int, n)
+	for i := 0; i < n; i++ {
+		results[i], _ = EcdsaVerify(ctx, sigs[i], msgs32[i], pubkeys[i])
+	}
+	return results, nil
+}
+
+// SchnorrVerifyBatch verifies the BIP-340 signatures sigs[i] of msgs[i] by
+// pubkeys[i] for every entry, returning the SchnorrVerify return code of
+// each entry. The slices must have the same length.
+func SchnorrVerifyBatch(ctx *Context, sigs [][]byte, msgs [][]byte, pubkeys []*XOnlyPublicKey) ([]int, error) {
+	n := len(sigs)
+	if len(msgs) != n || len(pubkeys) != n {
+		return nil, errors.New(ErrorBatchSize)
+	}
+	for i := 0; i < n; i++ {
+		if len(sigs[i]) != LenSchnorrSig {
+			return nil, errors.New(ErrorSchnorrSigSize)
+		}
+	}
+
+	results := make([]int, n)
+	for i := 0; i < n; i++ {
+		results[i], _ = SchnorrVerify(ctx, sigs[i], msgs[i], pubkeys[i])
+	}
+	return results, nil
+}
@@ -0,0 +1,101 @@
+package secp256k1
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEcdsaVerifyBatch(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	n := 16
+	sigs := make([]*EcdsaSignature, n)
+	msgs := make([][]byte, n)
+	pubkeys := make([]*PublicKey, n)
+	for i := 0; i < n; i++ {
+		priv := testingRand(32)
+		msgs[i] = testingRand(32)
+
+		r, pk, err := EcPubkeyCreate(ctx, priv)
+		spOK(t, r, err)
+		pubkeys[i] = pk
+
+		r, sig, err := EcdsaSign(ctx, msgs[i], priv)
+		spOK(t, r, err)
+		sigs[i] = sig
+	}
+	// Break a few entries: wrong message, wrong key, swapped signature
+	msgs[3] = testingRand(32)
+	pubkeys[7] = pubkeys[8]
+	sigs[12] = sigs[11]
+
+	results, err := EcdsaVerifyBatch(ctx, sigs, msgs, pubkeys)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		expected, err := EcdsaVerify(ctx, sigs[i], msgs[i], pubkeys[i])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, results[i], desc(i))
+		assert.Equal(t, i != 3 && i != 7 && i != 12, results[i] == 1, desc(i))
+	}
+
+	results, err = EcdsaVerifyBatch(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = EcdsaVerifyBatch(ctx, sigs, msgs[1:], pubkeys)
+	assert.Equal(t, ErrorBatchSize, err.Error())
+
+	msgs[5] = []byte(`a`)
+	_, err = EcdsaVerifyBatch(ctx, sigs, msgs, pubkeys)
+	assert.Equal(t, ErrorMsg32Size, err.Error())
+}
+
+func TestSchnorrVerifyBatch(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	n := 16
+	sigs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	pubkeys := make([]*XOnlyPublicKey, n)
+	for i := 0; i < n; i++ {
+		// Messages of every length from 0 to n-1 bytes
+		msgs[i] = testingRand(i)
+
+		r, keypair, err := KeypairCreate(ctx, testingRand(32))
+		spOK(t, r, err)
+
+		r, xonly, _, err := KeypairXOnlyPub(ctx, keypair)
+		spOK(t, r, err)
+		pubkeys[i] = xonly
+
+		r, sig, err := SchnorrSign(ctx, msgs[i], keypair, nil)
+		spOK(t, r, err)
+		sigs[i] = sig
+	}
+	msgs[0] = []byte(`a`)
+	pubkeys[9] = pubkeys[10]
+	sigs[15] = append([]byte{}, sigs[15]...)
+	sigs[15][63] ^= 1
+
+	results, err := SchnorrVerifyBatch(ctx, sigs, msgs, pubkeys)
+	assert.NoError(t, err)
+	for i := 0; i < n; i++ {
+		expected, err := SchnorrVerify(ctx, sigs[i], msgs[i], pubkeys[i])
+		assert.NoError(t, err)
+		assert.Equal(t, expected, results[i], desc(i))
+		assert.Equal(t, i != 0 && i != 9 && i != 15, results[i] == 1, desc(i))
+	}
+
+	_, err = SchnorrVerifyBatch(ctx, sigs, msgs, pubkeys[1:])
+	assert.Equal(t, ErrorBatchSize, err.Error())
+
+	sigs[2] = []byte(`a`)
+	_, err = SchnorrVerifyBatch(ctx, sigs, msgs, pubkeys)
+	assert.Equal(t, ErrorSchnorrSigSize, err.Error())
+}
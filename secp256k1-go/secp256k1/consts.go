@@ -0,0 +1,71 @@
+package secp256k1
+
+const (
+	/** Flags to pass to secp256k1_context_create, as in secp256k1.h. */
+	ContextVerify = uint(0x101)
+	ContextSign   = uint(0x201)
+
+	// Flags for EcPubkeySerialize
+	EcCompressed   = uint(0x102)
+	EcUncompressed = uint(0x002)
+
+	// Length of elements byte representations
+	LenCompressed   int = 33
+	LenUncompressed int = 65
+	LenMsgHash      int = 32
+	LenPrivateKey   int = 32
+	LenCompactSig   int = 64
+	LenMaxDerSig    int = 72
+
+	// Errors returned by functions
+	ErrorPrivateKeyNull                string = "Private key cannot be null"
+	ErrorPrivateKeyInvalid             string = "Invalid private key"
+	ErrorPublicKeyNull                 string = "Public key cannot be null"
+	ErrorEcdsaSignatureNull            string = "Signature cannot be null"
+	ErrorEcdsaRecoverableSignatureNull string = "Recoverable signature" +
+		" cannot be null"
+	ErrorEcdh             string = "Unable to do ECDH"
+	ErrorPublicKeyCreate  string = "Unable to produce public key"
+	ErrorPublicKeyCombine string = "Unable to combine public keys"
+
+	ErrorTweakSize      string = "Tweak must be exactly 32 bytes"
+	ErrorMsg32Size      string = "Message hash must be exactly 32 bytes"
+	ErrorPrivateKeySize string = "Private key must be exactly 32 bytes"
+	ErrorPublicKeySize  string = "Public key must be 33 or 65 bytes"
+
+	ErrorTweakingPublicKey  string = "Unable to tweak this public key"
+	ErrorTweakingPrivateKey string = "Unable to tweak this private key"
+
+	ErrorProducingSignature            string = "Unable to produce signature"
+	ErrorProducingRecoverableSignature string = "Unable to produce recoverable signature"
+
+	ErrorCompactSigSize  string = "Compact signature must be exactly 64 bytes"
+	ErrorCompactSigParse string = "Unable to parse this compact signature"
+
+	ErrorDerSigParse string = "Unable to parse this DER signature"
+
+	ErrorNonceDataSize string = "Nonce data must be exactly 32 bytes"
+
+	ErrorBatchSize string = "Batch slices must have the same length"
+
+	ErrorRecoverableSigParse string = "Unable to parse this recoverable signature"
+	ErrorRecoveryFailed      string = "Failed to recover public key"
+
+	ErrorPublicKeyParse string = "Unable to parse this public key"
+
+	// Length of BIP-340 byte representations
+	LenXOnlyPublicKey int = 32
+	LenSchnorrSig     int = 64
+	LenAuxRand        int = 32
+
+	// Errors returned by the extrakeys and schnorrsig functions
+	ErrorXOnlyPublicKeySize  string = "X-only public key must be exactly 32 bytes"
+	ErrorXOnlyPublicKeyParse string = "Unable to parse this x-only public key"
+	ErrorKeypairCreate       string = "Unable to produce keypair"
+	ErrorTweakingKeypair     string = "Unable to tweak this keypair"
+	ErrorTweakCheck          string = "Tweaked public key does not match"
+
+	ErrorSchnorrSigSize            string = "Schnorr signature must be exactly 64 bytes"
+	ErrorAuxRandSize               string = "Auxiliary randomness must be exactly 32 bytes"
+	ErrorProducingSchnorrSignature string = "Unable to produce schnorr signature"
+)
@@ -0,0 +1,121 @@
+package secp256k1
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EcdsaSignatureIsLowS reports whether S is at most half the curve order,
+// as required by EcdsaVerify and by Bitcoin standardness rules.
+func EcdsaSignatureIsLowS(ctx *Context, sig *EcdsaSignature) bool {
+	r, _, _ := EcdsaSignatureNormalize(ctx, sig)
+	return r == 0
+}
+
+// EcdsaSignatureParseDerLax parses a signature that is only loosely DER,
+// as found in transactions from before BIP-66. It is a port of
+// contrib/lax_der_parsing.c: length bytes may be padded or use the long
+// form, integers may be negative or zero padded, and trailing garbage is
+// ignored. The return code is 1 when the structure could be parsed, 0
+// otherwise. R or S values that do not fit in 32 bytes or are out of
+// range result in a zero signature, which fails verification for every
+// message and public key.
+func EcdsaSignatureParseDerLax(ctx *Context, signature []byte) (int, *EcdsaSignature, error) {
+	rpos, rlen, pos, ok := laxDerInteger(signature, 0, true)
+	if !ok {
+		return 0, nil, errors.New(ErrorDerSigParse)
+	}
+	spos, slen, _, ok := laxDerInteger(signature, pos, false)
+	if !ok {
+		return 0, nil, errors.New(ErrorDerSigParse)
+	}
+
+	var compact [64]byte
+	overflow := !laxDerCopy(compact[:32], signature[rpos:rpos+rlen]) ||
+		!laxDerCopy(compact[32:], signature[spos:spos+slen])
+	if !overflow {
+		if r, sig, err := EcdsaSignatureParseCompact(ctx, compact[:]); r == 1 && err == nil {
+			return 1, sig, nil
+		}
+	}
+	_, sig, err := EcdsaSignatureParseCompact(ctx, make([]byte, LenCompactSig))
+	if err != nil {
+		return 0, nil, err
+	}
+	return 1, sig, nil
+}
+
+// laxDerInteger reads the integer tag and length at pos, preceded by the
+// sequence header when first is set. It returns the position and length of
+// the integer contents and the position following them.
+func laxDerInteger(input []byte, pos int, first bool) (int, int, int, bool) {
+	if first {
+		// Sequence tag byte, then its length which is skipped entirely
+		if pos == len(input) || input[pos] != 0x30 {
+			return 0, 0, 0, false
+		}
+		pos++
+		if pos == len(input) {
+			return 0, 0, 0, false
+		}
+		lenbyte := int(input[pos])
+		pos++
+		if lenbyte&0x80 != 0 {
+			lenbyte -= 0x80
+			if pos+lenbyte > len(input) {
+				return 0, 0, 0, false
+			}
+			pos += lenbyte
+		}
+	}
+
+	// Integer tag byte
+	if pos == len(input) || input[pos] != 0x02 {
+		return 0, 0, 0, false
+	}
+	pos++
+
+	// Integer length
+	if pos == len(input) {
+		return 0, 0, 0, false
+	}
+	lenbyte := int(input[pos])
+	pos++
+	length := lenbyte
+	if lenbyte&0x80 != 0 {
+		lenbyte -= 0x80
+		if pos+lenbyte > len(input) {
+			return 0, 0, 0, false
+		}
+		for lenbyte > 0 && input[pos] == 0 {
+			pos++
+			lenbyte--
+		}
+		// The C code rejects lengths of sizeof(size_t) bytes or more, which
+		// is 8 on the 64-bit targets this package builds for
+		if lenbyte >= 8 {
+			return 0, 0, 0, false
+		}
+		length = 0
+		for ; lenbyte > 0; lenbyte-- {
+			length = length<<8 + int(input[pos])
+			pos++
+		}
+	}
+	if length < 0 || length > len(input)-pos {
+		return 0, 0, 0, false
+	}
+	return pos, length, pos + length, true
+}
+
+// laxDerCopy right aligns value without its leading zeros into out,
+// returning false if it does not fit.
+func laxDerCopy(out []byte, value []byte) bool {
+	for len(value) > 0 && value[0] == 0 {
+		value = value[1:]
+	}
+	if len(value) > len(out) {
+		return false
+	}
+	copy(out[len(out)-len(value):], value)
+	return true
+}
@@ -0,0 +1,69 @@
+package secp256k1
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// EcdsaSignOptions tunes the RFC6979 nonce used by EcdsaSignWithOptions.
+type EcdsaSignOptions struct {
+	// NonceData is nil, or 32 bytes of extra entropy added to the RFC6979
+	// seed. The signature stays deterministic for a given NonceData, while
+	// fresh random bytes protect against a signer leaking the key through
+	// a covert channel in the nonce.
+	NonceData []byte
+
+	// LowR grinds the nonce until R is below half the field, so the DER
+	// signature is at most 70 bytes, like Bitcoin Core does. After the
+	// first attempt a counter is xored, little endian, into the first four
+	// bytes of NonceData (or of 32 zero bytes when NonceData is nil).
+	LowR bool
+}
+
+// EcdsaSignWithOptions creates an ECDSA signature like EcdsaSign, using the
+// RFC6979 nonce function with the extra entropy and grinding requested by
+// opts, which may be nil. The signature is always in lower-S form. Return
+// code is 1 if the signature was created, or is zero and the error is set
+// if the private key was invalid.
+func EcdsaSignWithOptions(ctx *Context, msg32 []byte, seckey []byte, opts *EcdsaSignOptions) (int, *EcdsaSignature, error) {
+	if len(msg32) != LenMsgHash {
+		return 0, nil, errors.New(ErrorMsg32Size)
+	}
+	if len(seckey) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorPrivateKeySize)
+	}
+	if opts == nil {
+		opts = &EcdsaSignOptions{}
+	}
+	if opts.NonceData != nil && len(opts.NonceData) != 32 {
+		return 0, nil, errors.New(ErrorNonceDataSize)
+	}
+
+	result, sig := ecdsaSignNdata(ctx, msg32, seckey, opts.NonceData)
+	if result != 1 {
+		return result, nil, errors.New(ErrorProducingSignature)
+	}
+	if !opts.LowR {
+		return result, sig, nil
+	}
+
+	ndata := make([]byte, 32)
+	copy(ndata, opts.NonceData)
+	base := binary.LittleEndian.Uint32(ndata)
+	for counter := uint32(1); !ecdsaSignatureHasLowR(ctx, sig); counter++ {
+		binary.LittleEndian.PutUint32(ndata, base^counter)
+		result, sig = ecdsaSignNdata(ctx, msg32, seckey, ndata)
+		if result != 1 {
+			return result, nil, errors.New(ErrorProducingSignature)
+		}
+	}
+	return result, sig, nil
+}
+
+// ecdsaSignatureHasLowR reports whether R fits in 32 DER bytes without a
+// sign padding byte.
+func ecdsaSignatureHasLowR(ctx *Context, sig *EcdsaSignature) bool {
+	_, compact, _ := EcdsaSignatureSerializeCompact(ctx, sig)
+	return compact[0] < 0x80
+}
@@ -0,0 +1,118 @@
+package secp256k1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEcdsaSignWithOptionsRFC6979(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	priv, _ := hex.DecodeString("0000000000000000000000000000000000000000000000000000000000000001")
+	extra := sha256.Sum256([]byte("extra entropy"))
+
+	r, pub, err := EcPubkeyCreate(ctx, priv)
+	spOK(t, r, err)
+
+	// Expected values computed with an independent RFC6979 implementation
+	// seeded with seckey || msg32 || ndata, as libsecp256k1 does.
+	fixtures := []struct {
+		msg     string
+		opts    *EcdsaSignOptions
+		compact string
+	}{
+		{"Satoshi Nakamoto", nil,
+			"934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d82442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"},
+		{"Satoshi Nakamoto", &EcdsaSignOptions{NonceData: extra[:]},
+			"184dfcd0ec211fa61ed8b06dc464070c6388d0d89ee574d7ba40f2945b22f6b17228c58bc3303b8d2ddf43b1e0073368e3148b299a5caca0cdee9937c8b5fb80"},
+		// Low R already on the first attempt
+		{"low r 7", &EcdsaSignOptions{LowR: true},
+			"3f5037904b8e1fb95700ef269c3a0715bcdd0137bc58bf993ad8a2aa7ca982b0666389cf5a1053681709398b9ac5ab8c782592714dbc9800a256eeba244eadaa"},
+		// Two and four grinding rounds
+		{"low r 5", &EcdsaSignOptions{LowR: true},
+			"2a74de2c133a75e4152fb5570961eda7bb422ca59f3966e89d9b165a6f34167d42d116aa06a2b69413ab4edaa817fb06d33eab9814f561c0d6a1d8515b5d620c"},
+		{"low r 1", &EcdsaSignOptions{LowR: true},
+			"610f828a26ebb13f709961f11fcc3fd2d449fb203165e52b16bd88ae34c74b147d9d7076f8a662ee8107a041b51969b28f129d670b7c9e4d7e349c0c1ae737bf"},
+		{"low r 1", &EcdsaSignOptions{NonceData: extra[:], LowR: true},
+			"3b66aa2604953a0baa62d7cb7a46ad4042e3980dfc9d5bd2df5b48493ba82da64fda7d3c4aeec0425258bbee6ddc8304e976e2dfe87d912a2d880d6a9d61528f"},
+	}
+
+	for i, fixture := range fixtures {
+		msg32 := sha256.Sum256([]byte(fixture.msg))
+
+		r, sig, err := EcdsaSignWithOptions(ctx, msg32[:], priv, fixture.opts)
+		spOK(t, r, err)
+
+		_, compact, err := EcdsaSignatureSerializeCompact(ctx, sig)
+		assert.NoError(t, err, desc(i))
+		assert.Equal(t, fixture.compact, hex.EncodeToString(compact), desc(i))
+
+		r, err = EcdsaVerify(ctx, sig, msg32[:], pub)
+		spOK(t, r, err)
+	}
+
+	// Without options the result matches EcdsaSign
+	msg32 := sha256.Sum256([]byte("low r 1"))
+	r, expected, err := EcdsaSign(ctx, msg32[:], priv)
+	spOK(t, r, err)
+	r, sig, err := EcdsaSignWithOptions(ctx, msg32[:], priv, &EcdsaSignOptions{})
+	spOK(t, r, err)
+	assert.Equal(t, expected, sig)
+}
+
+func TestEcdsaSignWithOptionsLowR(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < 32; i++ {
+		msg32 := testingRand(32)
+		priv := testingRand(32)
+
+		r, pub, err := EcPubkeyCreate(ctx, priv)
+		spOK(t, r, err)
+
+		r, sig, err := EcdsaSignWithOptions(ctx, msg32, priv, &EcdsaSignOptions{NonceData: testingRand(32), LowR: true})
+		spOK(t, r, err)
+
+		r, der, err := EcdsaSignatureSerializeDer(ctx, sig)
+		spOK(t, r, err)
+		assert.True(t, len(der) <= 70, desc(i))
+		assert.True(t, der[4] < 0x80, desc(i))
+
+		r, err = EcdsaVerify(ctx, sig, msg32, pub)
+		spOK(t, r, err)
+	}
+}
+
+func TestEcdsaSignWithOptionsErrors(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	msg32 := testingRand(32)
+	priv := testingRand(32)
+	badKey, _ := hex.DecodeString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+
+	r, sig, err := EcdsaSignWithOptions(ctx, msg32, priv, &EcdsaSignOptions{NonceData: []byte(`a`)})
+	assert.Equal(t, 0, r)
+	assert.Nil(t, sig)
+	assert.Equal(t, ErrorNonceDataSize, err.Error())
+
+	r, sig, err = EcdsaSignWithOptions(ctx, []byte(`a`), priv, nil)
+	assert.Equal(t, 0, r)
+	assert.Nil(t, sig)
+	assert.Equal(t, ErrorMsg32Size, err.Error())
+
+	r, sig, err = EcdsaSignWithOptions(ctx, msg32, badKey, &EcdsaSignOptions{LowR: true})
+	assert.Equal(t, 0, r)
+	assert.Nil(t, sig)
+	assert.Equal(t, ErrorProducingSignature, err.Error())
+}
@@ -3,6 +3,7 @@ package secp256k1
 import (
 	"encoding/hex"
 	"github.com/stretchr/testify/assert"
+	"math/big"
 	"testing"
 )
 
@@ -167,3 +168,122 @@ func TestSignReturnsAnError(t *testing.T) {
 	assert.Nil(t, sig)
 	assert.Equal(t, ErrorProducingSignature, err.Error())
 }
+
+func TestEcdsaSignatureNormalize(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	msg32 := testingRand(32)
+	priv := testingRand(32)
+
+	r, pub, err := EcPubkeyCreate(ctx, priv)
+	spOK(t, r, err)
+
+	r, sig, err := EcdsaSign(ctx, msg32, priv)
+	spOK(t, r, err)
+	assert.True(t, EcdsaSignatureIsLowS(ctx, sig))
+
+	// Already normalized signatures come back unchanged
+	r, normalized, err := EcdsaSignatureNormalize(ctx, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, r)
+	assert.Equal(t, sig, normalized)
+
+	// Build the high-S twin (r, n-s) of the signature
+	_, compact, err := EcdsaSignatureSerializeCompact(ctx, sig)
+	assert.NoError(t, err)
+	n, _ := new(big.Int).SetString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141", 16)
+	highS := new(big.Int).Sub(n, new(big.Int).SetBytes(compact[32:]))
+	highCompact := append([]byte{}, compact[:32]...)
+	highCompact = append(highCompact, highS.FillBytes(make([]byte, 32))...)
+
+	r, high, err := EcdsaSignatureParseCompact(ctx, highCompact)
+	spOK(t, r, err)
+	assert.False(t, EcdsaSignatureIsLowS(ctx, high))
+
+	r, err = EcdsaVerify(ctx, high, msg32, pub)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, r)
+
+	r, normalized, err = EcdsaSignatureNormalize(ctx, high)
+	spOK(t, r, err)
+	assert.True(t, EcdsaSignatureIsLowS(ctx, normalized))
+	assert.False(t, EcdsaSignatureIsLowS(ctx, high))
+
+	_, normalizedCompact, err := EcdsaSignatureSerializeCompact(ctx, normalized)
+	assert.NoError(t, err)
+	assert.Equal(t, compact, normalizedCompact)
+
+	r, err = EcdsaVerify(ctx, normalized, msg32, pub)
+	spOK(t, r, err)
+}
+
+func TestEcdsaSignatureParseDerLax(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	sigR := "fe5fe404f3d8c21e1204a08c38ff3912d43c5a22541d2f1cdc4977cbcad24001"
+	sigS := "5a3b6e9040f62cacf016df4fef9412091592e4908e5e3a7bd2a42a4d1be01951"
+	order := "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"
+	zero := "0000000000000000000000000000000000000000000000000000000000000000"
+
+	fixtures := []struct {
+		der     string
+		strict  bool
+		compact string
+	}{
+		// Strict DER
+		{"3045022100" + sigR + "0220" + sigS, true, sigR + sigS},
+		// Wrong sequence length and trailing garbage
+		{"3000022100" + sigR + "0220" + sigS + "0102", false, sigR + sigS},
+		// Long form integer lengths with zero padding
+		{"304a02820021" + "00" + sigR + "02820020" + sigS, false, sigR + sigS},
+		// Negative R, and excess zero padding in S
+		{"30440220" + sigR + "022200" + "00" + sigS, false, sigR + sigS},
+		// R does not fit in 32 bytes, valid DER but out of range
+		{"3045022101" + sigR + "0220" + sigS, true, zero + zero},
+		// R is not below the order
+		{"3045022100" + order + "0220" + sigS, true, zero + zero},
+	}
+
+	for i, fixture := range fixtures {
+		der, _ := hex.DecodeString(fixture.der)
+
+		r, sig, err := EcdsaSignatureParseDerLax(ctx, der)
+		spOK(t, r, err)
+		if sig == nil {
+			continue
+		}
+
+		_, compact, err := EcdsaSignatureSerializeCompact(ctx, sig)
+		assert.NoError(t, err, desc(i))
+		assert.Equal(t, fixture.compact, hex.EncodeToString(compact), desc(i))
+
+		r, _, _ = EcdsaSignatureParseDer(ctx, der)
+		assert.Equal(t, fixture.strict, r == 1, desc(i))
+	}
+
+	malformed := []string{
+		"",
+		"31",
+		"30",
+		"3045022100" + sigR,
+		"3045032100" + sigR + "0220" + sigS,
+		"3045022100" + sigR + "0221" + sigS,
+		"3045028900" + sigR + "0220" + sigS,
+		"3045028401000000" + sigR + "0220" + sigS,
+	}
+	for i, m := range malformed {
+		der, _ := hex.DecodeString(m)
+
+		r, sig, err := EcdsaSignatureParseDerLax(ctx, der)
+		assert.Error(t, err, desc(i))
+		assert.Equal(t, 0, r, desc(i))
+		assert.Nil(t, sig, desc(i))
+		assert.Equal(t, ErrorDerSigParse, err.Error(), desc(i))
+	}
+}
@@ -0,0 +1,221 @@
+//go:build cgo && !purego
+
+package secp256k1
+
+// #include <stdlib.h>
+// #include "c-secp256k1/include/secp256k1.h"
+// #include "c-secp256k1/include/secp256k1_extrakeys.h"
+// #include "c-secp256k1/include/secp256k1_schnorrsig.h"
+/*
+// sign_custom takes the aux randomness through an extraparams struct, which
+// is easier to build on the C side.
+static int schnorrsigSign(const secp256k1_context* ctx, unsigned char *sig64,
+        const unsigned char *msg, size_t msglen,
+        const secp256k1_keypair *keypair, unsigned char *aux32) {
+        secp256k1_schnorrsig_extraparams extraparams = SECP256K1_SCHNORRSIG_EXTRAPARAMS_INIT;
+        extraparams.ndata = aux32;
+        return secp256k1_schnorrsig_sign_custom(ctx, sig64, msg, msglen, keypair, &extraparams);
+}
+*/
+import "C"
+
+import (
+	"github.com/pkg/errors"
+)
+
+// XOnlyPublicKey wraps a *secp256k1_xonly_pubkey, a public key whose Y
+// coordinate is implicitly even, as used by BIP-340 and taproot.
+type XOnlyPublicKey struct {
+	pk *C.secp256k1_xonly_pubkey
+}
+
+// Keypair wraps a *secp256k1_keypair, which caches the public key next
+// to the secret key so signing does not have to recompute it.
+type Keypair struct {
+	kp *C.secp256k1_keypair
+}
+
+func newXOnlyPublicKey() *XOnlyPublicKey {
+	return &XOnlyPublicKey{
+		pk: &C.secp256k1_xonly_pubkey{},
+	}
+}
+
+func newKeypair() *Keypair {
+	return &Keypair{
+		kp: &C.secp256k1_keypair{},
+	}
+}
+
+// Begin bindings for secp256k1_extrakeys.h
+
+// XOnlyPubkeyParse parses a 32-byte x-only public key. The return code is
+// 1 if the bytes are the X coordinate of a point on the curve, and 0
+// otherwise.
+func XOnlyPubkeyParse(ctx *Context, input32 []byte) (int, *XOnlyPublicKey, error) {
+	if len(input32) != LenXOnlyPublicKey {
+		return 0, nil, errors.New(ErrorXOnlyPublicKeySize)
+	}
+
+	pk := newXOnlyPublicKey()
+	result := int(C.secp256k1_xonly_pubkey_parse(ctx.ctx, pk.pk, cBuf(input32)))
+	if result != 1 {
+		return result, nil, errors.New(ErrorXOnlyPublicKeyParse)
+	}
+	return result, pk, nil
+}
+
+// XOnlyPubkeySerialize serializes an x-only public key into its 32-byte
+// X coordinate. The return code is always 1.
+func XOnlyPubkeySerialize(ctx *Context, pubkey *XOnlyPublicKey) (int, []byte, error) {
+	output := make([]C.uchar, LenXOnlyPublicKey)
+	result := int(C.secp256k1_xonly_pubkey_serialize(ctx.ctx, &output[0], pubkey.pk))
+	return result, goBytes(output, C.int(LenXOnlyPublicKey)), nil
+}
+
+// XOnlyPubkeyFromPubkey converts a public key into an x-only public key,
+// also returning the parity of the original Y coordinate (1 if it was
+// odd). The return code is always 1.
+func XOnlyPubkeyFromPubkey(ctx *Context, pubkey *PublicKey) (int, *XOnlyPublicKey, int, error) {
+	xonly := newXOnlyPublicKey()
+	parity := C.int(0)
+	result := int(C.secp256k1_xonly_pubkey_from_pubkey(ctx.ctx, xonly.pk, &parity, pubkey.pk))
+	return result, xonly, int(parity), nil
+}
+
+// XOnlyPubkeyTweakAdd computes internal + tweak*G and returns it as a full
+// public key, as done when deriving a taproot output key from its internal
+// key. The return code is 0 if the tweak was out of range or the result
+// would be the point at infinity, and 1 otherwise.
+func XOnlyPubkeyTweakAdd(ctx *Context, internal *XOnlyPublicKey, tweak []byte) (int, *PublicKey, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorTweakSize)
+	}
+
+	output := newPublicKey()
+	result := int(C.secp256k1_xonly_pubkey_tweak_add(ctx.ctx, output.pk, internal.pk, cBuf(tweak)))
+	if result != 1 {
+		return result, nil, errors.New(ErrorTweakingPublicKey)
+	}
+	return result, output, nil
+}
+
+// XOnlyPubkeyTweakAddCheck checks that tweakedPubkey32 with the given Y
+// parity is the result of tweaking internal by tweak. This is how a
+// taproot script path spend proves the output key commits to a script.
+// The return code is 1 if the check succeeded, 0 otherwise.
+func XOnlyPubkeyTweakAddCheck(ctx *Context, tweakedPubkey32 []byte, tweakedParity int,
+	internal *XOnlyPublicKey, tweak []byte) (int, error) {
+	if len(tweakedPubkey32) != LenXOnlyPublicKey {
+		return 0, errors.New(ErrorXOnlyPublicKeySize)
+	}
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+
+	result := int(C.secp256k1_xonly_pubkey_tweak_add_check(ctx.ctx, cBuf(tweakedPubkey32),
+		C.int(tweakedParity), internal.pk, cBuf(tweak)))
+	if result != 1 {
+		return result, errors.New(ErrorTweakCheck)
+	}
+	return result, nil
+}
+
+// KeypairCreate computes the keypair for a secret key. The return code is
+// 1 if the secret was valid, and 0 otherwise.
+func KeypairCreate(ctx *Context, seckey []byte) (int, *Keypair, error) {
+	if len(seckey) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorPrivateKeySize)
+	}
+
+	keypair := newKeypair()
+	result := int(C.secp256k1_keypair_create(ctx.ctx, keypair.kp, cBuf(seckey)))
+	if result != 1 {
+		return result, nil, errors.New(ErrorKeypairCreate)
+	}
+	return result, keypair, nil
+}
+
+// KeypairSec returns the secret key held by the keypair. The return code
+// is always 1.
+func KeypairSec(ctx *Context, keypair *Keypair) (int, []byte, error) {
+	seckey := make([]C.uchar, LenPrivateKey)
+	result := int(C.secp256k1_keypair_sec(ctx.ctx, &seckey[0], keypair.kp))
+	return result, goBytes(seckey, C.int(LenPrivateKey)), nil
+}
+
+// KeypairPub returns the full public key held by the keypair. The return
+// code is always 1.
+func KeypairPub(ctx *Context, keypair *Keypair) (int, *PublicKey, error) {
+	pk := newPublicKey()
+	result := int(C.secp256k1_keypair_pub(ctx.ctx, pk.pk, keypair.kp))
+	return result, pk, nil
+}
+
+// KeypairXOnlyPub returns the x-only public key held by the keypair and
+// the parity of its full Y coordinate. The return code is always 1.
+func KeypairXOnlyPub(ctx *Context, keypair *Keypair) (int, *XOnlyPublicKey, int, error) {
+	xonly := newXOnlyPublicKey()
+	parity := C.int(0)
+	result := int(C.secp256k1_keypair_xonly_pub(ctx.ctx, xonly.pk, &parity, keypair.kp))
+	return result, xonly, int(parity), nil
+}
+
+// KeypairXOnlyTweakAdd tweaks the keypair in place so that its x-only
+// public key matches XOnlyPubkeyTweakAdd of the original x-only public
+// key. The return code is 0 if the tweak was out of range or the result
+// would be invalid, and 1 otherwise.
+func KeypairXOnlyTweakAdd(ctx *Context, keypair *Keypair, tweak []byte) (int, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+
+	result := int(C.secp256k1_keypair_xonly_tweak_add(ctx.ctx, keypair.kp, cBuf(tweak)))
+	if result != 1 {
+		return result, errors.New(ErrorTweakingKeypair)
+	}
+	return result, nil
+}
+
+// Begin bindings for secp256k1_schnorrsig.h
+
+// SchnorrSign creates a BIP-340 signature of msg, which may be of any
+// length although it is normally a 32-byte hash. auxRand is optional: pass
+// nil, or 32 fresh random bytes to protect against side-channel attacks.
+// The return code is 1 if the signature was created, and 0 otherwise.
+func SchnorrSign(ctx *Context, msg []byte, keypair *Keypair, auxRand []byte) (int, []byte, error) {
+	var aux *C.uchar
+	if auxRand != nil {
+		if len(auxRand) != LenAuxRand {
+			return 0, nil, errors.New(ErrorAuxRandSize)
+		}
+		aux = cBuf(auxRand)
+	}
+
+	sig := make([]C.uchar, LenSchnorrSig)
+	result := int(C.schnorrsigSign(ctx.ctx, &sig[0], msgBuf(msg), C.size_t(len(msg)), keypair.kp, aux))
+	if result != 1 {
+		return result, nil, errors.New(ErrorProducingSchnorrSignature)
+	}
+	return result, goBytes(sig, C.int(LenSchnorrSig)), nil
+}
+
+// SchnorrVerify verifies a BIP-340 signature of msg against an x-only
+// public key. The return code is 1 for a correct signature, or 0 if
+// incorrect.
+func SchnorrVerify(ctx *Context, sig64 []byte, msg []byte, pubkey *XOnlyPublicKey) (int, error) {
+	if len(sig64) != LenSchnorrSig {
+		return 0, errors.New(ErrorSchnorrSigSize)
+	}
+
+	result := int(C.secp256k1_schnorrsig_verify(ctx.ctx, cBuf(sig64), msgBuf(msg), C.size_t(len(msg)), pubkey.pk))
+	return result, nil
+}
+
+// msgBuf is cBuf for messages that are allowed to be empty.
+func msgBuf(msg []byte) *C.uchar {
+	if len(msg) == 0 {
+		return nil
+	}
+	return cBuf(msg)
+}
@@ -0,0 +1,294 @@
+//go:build !cgo || purego
+
+package secp256k1
+
+import (
+	dcrec "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/pkg/errors"
+)
+
+// XOnlyPublicKey holds a point whose Y coordinate is even, as used by
+// BIP-340 and taproot.
+type XOnlyPublicKey struct {
+	pk PublicKey
+}
+
+// Keypair holds a secret key together with its public key.
+type Keypair struct {
+	sk dcrec.ModNScalar
+	pk PublicKey
+}
+
+func newXOnlyPublicKey() *XOnlyPublicKey {
+	return &XOnlyPublicKey{
+		pk: *newPublicKey(),
+	}
+}
+
+func newKeypair() *Keypair {
+	return &Keypair{
+		pk: *newPublicKey(),
+	}
+}
+
+// evenY negates the point if its Y coordinate is odd, returning whether
+// it did. The point must be affine.
+func evenY(point *dcrec.JacobianPoint) int {
+	if !point.Y.IsOdd() {
+		return 0
+	}
+	point.Y.Negate(1).Normalize()
+	return 1
+}
+
+// Begin secp256k1_extrakeys.h
+
+// XOnlyPubkeyParse parses a 32-byte x-only public key. The return code is
+// 1 if the bytes are the X coordinate of a point on the curve, and 0
+// otherwise.
+func XOnlyPubkeyParse(ctx *Context, input32 []byte) (int, *XOnlyPublicKey, error) {
+	if len(input32) != LenXOnlyPublicKey {
+		return 0, nil, errors.New(ErrorXOnlyPublicKeySize)
+	}
+
+	var x, y dcrec.FieldVal
+	if x.SetByteSlice(input32) || !dcrec.DecompressY(&x, false, &y) {
+		return 0, nil, errors.New(ErrorXOnlyPublicKeyParse)
+	}
+	pk := newXOnlyPublicKey()
+	pk.pk.pk = dcrec.NewPublicKey(&x, &y)
+	return 1, pk, nil
+}
+
+// XOnlyPubkeySerialize serializes an x-only public key into its 32-byte
+// X coordinate. The return code is always 1.
+func XOnlyPubkeySerialize(ctx *Context, pubkey *XOnlyPublicKey) (int, []byte, error) {
+	var point dcrec.JacobianPoint
+	output := make([]byte, LenXOnlyPublicKey)
+	if !pubkey.pk.load(&point) {
+		return 0, output, nil
+	}
+	point.X.PutBytesUnchecked(output)
+	return 1, output, nil
+}
+
+// XOnlyPubkeyFromPubkey converts a public key into an x-only public key,
+// also returning the parity of the original Y coordinate (1 if it was
+// odd). The return code is always 1.
+func XOnlyPubkeyFromPubkey(ctx *Context, pubkey *PublicKey) (int, *XOnlyPublicKey, int, error) {
+	var point dcrec.JacobianPoint
+	xonly := newXOnlyPublicKey()
+	if !pubkey.load(&point) {
+		return 0, xonly, 0, nil
+	}
+	parity := evenY(&point)
+	xonly.pk.save(&point)
+	return 1, xonly, parity, nil
+}
+
+// XOnlyPubkeyTweakAdd computes internal + tweak*G and returns it as a full
+// public key, as done when deriving a taproot output key from its internal
+// key. The return code is 0 if the tweak was out of range or the result
+// would be the point at infinity, and 1 otherwise.
+func XOnlyPubkeyTweakAdd(ctx *Context, internal *XOnlyPublicKey, tweak []byte) (int, *PublicKey, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorTweakSize)
+	}
+
+	var point dcrec.JacobianPoint
+	if !internal.pk.load(&point) || !pubkeyTweakAdd(&point, tweak) {
+		return 0, nil, errors.New(ErrorTweakingPublicKey)
+	}
+	output := newPublicKey()
+	output.save(&point)
+	return 1, output, nil
+}
+
+// XOnlyPubkeyTweakAddCheck checks that tweakedPubkey32 with the given Y
+// parity is the result of tweaking internal by tweak. This is how a
+// taproot script path spend proves the output key commits to a script.
+// The return code is 1 if the check succeeded, 0 otherwise.
+func XOnlyPubkeyTweakAddCheck(ctx *Context, tweakedPubkey32 []byte, tweakedParity int,
+	internal *XOnlyPublicKey, tweak []byte) (int, error) {
+	if len(tweakedPubkey32) != LenXOnlyPublicKey {
+		return 0, errors.New(ErrorXOnlyPublicKeySize)
+	}
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+
+	var point dcrec.JacobianPoint
+	if !internal.pk.load(&point) || !pubkeyTweakAdd(&point, tweak) {
+		return 0, errors.New(ErrorTweakCheck)
+	}
+	point.ToAffine()
+
+	var x dcrec.FieldVal
+	if x.SetByteSlice(tweakedPubkey32) || !x.Equals(&point.X) ||
+		boolToInt(point.Y.IsOdd()) != tweakedParity {
+		return 0, errors.New(ErrorTweakCheck)
+	}
+	return 1, nil
+}
+
+// KeypairCreate computes the keypair for a secret key. The return code is
+// 1 if the secret was valid, and 0 otherwise.
+func KeypairCreate(ctx *Context, seckey []byte) (int, *Keypair, error) {
+	if len(seckey) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorPrivateKeySize)
+	}
+
+	sec, ok := seckeyLoad(seckey)
+	if !ok {
+		return 0, nil, errors.New(ErrorKeypairCreate)
+	}
+	var point dcrec.JacobianPoint
+	dcrec.ScalarBaseMultNonConst(&sec, &point)
+
+	keypair := newKeypair()
+	keypair.sk = sec
+	keypair.pk.save(&point)
+	return 1, keypair, nil
+}
+
+// KeypairSec returns the secret key held by the keypair. The return code
+// is always 1.
+func KeypairSec(ctx *Context, keypair *Keypair) (int, []byte, error) {
+	seckey := keypair.sk.Bytes()
+	return 1, seckey[:], nil
+}
+
+// KeypairPub returns the full public key held by the keypair. The return
+// code is always 1.
+func KeypairPub(ctx *Context, keypair *Keypair) (int, *PublicKey, error) {
+	pk := newPublicKey()
+	pk.pk = keypair.pk.pk
+	return 1, pk, nil
+}
+
+// KeypairXOnlyPub returns the x-only public key held by the keypair and
+// the parity of its full Y coordinate. The return code is always 1.
+func KeypairXOnlyPub(ctx *Context, keypair *Keypair) (int, *XOnlyPublicKey, int, error) {
+	return XOnlyPubkeyFromPubkey(ctx, &keypair.pk)
+}
+
+// KeypairXOnlyTweakAdd tweaks the keypair in place so that its x-only
+// public key matches XOnlyPubkeyTweakAdd of the original x-only public
+// key. The return code is 0 if the tweak was out of range or the result
+// would be invalid, and 1 otherwise.
+func KeypairXOnlyTweakAdd(ctx *Context, keypair *Keypair, tweak []byte) (int, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+
+	var point dcrec.JacobianPoint
+	ok := keypair.pk.load(&point)
+	sec := keypair.sk
+	if ok && evenY(&point) == 1 {
+		sec.Negate()
+	}
+	var term dcrec.ModNScalar
+	ok = ok && !term.SetByteSlice(tweak)
+	sec.Add(&term)
+	ok = ok && !sec.IsZero() && pubkeyTweakAdd(&point, tweak)
+
+	*keypair = *newKeypair()
+	if !ok {
+		return 0, errors.New(ErrorTweakingKeypair)
+	}
+	keypair.sk = sec
+	keypair.pk.save(&point)
+	return 1, nil
+}
+
+// Begin secp256k1_schnorrsig.h
+
+// SchnorrSign creates a BIP-340 signature of msg, which may be of any
+// length although it is normally a 32-byte hash. auxRand is optional: pass
+// nil, or 32 fresh random bytes to protect against side-channel attacks.
+// The return code is 1 if the signature was created, and 0 otherwise.
+func SchnorrSign(ctx *Context, msg []byte, keypair *Keypair, auxRand []byte) (int, []byte, error) {
+	if auxRand != nil && len(auxRand) != LenAuxRand {
+		return 0, nil, errors.New(ErrorAuxRandSize)
+	}
+	if auxRand == nil {
+		// libsecp256k1 treats missing aux randomness as 32 zero bytes.
+		auxRand = make([]byte, LenAuxRand)
+	}
+
+	var point dcrec.JacobianPoint
+	if !keypair.pk.load(&point) || keypair.sk.IsZero() {
+		return 0, nil, errors.New(ErrorProducingSchnorrSignature)
+	}
+	sec := keypair.sk
+	defer sec.Zero()
+	if evenY(&point) == 1 {
+		sec.Negate()
+	}
+	pkBytes := point.X.Bytes()
+
+	// k = int(hash_nonce((d xor hash_aux(a)) || P || m)) mod n
+	masked := TaggedHash("BIP0340/aux", auxRand)
+	seckey := sec.Bytes()
+	for i := range masked {
+		masked[i] ^= seckey[i]
+	}
+	nonce := TaggedHash("BIP0340/nonce", masked, pkBytes[:], msg)
+	var k dcrec.ModNScalar
+	k.SetByteSlice(nonce)
+	defer k.Zero()
+	if k.IsZero() {
+		return 0, nil, errors.New(ErrorProducingSchnorrSignature)
+	}
+
+	var r dcrec.JacobianPoint
+	dcrec.ScalarBaseMultNonConst(&k, &r)
+	r.ToAffine()
+	if evenY(&r) == 1 {
+		k.Negate()
+	}
+	rBytes := r.X.Bytes()
+
+	var e dcrec.ModNScalar
+	challenge := TaggedHash("BIP0340/challenge", rBytes[:], pkBytes[:], msg)
+	e.SetByteSlice(challenge)
+	e.Mul(&sec).Add(&k)
+
+	sig := make([]byte, LenSchnorrSig)
+	copy(sig[:32], rBytes[:])
+	e.PutBytesUnchecked(sig[32:])
+	return 1, sig, nil
+}
+
+// SchnorrVerify verifies a BIP-340 signature of msg against an x-only
+// public key. The return code is 1 for a correct signature, or 0 if
+// incorrect.
+func SchnorrVerify(ctx *Context, sig64 []byte, msg []byte, pubkey *XOnlyPublicKey) (int, error) {
+	if len(sig64) != LenSchnorrSig {
+		return 0, errors.New(ErrorSchnorrSigSize)
+	}
+
+	var rx dcrec.FieldVal
+	var s dcrec.ModNScalar
+	var pk dcrec.JacobianPoint
+	if rx.SetByteSlice(sig64[:32]) || s.SetByteSlice(sig64[32:]) || !pubkey.pk.load(&pk) {
+		return 0, nil
+	}
+
+	pkBytes := pk.X.Bytes()
+	var e dcrec.ModNScalar
+	challenge := TaggedHash("BIP0340/challenge", sig64[:32], pkBytes[:], msg)
+	e.SetByteSlice(challenge)
+
+	// R = s*G - e*P
+	var r dcrec.JacobianPoint
+	ecmult(&r, &pk, e.Negate(), &s)
+	if isInfinity(&r) {
+		return 0, nil
+	}
+	r.ToAffine()
+	if r.Y.IsOdd() || !r.X.Equals(&rx) {
+		return 0, nil
+	}
+	return 1, nil
+}
@@ -0,0 +1,137 @@
+package secp256k1
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSchnorrSignVerify(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	msg32 := testingRand(32)
+	priv := testingRand(32)
+
+	r, keypair, err := KeypairCreate(ctx, priv)
+	spOK(t, r, err)
+
+	r, xonly, _, err := KeypairXOnlyPub(ctx, keypair)
+	spOK(t, r, err)
+
+	// Both without and with aux randomness
+	for _, aux := range [][]byte{nil, testingRand(32)} {
+		r, sig, err := SchnorrSign(ctx, msg32, keypair, aux)
+		spOK(t, r, err)
+		assert.Len(t, sig, LenSchnorrSig)
+
+		r, err = SchnorrVerify(ctx, sig, msg32, xonly)
+		spOK(t, r, err)
+
+		r, err = SchnorrVerify(ctx, sig, testingRand(32), xonly)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, r)
+	}
+
+	// Keypair exposes the secret and full public key
+	r, sec, err := KeypairSec(ctx, keypair)
+	spOK(t, r, err)
+	assert.Equal(t, priv, sec)
+
+	r, pub, err := KeypairPub(ctx, keypair)
+	spOK(t, r, err)
+
+	r, expectedPub, err := EcPubkeyCreate(ctx, priv)
+	spOK(t, r, err)
+	assert.Equal(t, expectedPub, pub)
+
+	r, fromPub, _, err := XOnlyPubkeyFromPubkey(ctx, pub)
+	spOK(t, r, err)
+	assert.Equal(t, xonly, fromPub)
+}
+
+func TestKeypairXOnlyTweakAdd(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	msg32 := testingRand(32)
+	tweak := testingRand(32)
+
+	r, keypair, err := KeypairCreate(ctx, testingRand(32))
+	spOK(t, r, err)
+
+	r, internal, _, err := KeypairXOnlyPub(ctx, keypair)
+	spOK(t, r, err)
+
+	r, output, err := XOnlyPubkeyTweakAdd(ctx, internal, tweak)
+	spOK(t, r, err)
+
+	r, expected, parity, err := XOnlyPubkeyFromPubkey(ctx, output)
+	spOK(t, r, err)
+
+	r, err = KeypairXOnlyTweakAdd(ctx, keypair, tweak)
+	spOK(t, r, err)
+
+	r, tweaked, tweakedParity, err := KeypairXOnlyPub(ctx, keypair)
+	spOK(t, r, err)
+	assert.Equal(t, expected, tweaked)
+	assert.Equal(t, parity, tweakedParity)
+
+	// The tweaked keypair signs for the output key
+	r, sig, err := SchnorrSign(ctx, msg32, keypair, nil)
+	spOK(t, r, err)
+
+	r, err = SchnorrVerify(ctx, sig, msg32, expected)
+	spOK(t, r, err)
+}
+
+func TestSchnorrSigErrors(t *testing.T) {
+	ctx, err := ContextCreate(ContextSign | ContextVerify)
+	if err != nil {
+		panic(err)
+	}
+
+	badKey, _ := hex.DecodeString("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141")
+
+	r, _, err := KeypairCreate(ctx, []byte(`a`))
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorPrivateKeySize, err.Error())
+
+	r, _, err = KeypairCreate(ctx, badKey)
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorKeypairCreate, err.Error())
+
+	r, _, err = XOnlyPubkeyParse(ctx, []byte(`a`))
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorXOnlyPublicKeySize, err.Error())
+
+	r, keypair, err := KeypairCreate(ctx, testingRand(32))
+	spOK(t, r, err)
+
+	r, xonly, _, err := KeypairXOnlyPub(ctx, keypair)
+	spOK(t, r, err)
+
+	r, _, err = SchnorrSign(ctx, testingRand(32), keypair, []byte(`a`))
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorAuxRandSize, err.Error())
+
+	r, err = SchnorrVerify(ctx, []byte(`a`), testingRand(32), xonly)
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorSchnorrSigSize, err.Error())
+
+	r, _, err = XOnlyPubkeyTweakAdd(ctx, xonly, []byte(`a`))
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorTweakSize, err.Error())
+
+	r, _, err = XOnlyPubkeyTweakAdd(ctx, xonly, badKey)
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorTweakingPublicKey, err.Error())
+
+	r, err = KeypairXOnlyTweakAdd(ctx, keypair, badKey)
+	assert.Equal(t, 0, r)
+	assert.Equal(t, ErrorTweakingKeypair, err.Error())
+}
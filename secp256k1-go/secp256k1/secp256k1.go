@@ -1,3 +1,5 @@
+//go:build cgo && !purego
+
 package secp256k1
 
 // #include <stdlib.h>
@@ -16,9 +18,9 @@ static void setArrayPubkey(secp256k1_pubkey **a, secp256k1_pubkey *pubkey, int n
 static void freePubkeyArray(secp256k1_pubkey **a) {
         free(a);
 }
+// note: -igmp in m1 pro is not working, so use -L/opt/homebrew/opt/gmp/lib
 */
 // #cgo LDFLAGS: ${SRCDIR}/c-secp256k1/.libs/libsecp256k1.a -L/opt/homebrew/opt/gmp/lib
-// note: -igmp in m1 pro is not working, so use -L/opt/homebrew/opt/gmp/lib
 import "C"
 
 import (
@@ -26,56 +28,6 @@ import (
 	"unsafe"
 )
 
-const (
-	/** Flags to pass to secp256k1_context_create. */
-	ContextVerify = uint(C.SECP256K1_CONTEXT_VERIFY)
-	ContextSign   = uint(C.SECP256K1_CONTEXT_SIGN)
-
-	// Flags for EcPubkeySerialize
-	EcCompressed   = uint(C.SECP256K1_EC_COMPRESSED)
-	EcUncompressed = uint(C.SECP256K1_EC_UNCOMPRESSED)
-
-	// Length of elements byte representations
-	LenCompressed   int = 33
-	LenUncompressed int = 65
-	LenMsgHash      int = 32
-	LenPrivateKey   int = 32
-	LenCompactSig   int = 64
-	LenMaxDerSig    int = 72
-
-	// Errors returned by functions
-	ErrorPrivateKeyNull                string = "Private key cannot be null"
-	ErrorPrivateKeyInvalid             string = "Invalid private key"
-	ErrorPublicKeyNull                 string = "Public key cannot be null"
-	ErrorEcdsaSignatureNull            string = "Signature cannot be null"
-	ErrorEcdsaRecoverableSignatureNull string = "Recoverable signature" +
-		" cannot be null"
-	ErrorEcdh             string = "Unable to do ECDH"
-	ErrorPublicKeyCreate  string = "Unable to produce public key"
-	ErrorPublicKeyCombine string = "Unable to combine public keys"
-
-	ErrorTweakSize      string = "Tweak must be exactly 32 bytes"
-	ErrorMsg32Size      string = "Message hash must be exactly 32 bytes"
-	ErrorPrivateKeySize string = "Private key must be exactly 32 bytes"
-	ErrorPublicKeySize  string = "Public key must be 33 or 65 bytes"
-
-	ErrorTweakingPublicKey  string = "Unable to tweak this public key"
-	ErrorTweakingPrivateKey string = "Unable to tweak this private key"
-
-	ErrorProducingSignature            string = "Unable to produce signature"
-	ErrorProducingRecoverableSignature string = "Unable to produce recoverable signature"
-
-	ErrorCompactSigSize  string = "Compact signature must be exactly 64 bytes"
-	ErrorCompactSigParse string = "Unable to parse this compact signature"
-
-	ErrorDerSigParse string = "Unable to parse this DER signature"
-
-	ErrorRecoverableSigParse string = "Unable to parse this recoverable signature"
-	ErrorRecoveryFailed      string = "Failed to recover public key"
-
-	ErrorPublicKeyParse string = "Unable to parse this public key"
-)
-
 // Context wraps a *secp256k1_context, required to use all
 // functions. It can be initialized for signing, verification,
 // or both.
@@ -263,6 +215,16 @@ func EcdsaSignatureSerializeDer(ctx *Context, sig *EcdsaSignature) (int, []byte,
 	return result, goBytes(serializedSig, C.int(outputLen)), nil
 }
 
+// EcdsaSignatureNormalize converts a signature to lower-S form, replacing
+// S with n-S when it is above half the order. The return code is 1 if sig
+// was not normalized, and 0 if it already was. The returned signature is
+// the normalized form in both cases, sig itself is left untouched.
+func EcdsaSignatureNormalize(ctx *Context, sig *EcdsaSignature) (int, *EcdsaSignature, error) {
+	sigout := newEcdsaSignature()
+	result := int(C.secp256k1_ecdsa_signature_normalize(ctx.ctx, sigout.sig, sig.sig))
+	return result, sigout, nil
+}
+
 // Verify an ECDSA signature. Return code is 1 for a correct signature,
 // or 0 if incorrect. To avoid accepting malleable signature, only ECDSA
 // signatures in lower-S form are accepted. If you need to accept ECDSA
@@ -302,6 +264,19 @@ func EcdsaSign(ctx *Context, msg32 []byte, seckey []byte) (int, *EcdsaSignature,
 	return result, signature, nil
 }
 
+// ecdsaSignNdata is EcdsaSign with ndata passed to the RFC6979 nonce
+// function as extra entropy. ndata is nil or 32 bytes.
+func ecdsaSignNdata(ctx *Context, msg32 []byte, seckey []byte, ndata []byte) (int, *EcdsaSignature) {
+	var extra unsafe.Pointer
+	if ndata != nil {
+		extra = unsafe.Pointer(cBuf(ndata))
+	}
+	signature := newEcdsaSignature()
+	result := int(C.secp256k1_ecdsa_sign(ctx.ctx, signature.sig,
+		cBuf(msg32), cBuf(seckey), C.secp256k1_nonce_function_rfc6979, extra))
+	return result, signature
+}
+
 // Verify a secret key. Returns 1 if the secret key is valid, or 0 if an
 // error occured or the key was empty.
 func EcSeckeyVerify(ctx *Context, seckey []byte) (int, error) {
@@ -0,0 +1,815 @@
+//go:build !cgo || purego
+
+package secp256k1
+
+import (
+	"crypto/sha256"
+
+	dcrec "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/pkg/errors"
+)
+
+// This file is the pure-Go backend, used when cgo is unavailable or the
+// purego build tag is set. It exposes the same functions as the cgo
+// bindings in secp256k1.go and follows libsecp256k1's semantics (return
+// codes, nonce generation, lower-S normalization), so both backends produce
+// identical results. Point multiplication is not constant time.
+
+// Context holds the flags the context was created with. The pure-Go
+// backend keeps no precomputed tables in it, so it is cheap to create.
+type Context struct {
+	flags uint
+}
+
+// PublicKey holds a point on the curve. A zero PublicKey, as returned by
+// newPublicKey, is invalid until it is filled in.
+type PublicKey struct {
+	pk *dcrec.PublicKey
+}
+
+// EcdsaSignature holds the R and S values.
+type EcdsaSignature struct {
+	r, s dcrec.ModNScalar
+}
+
+// EcdsaRecoverableSignature holds the signature and the recovery id.
+type EcdsaRecoverableSignature struct {
+	r, s  dcrec.ModNScalar
+	recid int
+}
+
+// Helper methods for this library
+
+func newContext() *Context {
+	return &Context{
+		flags: ContextSign | ContextVerify,
+	}
+}
+
+func newPublicKey() *PublicKey {
+	return &PublicKey{
+		pk: &dcrec.PublicKey{},
+	}
+}
+
+func newEcdsaSignature() *EcdsaSignature {
+	return &EcdsaSignature{}
+}
+
+func newEcdsaRecoverableSignature() *EcdsaRecoverableSignature {
+	return &EcdsaRecoverableSignature{}
+}
+
+// orderAsField is the group order as a field element, used when a
+// recovery id says R.x overflowed the order.
+var orderAsField = func() dcrec.FieldVal {
+	var f dcrec.FieldVal
+	n := dcrec.Params().N.Bytes()
+	f.SetByteSlice(n)
+	return f
+}()
+
+// load reads the point into result, returning false for an uninitialized
+// key, like secp256k1_pubkey_load.
+func (pk *PublicKey) load(result *dcrec.JacobianPoint) bool {
+	pk.pk.AsJacobian(result)
+	return !result.X.IsZero()
+}
+
+// save stores the point, which must not be the point at infinity.
+func (pk *PublicKey) save(point *dcrec.JacobianPoint) {
+	point.ToAffine()
+	pk.pk = dcrec.NewPublicKey(&point.X, &point.Y)
+}
+
+func isInfinity(point *dcrec.JacobianPoint) bool {
+	return (point.X.IsZero() && point.Y.IsZero()) || point.Z.IsZero()
+}
+
+// seckeyLoad parses a secret key, which must be in [1, n-1].
+func seckeyLoad(seckey []byte) (dcrec.ModNScalar, bool) {
+	var sec dcrec.ModNScalar
+	overflow := sec.SetByteSlice(seckey)
+	return sec, !overflow && !sec.IsZero()
+}
+
+// Begin secp256k1.h
+
+// ContextCreate produces a new *Context, initialized with a bitmask
+// of flags depending on it's intended usage. The supported flags
+// are currently ContextSign and ContextVerify. Although expressed
+// in the return type signature, the function does not currently
+// return an error.
+func ContextCreate(flags uint) (*Context, error) {
+	context := newContext()
+	context.flags = flags
+	return context, nil
+}
+
+// ContextClone makes a copy of the provided *Context. The provided
+// context must not be NULL.
+func ContextClone(ctx *Context) (*Context, error) {
+	other := newContext()
+	other.flags = ctx.flags
+	return other, nil
+}
+
+// ContextDestroy destroys the context. The provided context must not
+// be NULL.
+func ContextDestroy(ctx *Context) {
+}
+
+// ContextRandomize accepts a [32]byte seed in order to update the context
+// randomization. The pure-Go backend does not blind its multiplications,
+// so this has no effect and always returns 1.
+func ContextRandomize(ctx *Context, seed32 [32]byte) int {
+	return 1
+}
+
+// EcPubkeyParse deserializes a variable-length public key into a *Pubkey
+// object. The function will reject any input of zero bytes in length.
+// This function supports parsing compressed (33 bytes, header byte 0x02 or
+// 0x03), uncompressed (65 bytes, header byte 0x04), or hybrid (65 bytes,
+// header byte 0x06 or 0x07) format public keys. The return code is 1 if
+// the public key was fully valid, or 0 if the public key was invalid or
+// could not be parsed.
+func EcPubkeyParse(ctx *Context, publicKey []byte) (int, *PublicKey, error) {
+	l := len(publicKey)
+	if l < 1 {
+		return 0, nil, errors.New(ErrorPublicKeySize)
+	}
+
+	var x, y dcrec.FieldVal
+	valid := false
+	switch {
+	case l == LenCompressed && (publicKey[0] == 0x02 || publicKey[0] == 0x03):
+		valid = !x.SetByteSlice(publicKey[1:33]) &&
+			dcrec.DecompressY(&x, publicKey[0] == 0x03, &y)
+	case l == LenUncompressed && (publicKey[0] == 0x04 || publicKey[0] == 0x06 || publicKey[0] == 0x07):
+		valid = !x.SetByteSlice(publicKey[1:33]) && !y.SetByteSlice(publicKey[33:65])
+		if valid && publicKey[0] != 0x04 && y.IsOdd() != (publicKey[0] == 0x07) {
+			valid = false
+		}
+		valid = valid && dcrec.NewPublicKey(&x, &y).IsOnCurve()
+	}
+	if !valid {
+		return 0, nil, errors.New(ErrorPublicKeyParse)
+	}
+	return 1, &PublicKey{pk: dcrec.NewPublicKey(&x, &y)}, nil
+}
+
+// EcPubkeySerialize serializes a pubkey object into a []byte. The output
+// is an array of 65-bytes (if compressed==0), or 33-bytes (if compressed==1).
+// Use EcCompressed or EcUncompressed to request a certain format. The
+// function will always return 1, because the only
+// public key objects are valid ones.
+func EcPubkeySerialize(ctx *Context, publicKey *PublicKey, flags uint) (int, []byte, error) {
+	var point dcrec.JacobianPoint
+	if !publicKey.load(&point) {
+		return 0, []byte{}, nil
+	}
+	if flags == EcCompressed {
+		return 1, publicKey.pk.SerializeCompressed(), nil
+	}
+	return 1, publicKey.pk.SerializeUncompressed(), nil
+}
+
+// EcdsaSignatureParseCompact parses an ECDSA signature in compact (64
+// bytes) format. The return code is 1 when the signature could be
+// parsed, 0 otherwise. The signature must consist of a 32-byte big
+// endian R value, followed by a 32-byte big endian S value. If R or S fall
+// outside of [0..order-1], the encoding is invalid. R and S with value 0
+// are allowed in the encoding.
+func EcdsaSignatureParseCompact(ctx *Context, signature []byte) (int, *EcdsaSignature, error) {
+	if len(signature) != LenCompactSig {
+		return 0, nil, errors.New(ErrorCompactSigSize)
+	}
+
+	sig := newEcdsaSignature()
+	overflowR := sig.r.SetByteSlice(signature[:32])
+	overflowS := sig.s.SetByteSlice(signature[32:])
+	if overflowR || overflowS {
+		return 0, nil, errors.New(ErrorCompactSigParse)
+	}
+	return 1, sig, nil
+}
+
+// Serialize an ECDSA signature in compact (64 byte) format. Return code is
+// always 1. See EcdsaSignatureParseCompact for details about the encoding.
+func EcdsaSignatureSerializeCompact(ctx *Context, sig *EcdsaSignature) (int, []byte, error) {
+	return 1, serializeCompact(&sig.r, &sig.s), nil
+}
+
+// Parse a DER ECDSA signature. Returns 1 when the signature
+// could be parsed, 0 otherwise. This function will accept any
+// valid DER encoded signature, even if the encoded numbers are
+// out of range. If the encoded numbers are out of range, signature
+// validation with it is guaranteed to fail for every message and
+// public key.
+func EcdsaSignatureParseDer(ctx *Context, signature []byte) (int, *EcdsaSignature, error) {
+	sig := newEcdsaSignature()
+	if !parseDer(&sig.r, &sig.s, signature) {
+		return 0, nil, errors.New(ErrorDerSigParse)
+	}
+	return 1, sig, nil
+}
+
+// Serialize an ECDSA signature in DER format. The return code is always 1.
+func EcdsaSignatureSerializeDer(ctx *Context, sig *EcdsaSignature) (int, []byte, error) {
+	return 1, serializeDer(&sig.r, &sig.s), nil
+}
+
+// EcdsaSignatureNormalize converts a signature to lower-S form, replacing
+// S with n-S when it is above half the order. The return code is 1 if sig
+// was not normalized, and 0 if it already was. The returned signature is
+// the normalized form in both cases, sig itself is left untouched.
+func EcdsaSignatureNormalize(ctx *Context, sig *EcdsaSignature) (int, *EcdsaSignature, error) {
+	sigout := newEcdsaSignature()
+	*sigout = *sig
+	if !sigout.s.IsOverHalfOrder() {
+		return 0, sigout, nil
+	}
+	sigout.s.Negate()
+	return 1, sigout, nil
+}
+
+// Verify an ECDSA signature. Return code is 1 for a correct signature,
+// or 0 if incorrect. To avoid accepting malleable signature, only ECDSA
+// signatures in lower-S form are accepted.
+func EcdsaVerify(ctx *Context, sig *EcdsaSignature, msg32 []byte,
+	pubkey *PublicKey) (int, error) {
+	if len(msg32) != LenMsgHash {
+		return 0, errors.New(ErrorMsg32Size)
+	}
+
+	var q dcrec.JacobianPoint
+	if sig.s.IsOverHalfOrder() || !pubkey.load(&q) {
+		return 0, nil
+	}
+	var msg dcrec.ModNScalar
+	msg.SetByteSlice(msg32)
+	if !ecdsaVerify(&sig.r, &sig.s, &q, &msg) {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// Create an ECDSA signature. Return code is 1 if the signature was
+// created, or is zero and the error is set if the nonce generation
+// function failed, or the private key was invalid. The created
+// signature is always in lower-S form.
+func EcdsaSign(ctx *Context, msg32 []byte, seckey []byte) (int, *EcdsaSignature, error) {
+	if len(msg32) != LenMsgHash {
+		return 0, nil, errors.New(ErrorMsg32Size)
+	}
+	if len(seckey) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorPrivateKeySize)
+	}
+
+	signature := newEcdsaSignature()
+	if _, ok := ecdsaSign(&signature.r, &signature.s, msg32, seckey, nil); !ok {
+		return 0, nil, errors.New(ErrorProducingSignature)
+	}
+	return 1, signature, nil
+}
+
+// ecdsaSignNdata is EcdsaSign with ndata passed to the RFC6979 nonce
+// function as extra entropy. ndata is nil or 32 bytes.
+func ecdsaSignNdata(ctx *Context, msg32 []byte, seckey []byte, ndata []byte) (int, *EcdsaSignature) {
+	signature := newEcdsaSignature()
+	if _, ok := ecdsaSign(&signature.r, &signature.s, msg32, seckey, ndata); !ok {
+		return 0, signature
+	}
+	return 1, signature
+}
+
+// Verify a secret key. Returns 1 if the secret key is valid, or 0 if an
+// error occured or the key was empty.
+func EcSeckeyVerify(ctx *Context, seckey []byte) (int, error) {
+	if len(seckey) < 1 {
+		return 0, errors.New(ErrorPrivateKeyNull)
+	}
+	if _, ok := seckeyLoad(seckey); len(seckey) != LenPrivateKey || !ok {
+		return 0, errors.New(ErrorPrivateKeyInvalid)
+	}
+	return 1, nil
+}
+
+// EcPubkeyCreate will compute the public key for a secret key. The
+// return code is 1 and the key returned if the secret was valid.
+// Otherwise, the return code is 0, and an error is returned. The key
+// length must be 32-bytes.
+func EcPubkeyCreate(ctx *Context, seckey []byte) (int, *PublicKey, error) {
+	if len(seckey) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorPrivateKeySize)
+	}
+
+	sec, ok := seckeyLoad(seckey)
+	if !ok {
+		return 0, nil, errors.New(ErrorPublicKeyCreate)
+	}
+	var point dcrec.JacobianPoint
+	dcrec.ScalarBaseMultNonConst(&sec, &point)
+	sec.Zero()
+
+	pk := newPublicKey()
+	pk.save(&point)
+	return 1, pk, nil
+}
+
+// EcPrivkeyNegate will negate a private key in place. The return code is
+// 1 if the operation was successful, or 0 if the length was invalid or
+// the key was invalid, in which case it is zeroed.
+func EcPrivkeyNegate(ctx *Context, seckey []byte) (int, error) {
+	if len(seckey) != LenPrivateKey {
+		return 0, errors.New(ErrorPrivateKeySize)
+	}
+
+	sec, ok := seckeyLoad(seckey)
+	if !ok {
+		sec.Zero()
+	}
+	sec.Negate()
+	sec.PutBytesUnchecked(seckey)
+	return boolToInt(ok), nil
+}
+
+// EcPubkeyNegate will negate a public key object in place. The return code
+// is always 1.
+func EcPubkeyNegate(ctx *Context, pubkey *PublicKey) (int, error) {
+	var point dcrec.JacobianPoint
+	if !pubkey.load(&point) {
+		return 0, nil
+	}
+	point.Y.Negate(1).Normalize()
+	pubkey.save(&point)
+	return 1, nil
+}
+
+// EcPrivkeyTweakAdd modifies the provided `seckey` by adding tweak to
+// it. The return code is 0 if `tweak` was out of range (chance of
+// around 1 in 2^128 for uniformly random 32-byte arrays), or if the
+// resulting private key would be invalid (only when the tweak is the
+// complement of the private key). The return code is 1 otherwise.
+func EcPrivkeyTweakAdd(ctx *Context, seckey []byte, tweak []byte) (int, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+	if len(seckey) != LenPrivateKey {
+		return 0, errors.New(ErrorPrivateKeySize)
+	}
+
+	sec, ok := seckeyLoad(seckey)
+	var term dcrec.ModNScalar
+	ok = !term.SetByteSlice(tweak) && ok
+	sec.Add(&term)
+	ok = ok && !sec.IsZero()
+	if !ok {
+		sec.Zero()
+	}
+	sec.PutBytesUnchecked(seckey)
+	if !ok {
+		return 0, errors.New(ErrorTweakingPrivateKey)
+	}
+	return 1, nil
+}
+
+// Tweak a private key by multiplying it by a tweak. The return code is 0
+// if the tweak was out of range (chance of around 1 in 2^128 for uniformly
+// random 32-byte arrays) or zero. The code is 1 otherwise.
+func EcPrivkeyTweakMul(ctx *Context, seckey []byte, tweak []byte) (int, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+	if len(seckey) != LenPrivateKey {
+		return 0, errors.New(ErrorPrivateKeySize)
+	}
+
+	sec, ok := seckeyLoad(seckey)
+	var factor dcrec.ModNScalar
+	ok = !factor.SetByteSlice(tweak) && !factor.IsZero() && ok
+	sec.Mul(&factor)
+	if !ok {
+		sec.Zero()
+	}
+	sec.PutBytesUnchecked(seckey)
+	if !ok {
+		return 0, errors.New(ErrorTweakingPrivateKey)
+	}
+	return 1, nil
+}
+
+// Tweak a public key by adding tweak times the generator to it. The
+// return code is 0 if the tweak was out of range (chance of around 1 in
+// 2^128 for uniformly random 32-byte arrays) or if the resulting public
+// key would be invalid. The return code is 1 otherwise.
+func EcPubkeyTweakAdd(ctx *Context, pk *PublicKey, tweak []byte) (int, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+
+	var point dcrec.JacobianPoint
+	ok := pk.load(&point) && pubkeyTweakAdd(&point, tweak)
+	if !ok {
+		pk.pk = &dcrec.PublicKey{}
+		return 0, errors.New(ErrorTweakingPublicKey)
+	}
+	pk.save(&point)
+	return 1, nil
+}
+
+// Tweak a public key by multiplying it by a tweak. The return code is 0
+// if the tweak was out of range (chance of around 1 in 2^128 for uniformly
+// random 32-byte arrays) or zero. The code is 1 otherwise.
+func EcPubkeyTweakMul(ctx *Context, pk *PublicKey, tweak []byte) (int, error) {
+	if len(tweak) != LenPrivateKey {
+		return 0, errors.New(ErrorTweakSize)
+	}
+
+	var point dcrec.JacobianPoint
+	var factor dcrec.ModNScalar
+	ok := !factor.SetByteSlice(tweak) && !factor.IsZero() && pk.load(&point)
+	if !ok {
+		pk.pk = &dcrec.PublicKey{}
+		return 0, errors.New(ErrorTweakingPublicKey)
+	}
+	var product dcrec.JacobianPoint
+	dcrec.ScalarMultNonConst(&factor, &point, &product)
+	pk.save(&product)
+	return 1, nil
+}
+
+// EcPubkeyCombine will compute sum of all the provided public keys,
+// returning a new point. The error code is 1 if the sum is valid, 0
+// otherwise. There must be at least one public key.
+func EcPubkeyCombine(ctx *Context, vPk []*PublicKey) (int, *PublicKey, error) {
+	l := len(vPk)
+	if l < 1 {
+		return 0, nil, errors.New("Must provide at least one public key")
+	}
+
+	var sum, point dcrec.JacobianPoint
+	for i := 0; i < l; i++ {
+		if !vPk[i].load(&point) {
+			return 0, nil, errors.New(ErrorPublicKeyCombine)
+		}
+		dcrec.AddNonConst(&sum, &point, &sum)
+	}
+	if isInfinity(&sum) {
+		return 0, nil, errors.New(ErrorPublicKeyCombine)
+	}
+
+	pkOut := newPublicKey()
+	pkOut.save(&sum)
+	return 1, pkOut, nil
+}
+
+// Begin secp256k1_ecdh.h
+
+// Compute an EC Diffie-Hellman secret. Return code is
+// 1 if exponentiation was successful, or 0 if the scalar was invalid.
+// The secret is the SHA256 of the compressed shared point, as with
+// libsecp256k1's default hash function.
+func Ecdh(ctx *Context, pubKey *PublicKey, privKey []byte) (int, []byte, error) {
+	if len(privKey) != LenPrivateKey {
+		return 0, []byte{}, errors.New(ErrorPrivateKeySize)
+	}
+
+	var point dcrec.JacobianPoint
+	sec, ok := seckeyLoad(privKey)
+	if !ok || !pubKey.load(&point) {
+		return 0, []byte{}, errors.New(ErrorEcdh)
+	}
+	var product dcrec.JacobianPoint
+	dcrec.ScalarMultNonConst(&sec, &point, &product)
+	sec.Zero()
+
+	product.ToAffine()
+	shared := dcrec.NewPublicKey(&product.X, &product.Y).SerializeCompressed()
+	secret := sha256.Sum256(shared)
+	return 1, secret[:], nil
+}
+
+// Begin secp256k1_recovery.h
+
+// Parse a compact ECDSA signature from the 64-byte signature and recovery
+// id (0, 1, 2, or 3). The return code is 1 if successful, 0 otherwise.
+func EcdsaRecoverableSignatureParseCompact(ctx *Context, signature []byte, recid int) (int, *EcdsaRecoverableSignature, error) {
+	if len(signature) != LenCompactSig {
+		return 0, nil, errors.New(ErrorCompactSigSize)
+	}
+
+	sig := newEcdsaRecoverableSignature()
+	overflowR := sig.r.SetByteSlice(signature[:32])
+	overflowS := sig.s.SetByteSlice(signature[32:])
+	if overflowR || overflowS || recid < 0 || recid > 3 {
+		return 0, nil, errors.New(ErrorRecoverableSigParse)
+	}
+	sig.recid = recid
+	return 1, sig, nil
+}
+
+// Serialize an ECDSA signature in compact format, returning the []byte
+// and the recovery id. Return code is always 1.
+func EcdsaRecoverableSignatureSerializeCompact(ctx *Context, sig *EcdsaRecoverableSignature) (int, []byte, int, error) {
+	return 1, serializeCompact(&sig.r, &sig.s), sig.recid, nil
+}
+
+// Convert a recoverable signature into a normal signature. The return code
+// is always 1.
+func EcdsaRecoverableSignatureConvert(ctx *Context, sig *EcdsaRecoverableSignature) (int, *EcdsaSignature, error) {
+	sigOut := newEcdsaSignature()
+	sigOut.r.Set(&sig.r)
+	sigOut.s.Set(&sig.s)
+	return 1, sigOut, nil
+}
+
+// Create a recoverable ECDSA signature. The return code is 1 when the sig
+// was created, or 0 if nonce generation failed or the private key was
+// invalid.
+func EcdsaSignRecoverable(ctx *Context, msg32 []byte, seckey []byte) (int, *EcdsaRecoverableSignature, error) {
+	if len(msg32) != LenMsgHash {
+		return 0, nil, errors.New(ErrorMsg32Size)
+	}
+	if len(seckey) != LenPrivateKey {
+		return 0, nil, errors.New(ErrorPrivateKeySize)
+	}
+
+	recoverable := newEcdsaRecoverableSignature()
+	recid, ok := ecdsaSign(&recoverable.r, &recoverable.s, msg32, seckey, nil)
+	if !ok {
+		return 0, nil, errors.New(ErrorProducingRecoverableSignature)
+	}
+	recoverable.recid = recid
+	return 1, recoverable, nil
+}
+
+// Recover an ECDSA public key from a signature. The return code is 1 if
+// the key was successfully recovered (which guarantees a correct
+// signature), and is 0 otherwise.
+func EcdsaRecover(ctx *Context, sig *EcdsaRecoverableSignature, msg32 []byte) (int, *PublicKey, error) {
+	if len(msg32) != LenMsgHash {
+		return 0, nil, errors.New(ErrorMsg32Size)
+	}
+	if sig.r.IsZero() || sig.s.IsZero() {
+		return 0, nil, errors.New(ErrorRecoveryFailed)
+	}
+
+	// R.x is r, or r+n when the recovery id says it overflowed the order.
+	var x, y dcrec.FieldVal
+	rBytes := sig.r.Bytes()
+	x.SetBytes(&rBytes)
+	if sig.recid&2 != 0 {
+		if x.IsGtOrEqPrimeMinusOrder() {
+			return 0, nil, errors.New(ErrorRecoveryFailed)
+		}
+		x.Add(&orderAsField).Normalize()
+	}
+	if !dcrec.DecompressY(&x, sig.recid&1 != 0, &y) {
+		return 0, nil, errors.New(ErrorRecoveryFailed)
+	}
+
+	// Q = r^-1 (sR - eG)
+	var msg, rn, u1, u2 dcrec.ModNScalar
+	msg.SetByteSlice(msg32)
+	rn.InverseValNonConst(&sig.r)
+	u1.Mul2(&rn, &msg).Negate()
+	u2.Mul2(&rn, &sig.s)
+
+	var one dcrec.FieldVal
+	one.SetInt(1)
+	R := dcrec.MakeJacobianPoint(&x, &y, &one)
+	var q dcrec.JacobianPoint
+	ecmult(&q, &R, &u2, &u1)
+	if isInfinity(&q) {
+		return 0, nil, errors.New(ErrorRecoveryFailed)
+	}
+
+	recovered := newPublicKey()
+	recovered.save(&q)
+	return 1, recovered, nil
+}
+
+// Internal arithmetic shared with schnorrsig_purego.go
+
+// ecmult computes na*a + ng*G.
+func ecmult(result, a *dcrec.JacobianPoint, na, ng *dcrec.ModNScalar) {
+	var p1, p2 dcrec.JacobianPoint
+	dcrec.ScalarMultNonConst(na, a, &p1)
+	dcrec.ScalarBaseMultNonConst(ng, &p2)
+	dcrec.AddNonConst(&p1, &p2, result)
+}
+
+// pubkeyTweakAdd computes point + tweak*G, failing if the tweak overflows
+// or the result is the point at infinity.
+func pubkeyTweakAdd(point *dcrec.JacobianPoint, tweak []byte) bool {
+	var term, one dcrec.ModNScalar
+	if term.SetByteSlice(tweak) {
+		return false
+	}
+	one.SetInt(1)
+	ecmult(point, point, &one, &term)
+	return !isInfinity(point)
+}
+
+// ecdsaSign signs msg32 with the RFC6979 nonce used by libsecp256k1's
+// default nonce function, returning the recovery id. ndata is nil or 32
+// bytes of extra entropy mixed into the nonce.
+func ecdsaSign(r, s *dcrec.ModNScalar, msg32, seckey, ndata []byte) (int, bool) {
+	sec, ok := seckeyLoad(seckey)
+	if !ok {
+		return 0, false
+	}
+	defer sec.Zero()
+
+	var msg dcrec.ModNScalar
+	msg.SetByteSlice(msg32)
+	for count := uint32(0); ; count++ {
+		nonce := dcrec.NonceRFC6979(seckey, msg32, ndata, nil, count)
+		recid, ok := ecdsaSigSign(r, s, &sec, &msg, nonce)
+		nonce.Zero()
+		if ok {
+			return recid, true
+		}
+	}
+}
+
+// ecdsaSigSign computes the signature for a given nonce, mirroring
+// secp256k1_ecdsa_sig_sign.
+func ecdsaSigSign(sigr, sigs, sec, msg, nonce *dcrec.ModNScalar) (int, bool) {
+	var rp dcrec.JacobianPoint
+	dcrec.ScalarBaseMultNonConst(nonce, &rp)
+	rp.ToAffine()
+
+	rx := rp.X.Bytes()
+	overflow := sigr.SetBytes(rx) != 0
+	recid := 0
+	if overflow {
+		recid |= 2
+	}
+	if rp.Y.IsOdd() {
+		recid |= 1
+	}
+
+	var n, k dcrec.ModNScalar
+	n.Mul2(sigr, sec).Add(msg)
+	k.InverseValNonConst(nonce)
+	sigs.Mul2(&k, &n)
+	n.Zero()
+	k.Zero()
+	if sigs.IsZero() || sigr.IsZero() {
+		return 0, false
+	}
+	if sigs.IsOverHalfOrder() {
+		sigs.Negate()
+		recid ^= 1
+	}
+	return recid, true
+}
+
+// ecdsaVerify mirrors secp256k1_ecdsa_sig_verify.
+func ecdsaVerify(sigr, sigs *dcrec.ModNScalar, q *dcrec.JacobianPoint, msg *dcrec.ModNScalar) bool {
+	if sigr.IsZero() || sigs.IsZero() {
+		return false
+	}
+
+	var sn, u1, u2 dcrec.ModNScalar
+	sn.InverseValNonConst(sigs)
+	u1.Mul2(&sn, msg)
+	u2.Mul2(&sn, sigr)
+
+	var pr dcrec.JacobianPoint
+	ecmult(&pr, q, &u2, &u1)
+	if isInfinity(&pr) {
+		return false
+	}
+	pr.ToAffine()
+
+	var x dcrec.ModNScalar
+	x.SetBytes(pr.X.Bytes())
+	return x.Equals(sigr)
+}
+
+func serializeCompact(r, s *dcrec.ModNScalar) []byte {
+	output := make([]byte, LenCompactSig)
+	r.PutBytesUnchecked(output[:32])
+	s.PutBytesUnchecked(output[32:])
+	return output
+}
+
+// serializeDer mirrors secp256k1_ecdsa_sig_serialize.
+func serializeDer(r, s *dcrec.ModNScalar) []byte {
+	var rb, sb [33]byte
+	r.PutBytesUnchecked(rb[1:])
+	s.PutBytesUnchecked(sb[1:])
+	rp, sp := rb[:], sb[:]
+	for len(rp) > 1 && rp[0] == 0 && rp[1] < 0x80 {
+		rp = rp[1:]
+	}
+	for len(sp) > 1 && sp[0] == 0 && sp[1] < 0x80 {
+		sp = sp[1:]
+	}
+
+	sig := make([]byte, 0, 6+len(rp)+len(sp))
+	sig = append(sig, 0x30, byte(4+len(rp)+len(sp)))
+	sig = append(sig, 0x02, byte(len(rp)))
+	sig = append(sig, rp...)
+	sig = append(sig, 0x02, byte(len(sp)))
+	sig = append(sig, sp...)
+	return sig
+}
+
+// parseDer mirrors secp256k1_ecdsa_sig_parse: the encoding must be strict
+// DER, but integers that are negative or out of range parse as zero.
+func parseDer(r, s *dcrec.ModNScalar, sig []byte) bool {
+	if len(sig) == 0 || sig[0] != 0x30 {
+		return false
+	}
+	sig = sig[1:]
+	rlen, sig, ok := derReadLen(sig)
+	if !ok || rlen != len(sig) {
+		return false
+	}
+	if sig, ok = derParseInteger(r, sig); !ok {
+		return false
+	}
+	if sig, ok = derParseInteger(s, sig); !ok {
+		return false
+	}
+	return len(sig) == 0
+}
+
+func derReadLen(sig []byte) (int, []byte, bool) {
+	if len(sig) == 0 {
+		return 0, nil, false
+	}
+	b1 := sig[0]
+	sig = sig[1:]
+	if b1 == 0xff {
+		return 0, nil, false
+	}
+	if b1&0x80 == 0 {
+		return int(b1), sig, true
+	}
+	if b1 == 0x80 {
+		// Indefinite length is not allowed in DER.
+		return 0, nil, false
+	}
+	lenLeft := int(b1 & 0x7f)
+	if lenLeft > len(sig) || sig[0] == 0 || lenLeft > 8 {
+		return 0, nil, false
+	}
+	ret := 0
+	for ; lenLeft > 0; lenLeft-- {
+		ret = ret<<8 | int(sig[0])
+		if ret+lenLeft > len(sig) {
+			return 0, nil, false
+		}
+		sig = sig[1:]
+	}
+	if ret < 128 {
+		// Not the shortest possible length encoding.
+		return 0, nil, false
+	}
+	return ret, sig, true
+}
+
+func derParseInteger(r *dcrec.ModNScalar, sig []byte) ([]byte, bool) {
+	if len(sig) == 0 || sig[0] != 0x02 {
+		return nil, false
+	}
+	rlen, sig, ok := derReadLen(sig[1:])
+	if !ok || rlen <= 0 || rlen > len(sig) {
+		return nil, false
+	}
+	if sig[0] == 0x00 && rlen > 1 && sig[1]&0x80 == 0x00 {
+		// Excessive 0x00 padding.
+		return nil, false
+	}
+	if sig[0] == 0xff && rlen > 1 && sig[1]&0x80 == 0x80 {
+		// Excessive 0xFF padding.
+		return nil, false
+	}
+	num := sig[:rlen]
+	overflow := num[0]&0x80 == 0x80
+	for len(num) > 0 && num[0] == 0 {
+		num = num[1:]
+	}
+	if len(num) > 32 {
+		overflow = true
+	}
+	if !overflow {
+		overflow = r.SetByteSlice(num)
+	}
+	if overflow {
+		r.Zero()
+	}
+	return sig[rlen:], true
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
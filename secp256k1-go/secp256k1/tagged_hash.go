@@ -0,0 +1,15 @@
+package secp256k1
+
+import "crypto/sha256"
+
+// TaggedHash is the BIP-340 tagged hash SHA256(SHA256(tag) || SHA256(tag) || msg...).
+func TaggedHash(tag string, msgs ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, msg := range msgs {
+		h.Write(msg)
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// batchChunkSize is how many entries a worker verifies per call into the
+// secp256k1 package, which costs a single cgo crossing per call.
+const batchChunkSize = 64
+
+// EcdsaBatchEntry is an ECDSA signature of a 32 bytes digest to verify.
+type EcdsaBatchEntry struct {
+	PubKey *PublicKey
+	Digest []byte
+	Sig    *Signature
+}
+
+// SchnorrBatchEntry is a BIP-340 signature of a message to verify.
+type SchnorrBatchEntry struct {
+	PubKey *XOnlyPublicKey
+	Msg    []byte
+	Sig    []byte
+}
+
+// BatchError lists the entries of a batch that failed verification.
+type BatchError struct {
+	// Failed holds the indexes of the failed entries, in increasing order.
+	Failed []int
+	// Total is the number of entries in the batch.
+	Total int
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d signatures failed verification", len(e.Failed), e.Total)
+}
+
+// VerifyEcdsaBatch verifies every entry, spreading the work over at most
+// workers goroutines (GOMAXPROCS when workers <= 0). It returns nil if all
+// signatures are valid, and a *BatchError otherwise. Entries with missing
+// fields or a digest that is not 32 bytes count as failed.
+func VerifyEcdsaBatch(entries []EcdsaBatchEntry, workers int) error {
+	return verifyBatch(len(entries), workers, func(start, end int) []int {
+		var failed, idx []int
+		var sigs []*secp256k1.EcdsaSignature
+		var digests [][]byte
+		var pubkeys []*secp256k1.PublicKey
+		for i := start; i < end; i++ {
+			e := entries[i]
+			if e.PubKey == nil || e.Sig == nil || len(e.Digest) != secp256k1.LenMsgHash {
+				failed = append(failed, i)
+				continue
+			}
+			idx = append(idx, i)
+			sigs = append(sigs, e.Sig.sig)
+			digests = append(digests, e.Digest)
+			pubkeys = append(pubkeys, e.PubKey.pk)
+		}
+		results, err := secp256k1.EcdsaVerifyBatch(Context(), sigs, digests, pubkeys)
+		return append(failed, batchFailures(idx, results, err)...)
+	})
+}
+
+// VerifySchnorrBatch verifies every entry, spreading the work over at most
+// workers goroutines (GOMAXPROCS when workers <= 0). It returns nil if all
+// signatures are valid, and a *BatchError otherwise. Entries with a missing
+// public key or a signature that is not 64 bytes count as failed.
+func VerifySchnorrBatch(entries []SchnorrBatchEntry, workers int) error {
+	return verifyBatch(len(entries), workers, func(start, end int) []int {
+		var failed, idx []int
+		var sigs, msgs [][]byte
+		var pubkeys []*secp256k1.XOnlyPublicKey
+		for i := start; i < end; i++ {
+			e := entries[i]
+			if e.PubKey == nil || len(e.Sig) != SchnorrSignatureLength {
+				failed = append(failed, i)
+				continue
+			}
+			idx = append(idx, i)
+			sigs = append(sigs, e.Sig)
+			msgs = append(msgs, e.Msg)
+			pubkeys = append(pubkeys, e.PubKey.pk)
+		}
+		results, err := secp256k1.SchnorrVerifyBatch(Context(), sigs, msgs, pubkeys)
+		return append(failed, batchFailures(idx, results, err)...)
+	})
+}
+
+// batchFailures maps the failed results of a chunk back to entry indexes.
+// An error fails the whole chunk, although the entries are checked first.
+func batchFailures(idx []int, results []int, err error) []int {
+	if err != nil {
+		return idx
+	}
+	var failed []int
+	for i, r := range results {
+		if r != 1 {
+			failed = append(failed, idx[i])
+		}
+	}
+	return failed
+}
+
+// verifyBatch splits n entries into chunks verified by a bounded pool of
+// workers, each chunk returning the indexes that failed.
+func verifyBatch(n int, workers int, verify func(start, end int) []int) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunks := (n + batchChunkSize - 1) / batchChunkSize
+	if workers > chunks {
+		workers = chunks
+	}
+
+	starts := make(chan int)
+	var mu sync.Mutex
+	var failed []int
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + batchChunkSize
+				if end > n {
+					end = n
+				}
+				if f := verify(start, end); len(f) > 0 {
+					mu.Lock()
+					failed = append(failed, f...)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for start := 0; start < n; start += batchChunkSize {
+		starts <- start
+	}
+	close(starts)
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	sort.Ints(failed)
+	return &BatchError{Failed: failed, Total: n}
+}
@@ -0,0 +1,149 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testEcdsaBatch(tb testing.TB, n int) []EcdsaBatchEntry {
+	tb.Helper()
+	entries := make([]EcdsaBatchEntry, n)
+	for i := range entries {
+		key, err := GeneratePrivateKey()
+		if err != nil {
+			tb.Fatal(err)
+		}
+		digest := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+		sig, err := Sign(key, digest[:])
+		if err != nil {
+			tb.Fatal(err)
+		}
+		entries[i] = EcdsaBatchEntry{PubKey: key.PubKey(), Digest: digest[:], Sig: sig}
+	}
+	return entries
+}
+
+func testSchnorrBatch(tb testing.TB, n int) []SchnorrBatchEntry {
+	tb.Helper()
+	entries := make([]SchnorrBatchEntry, n)
+	for i := range entries {
+		key, err := GeneratePrivateKey()
+		if err != nil {
+			tb.Fatal(err)
+		}
+		msg := sha256.Sum256([]byte{byte(i), byte(i >> 8)})
+		sig, err := SignSchnorr(key, msg[:], nil)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		entries[i] = SchnorrBatchEntry{PubKey: key.PubKey().XOnly(), Msg: msg[:], Sig: sig}
+	}
+	return entries
+}
+
+func TestVerifyEcdsaBatch(t *testing.T) {
+	entries := testEcdsaBatch(t, 300)
+	for _, workers := range []int{0, 1, 3, 16} {
+		if err := VerifyEcdsaBatch(entries, workers); err != nil {
+			t.Errorf("VerifyEcdsaBatch(workers = %d) error = %v", workers, err)
+		}
+	}
+
+	entries[0].Digest = entries[1].Digest
+	entries[64].PubKey = entries[65].PubKey
+	entries[150].Sig = nil
+	entries[299].Digest = entries[299].Digest[:31]
+	want := []int{0, 64, 150, 299}
+	for _, workers := range []int{0, 1, 3, 16} {
+		err := VerifyEcdsaBatch(entries, workers)
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("VerifyEcdsaBatch(workers = %d) error = %v, want *BatchError", workers, err)
+		}
+		if !reflect.DeepEqual(batchErr.Failed, want) || batchErr.Total != len(entries) {
+			t.Errorf("VerifyEcdsaBatch(workers = %d) failed = %v of %d, want %v of %d",
+				workers, batchErr.Failed, batchErr.Total, want, len(entries))
+		}
+	}
+
+	if err := VerifyEcdsaBatch(nil, 0); err != nil {
+		t.Errorf("VerifyEcdsaBatch(nil) error = %v", err)
+	}
+}
+
+func TestVerifySchnorrBatch(t *testing.T) {
+	entries := testSchnorrBatch(t, 200)
+	if err := VerifySchnorrBatch(entries, 0); err != nil {
+		t.Errorf("VerifySchnorrBatch() error = %v", err)
+	}
+
+	entries[5].Msg = nil
+	entries[63].Sig = entries[63].Sig[:63]
+	entries[128].PubKey = nil
+	entries[199].PubKey = entries[198].PubKey
+	want := []int{5, 63, 128, 199}
+	err := VerifySchnorrBatch(entries, 2)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("VerifySchnorrBatch() error = %v, want *BatchError", err)
+	}
+	if !reflect.DeepEqual(batchErr.Failed, want) {
+		t.Errorf("VerifySchnorrBatch() failed = %v, want %v", batchErr.Failed, want)
+	}
+}
+
+func BenchmarkVerifyEcdsaEach(b *testing.B) {
+	entries := testEcdsaBatch(b, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			if !e.Sig.Verify(e.Digest, e.PubKey) {
+				b.Fatal("signature does not verify")
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyEcdsaBatchSingleWorker(b *testing.B) {
+	entries := testEcdsaBatch(b, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyEcdsaBatch(entries, 1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyEcdsaBatch(b *testing.B) {
+	entries := testEcdsaBatch(b, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifyEcdsaBatch(entries, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifySchnorrEach(b *testing.B) {
+	entries := testSchnorrBatch(b, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range entries {
+			if !e.PubKey.VerifySchnorr(e.Msg, e.Sig) {
+				b.Fatal("signature does not verify")
+			}
+		}
+	}
+}
+
+func BenchmarkVerifySchnorrBatch(b *testing.B) {
+	entries := testSchnorrBatch(b, 1024)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := VerifySchnorrBatch(entries, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
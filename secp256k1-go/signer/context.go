@@ -0,0 +1,39 @@
+// Package signer is an idiomatic layer over the secp256k1 package: keys and
+// signatures are Go types, errors replace the libsecp256k1 return codes, and
+// a single shared context is managed for the caller.
+package signer
+
+import (
+	"crypto/rand"
+	"sync"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var (
+	sharedCtx  *secp256k1.Context
+	sharedOnce sync.Once
+)
+
+// Context returns the context shared by the package, created for signing
+// and verification and randomized once against side-channel attacks. After
+// that libsecp256k1 only reads from it, so it is safe for concurrent use.
+// Callers may pass it to the secp256k1 functions but must not randomize or
+// destroy it.
+func Context() *secp256k1.Context {
+	sharedOnce.Do(func() {
+		ctx, err := secp256k1.ContextCreate(secp256k1.ContextSign | secp256k1.ContextVerify)
+		if err != nil {
+			panic(err)
+		}
+		var seed [32]byte
+		if _, err := rand.Read(seed[:]); err != nil {
+			panic(err)
+		}
+		if secp256k1.ContextRandomize(ctx, seed) != 1 {
+			panic("signer: unable to randomize context")
+		}
+		sharedCtx = ctx
+	})
+	return sharedCtx
+}
@@ -0,0 +1,90 @@
+package signer
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"io"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// PrivateKeyLength is the length of a serialized private key.
+const PrivateKeyLength = 32
+
+var (
+	ErrInvalidPrivateKey = errors.New("private key must be 32 bytes in [1, n-1]")
+	ErrInvalidDigest     = errors.New("digest must be 32 bytes")
+)
+
+// PrivateKey is a secp256k1 private key. It implements crypto.Signer.
+type PrivateKey struct {
+	seckey []byte
+	pub    *PublicKey
+}
+
+// GeneratePrivateKey returns a new private key read from crypto/rand.
+func GeneratePrivateKey() (*PrivateKey, error) {
+	return NewPrivateKey(rand.Reader)
+}
+
+// NewPrivateKey returns a new private key read from r, retrying the
+// negligible fraction of values that are out of range.
+func NewPrivateKey(r io.Reader) (*PrivateKey, error) {
+	seckey := make([]byte, PrivateKeyLength)
+	for {
+		if _, err := io.ReadFull(r, seckey); err != nil {
+			return nil, err
+		}
+		key, err := PrivKeyFromBytes(seckey)
+		if err == nil {
+			return key, nil
+		}
+	}
+}
+
+// PrivKeyFromBytes returns the private key for a 32 bytes big endian secret.
+func PrivKeyFromBytes(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeyLength {
+		return nil, ErrInvalidPrivateKey
+	}
+	seckey := append([]byte(nil), b...)
+	_, pk, err := secp256k1.EcPubkeyCreate(Context(), seckey)
+	if err != nil {
+		return nil, ErrInvalidPrivateKey
+	}
+	return &PrivateKey{seckey: seckey, pub: &PublicKey{pk: pk}}, nil
+}
+
+// Serialize returns a copy of the 32 bytes secret.
+func (k *PrivateKey) Serialize() []byte {
+	return append([]byte(nil), k.seckey...)
+}
+
+// PubKey returns the public key of k.
+func (k *PrivateKey) PubKey() *PublicKey {
+	return k.pub
+}
+
+// Public implements crypto.Signer, the result is a *PublicKey.
+func (k *PrivateKey) Public() crypto.PublicKey {
+	return k.pub
+}
+
+// Sign implements crypto.Signer. It signs a 32 bytes digest and returns the
+// DER signature. The nonce is derived with RFC 6979, so rand is ignored, and
+// so is opts since the digest is signed as is.
+func (k *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	sig, err := Sign(k, digest)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Serialize(), nil
+}
+
+// Zero overwrites the secret, the key must not be used afterwards.
+func (k *PrivateKey) Zero() {
+	for i := range k.seckey {
+		k.seckey[i] = 0
+	}
+}
@@ -0,0 +1,49 @@
+package signer
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
+// PublicKey is a secp256k1 public key. It implements crypto.PublicKey.
+type PublicKey struct {
+	pk *secp256k1.PublicKey
+}
+
+// ParsePubKey parses a 33 bytes compressed or 65 bytes uncompressed public key.
+func ParsePubKey(b []byte) (*PublicKey, error) {
+	_, pk, err := secp256k1.EcPubkeyParse(Context(), b)
+	if err != nil {
+		return nil, ErrInvalidPublicKey
+	}
+	return &PublicKey{pk: pk}, nil
+}
+
+// SerializeCompressed returns the 33 bytes compressed encoding.
+func (p *PublicKey) SerializeCompressed() []byte {
+	_, b, _ := secp256k1.EcPubkeySerialize(Context(), p.pk, secp256k1.EcCompressed)
+	return b
+}
+
+// SerializeUncompressed returns the 65 bytes uncompressed encoding.
+func (p *PublicKey) SerializeUncompressed() []byte {
+	_, b, _ := secp256k1.EcPubkeySerialize(Context(), p.pk, secp256k1.EcUncompressed)
+	return b
+}
+
+// Equal reports whether x is a *PublicKey for the same point.
+func (p *PublicKey) Equal(x crypto.PublicKey) bool {
+	other, ok := x.(*PublicKey)
+	return ok && bytes.Equal(p.SerializeCompressed(), other.SerializeCompressed())
+}
+
+// Verify reports whether der is a valid DER signature of digest by p.
+func (p *PublicKey) Verify(digest []byte, der []byte) bool {
+	sig, err := ParseDERSignature(der)
+	return err == nil && sig.Verify(digest, p)
+}
@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+// SchnorrSignatureLength is the length of a BIP-340 signature.
+const SchnorrSignatureLength = 64
+
+var ErrInvalidXOnlyPublicKey = errors.New("invalid x-only public key")
+
+// XOnlyPublicKey is a BIP-340 public key, of which only X is serialized.
+type XOnlyPublicKey struct {
+	pk *secp256k1.XOnlyPublicKey
+}
+
+// ParseXOnlyPubKey parses a 32 bytes x-only public key.
+func ParseXOnlyPubKey(b []byte) (*XOnlyPublicKey, error) {
+	_, pk, err := secp256k1.XOnlyPubkeyParse(Context(), b)
+	if err != nil {
+		return nil, ErrInvalidXOnlyPublicKey
+	}
+	return &XOnlyPublicKey{pk: pk}, nil
+}
+
+// XOnly returns the x-only public key for p, dropping the parity of Y.
+func (p *PublicKey) XOnly() *XOnlyPublicKey {
+	_, pk, _, _ := secp256k1.XOnlyPubkeyFromPubkey(Context(), p.pk)
+	return &XOnlyPublicKey{pk: pk}
+}
+
+// Serialize returns the 32 bytes X coordinate.
+func (p *XOnlyPublicKey) Serialize() []byte {
+	_, b, _ := secp256k1.XOnlyPubkeySerialize(Context(), p.pk)
+	return b
+}
+
+// SignSchnorr creates a BIP-340 signature of msg. auxRand is nil or 32 fresh
+// random bytes.
+func SignSchnorr(key *PrivateKey, msg []byte, auxRand []byte) ([]byte, error) {
+	_, keypair, err := secp256k1.KeypairCreate(Context(), key.seckey)
+	if err != nil {
+		return nil, err
+	}
+	_, sig, err := secp256k1.SchnorrSign(Context(), msg, keypair, auxRand)
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// VerifySchnorr reports whether sig is a valid BIP-340 signature of msg by p.
+func (p *XOnlyPublicKey) VerifySchnorr(msg []byte, sig []byte) bool {
+	ok, err := secp256k1.SchnorrVerify(Context(), sig, msg, p.pk)
+	return err == nil && ok == 1
+}
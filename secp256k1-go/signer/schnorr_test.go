@@ -0,0 +1,50 @@
+package signer
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSignSchnorr(t *testing.T) {
+	// BIP-340 test vector 1
+	key, err := PrivKeyFromBytes(mustHex(t, "b7e151628aed2a6abf7158809cf4f3c762e7160f38b4da56a784d9045190cfef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := mustHex(t, "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89")
+	aux := mustHex(t, "0000000000000000000000000000000000000000000000000000000000000001")
+	wantPub := mustHex(t, "dff1d77f2a671c5f36183726db2341be58feae1da2deced843240f7b502ba659")
+	wantSig := mustHex(t, "6896bd60eeae296db48a229ff71dfe071bde413e6d43f917dc8dcf8c78de3341"+
+		"8906d11ac976abccb20b091292bff4ea897efcb639ea871cfa95f6de339e4b0a")
+
+	xonly := key.PubKey().XOnly()
+	if !bytes.Equal(xonly.Serialize(), wantPub) {
+		t.Errorf("XOnly() = %x, want %x", xonly.Serialize(), wantPub)
+	}
+	sig, err := SignSchnorr(key, msg, aux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(sig, wantSig) {
+		t.Errorf("SignSchnorr() = %x, want %x", sig, wantSig)
+	}
+
+	pub, err := ParseXOnlyPubKey(wantPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.VerifySchnorr(msg, sig) {
+		t.Error("VerifySchnorr() = false, want true")
+	}
+	if pub.VerifySchnorr(msg[1:], sig) {
+		t.Error("VerifySchnorr() of another message = true, want false")
+	}
+	if pub.VerifySchnorr(msg, sig[1:]) {
+		t.Error("VerifySchnorr() of a short signature = true, want false")
+	}
+
+	if _, err := ParseXOnlyPubKey(wantPub[1:]); !errors.Is(err, ErrInvalidXOnlyPublicKey) {
+		t.Errorf("ParseXOnlyPubKey() error = %v, want %v", err, ErrInvalidXOnlyPublicKey)
+	}
+}
@@ -0,0 +1,102 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature encoding")
+
+// Signature is an ECDSA signature over secp256k1.
+type Signature struct {
+	sig *secp256k1.EcdsaSignature
+}
+
+// Sign signs a 32 bytes digest with key, using an RFC 6979 nonce. The
+// signature is always low-S.
+func Sign(key *PrivateKey, digest []byte) (*Signature, error) {
+	if len(digest) != secp256k1.LenMsgHash {
+		return nil, ErrInvalidDigest
+	}
+	_, sig, err := secp256k1.EcdsaSign(Context(), digest, key.seckey)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{sig: sig}, nil
+}
+
+// SignWithOptions is Sign with extra nonce entropy or low-R grinding, see
+// secp256k1.EcdsaSignOptions.
+func SignWithOptions(key *PrivateKey, digest []byte, opts *secp256k1.EcdsaSignOptions) (*Signature, error) {
+	if len(digest) != secp256k1.LenMsgHash {
+		return nil, ErrInvalidDigest
+	}
+	_, sig, err := secp256k1.EcdsaSignWithOptions(Context(), digest, key.seckey, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Signature{sig: sig}, nil
+}
+
+// ParseDERSignature parses a strict DER signature.
+func ParseDERSignature(der []byte) (*Signature, error) {
+	_, sig, err := secp256k1.EcdsaSignatureParseDer(Context(), der)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{sig: sig}, nil
+}
+
+// ParseDERSignatureLax parses a signature that is only loosely DER, as found
+// in Bitcoin transactions from before BIP-66. Out of range values parse as
+// a signature that never verifies. The result may be high-S, see Normalize.
+func ParseDERSignatureLax(der []byte) (*Signature, error) {
+	_, sig, err := secp256k1.EcdsaSignatureParseDerLax(Context(), der)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{sig: sig}, nil
+}
+
+// ParseCompactSignature parses a 64 bytes r || s signature.
+func ParseCompactSignature(b []byte) (*Signature, error) {
+	_, sig, err := secp256k1.EcdsaSignatureParseCompact(Context(), b)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return &Signature{sig: sig}, nil
+}
+
+// Serialize returns the DER encoding of the signature.
+func (s *Signature) Serialize() []byte {
+	_, der, _ := secp256k1.EcdsaSignatureSerializeDer(Context(), s.sig)
+	return der
+}
+
+// SerializeCompact returns the 64 bytes r || s encoding of the signature.
+func (s *Signature) SerializeCompact() []byte {
+	_, b, _ := secp256k1.EcdsaSignatureSerializeCompact(Context(), s.sig)
+	return b
+}
+
+// IsLowS reports whether S is at most half the curve order.
+func (s *Signature) IsLowS() bool {
+	return secp256k1.EcdsaSignatureIsLowS(Context(), s.sig)
+}
+
+// Normalize returns the lower-S form of s, which Verify accepts.
+func (s *Signature) Normalize() *Signature {
+	_, sig, _ := secp256k1.EcdsaSignatureNormalize(Context(), s.sig)
+	return &Signature{sig: sig}
+}
+
+// Verify reports whether s is a valid signature of the 32 bytes digest by
+// pub. As in libsecp256k1, high-S signatures are rejected.
+func (s *Signature) Verify(digest []byte, pub *PublicKey) bool {
+	if len(digest) != secp256k1.LenMsgHash {
+		return false
+	}
+	ok, err := secp256k1.EcdsaVerify(Context(), s.sig, digest, pub.pk)
+	return err == nil && ok == 1
+}
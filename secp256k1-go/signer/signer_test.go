@@ -0,0 +1,271 @@
+package signer
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/secp256k1-go/secp256k1"
+)
+
+var _ crypto.Signer = (*PrivateKey)(nil)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestPrivKeyFromBytes(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		wantPub string
+		wantErr error
+	}{
+		{
+			name:    "one",
+			key:     "0000000000000000000000000000000000000000000000000000000000000001",
+			wantPub: "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			name:    "n-1",
+			key:     "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			wantPub: "0379be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			name:    "zero",
+			key:     "0000000000000000000000000000000000000000000000000000000000000000",
+			wantErr: ErrInvalidPrivateKey,
+		},
+		{
+			name:    "order",
+			key:     "fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+			wantErr: ErrInvalidPrivateKey,
+		},
+		{
+			name:    "short",
+			key:     "01",
+			wantErr: ErrInvalidPrivateKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := PrivKeyFromBytes(mustHex(t, tt.key))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PrivKeyFromBytes() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := hex.EncodeToString(key.PubKey().SerializeCompressed()); got != tt.wantPub {
+				t.Errorf("PubKey() = %v, want %v", got, tt.wantPub)
+			}
+			if got := hex.EncodeToString(key.Serialize()); got != tt.key {
+				t.Errorf("Serialize() = %v, want %v", got, tt.key)
+			}
+		})
+	}
+}
+
+func TestSignRFC6979(t *testing.T) {
+	key, err := PrivKeyFromBytes(mustHex(t, "0000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("Satoshi Nakamoto"))
+
+	sig, err := Sign(key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8" +
+		"2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	if got := hex.EncodeToString(sig.SerializeCompact()); got != want {
+		t.Errorf("SerializeCompact() = %v, want %v", got, want)
+	}
+	if !sig.Verify(digest[:], key.PubKey()) {
+		t.Error("Verify() = false, want true")
+	}
+}
+
+func TestSignWithOptions(t *testing.T) {
+	key, err := PrivKeyFromBytes(mustHex(t, "0000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("low r 1"))
+
+	sig, err := SignWithOptions(key, digest[:], &secp256k1.EcdsaSignOptions{LowR: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "30440220610f828a26ebb13f709961f11fcc3fd2d449fb203165e52b16bd88ae34c74b14" +
+		"02207d9d7076f8a662ee8107a041b51969b28f129d670b7c9e4d7e349c0c1ae737bf"
+	if got := hex.EncodeToString(sig.Serialize()); got != want {
+		t.Errorf("Serialize() = %v, want %v", got, want)
+	}
+	if !sig.Verify(digest[:], key.PubKey()) {
+		t.Error("Verify() = false, want true")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("hello"))
+
+	der, err := key.Sign(nil, digest[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, ok := key.Public().(*PublicKey)
+	if !ok {
+		t.Fatalf("Public() = %T, want *PublicKey", key.Public())
+	}
+	if !pub.Verify(digest[:], der) {
+		t.Error("Verify() = false, want true")
+	}
+	if other.PubKey().Verify(digest[:], der) {
+		t.Error("Verify() with another key = true, want false")
+	}
+	wrong := sha256.Sum256([]byte("world"))
+	if pub.Verify(wrong[:], der) {
+		t.Error("Verify() of another digest = true, want false")
+	}
+
+	sig, err := ParseDERSignature(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compact, err := ParseCompactSignature(sig.SerializeCompact())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(compact.Serialize(), der) {
+		t.Errorf("Serialize() = %x, want %x", compact.Serialize(), der)
+	}
+
+	if _, err := key.Sign(nil, digest[:31], crypto.SHA256); !errors.Is(err, ErrInvalidDigest) {
+		t.Errorf("Sign() error = %v, want %v", err, ErrInvalidDigest)
+	}
+	if _, err := ParseDERSignature([]byte{0x30}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseDERSignature() error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := ParseCompactSignature(der); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("ParseCompactSignature() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestParsePubKey(t *testing.T) {
+	key, err := GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range [][]byte{key.PubKey().SerializeCompressed(), key.PubKey().SerializeUncompressed()} {
+		pub, err := ParsePubKey(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pub.Equal(key.PubKey()) {
+			t.Errorf("ParsePubKey(%x) is not equal to the original key", b)
+		}
+	}
+
+	invalid := mustHex(t, "020000000000000000000000000000000000000000000000000000000000000005")
+	if _, err := ParsePubKey(invalid); !errors.Is(err, ErrInvalidPublicKey) {
+		t.Errorf("ParsePubKey() error = %v, want %v", err, ErrInvalidPublicKey)
+	}
+	if key.PubKey().Equal(crypto.PublicKey(nil)) {
+		t.Error("Equal(nil) = true, want false")
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key, err := GeneratePrivateKey()
+			if err != nil {
+				errs <- err
+				return
+			}
+			for j := 0; j < 20; j++ {
+				digest := sha256.Sum256([]byte{byte(i), byte(j)})
+				sig, err := Sign(key, digest[:])
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !sig.Verify(digest[:], key.PubKey()) {
+					errs <- errors.New("signature does not verify")
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestSignatureNormalize(t *testing.T) {
+	key, err := PrivKeyFromBytes(mustHex(t, "0000000000000000000000000000000000000000000000000000000000000001"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte("Satoshi Nakamoto"))
+
+	// The high-S twin of the RFC 6979 signature, with negative looking R
+	// and S as pre BIP-66 encoders used to produce them.
+	lax := mustHex(t, "30440220934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8"+
+		"0220dbbd3162d46e9f9bef7feb87c16dc13b4f6568a87f4e83f728e2443ba586675c")
+	strict, err := ParseDERSignature(lax)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strict.Normalize().Verify(digest[:], key.PubKey()) {
+		t.Error("Verify() of strictly parsed negative values = true, want false")
+	}
+	sig, err := ParseDERSignatureLax(lax)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.IsLowS() {
+		t.Error("IsLowS() = true, want false")
+	}
+	if sig.Verify(digest[:], key.PubKey()) {
+		t.Error("Verify() of a high-S signature = true, want false")
+	}
+
+	normalized := sig.Normalize()
+	if !normalized.IsLowS() {
+		t.Error("IsLowS() after Normalize() = false, want true")
+	}
+	if !normalized.Verify(digest[:], key.PubKey()) {
+		t.Error("Verify() after Normalize() = false, want true")
+	}
+	want := "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8" +
+		"2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5"
+	if got := hex.EncodeToString(normalized.SerializeCompact()); got != want {
+		t.Errorf("SerializeCompact() = %v, want %v", got, want)
+	}
+}
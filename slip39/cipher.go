@@ -0,0 +1,62 @@
+package slip39
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// baseIterationCount is the PBKDF2 iteration count of all rounds
+	// together at iteration exponent 0.
+	baseIterationCount = 10000
+	roundCount         = 4
+)
+
+// roundFunction is the round function of the Feistel network, keyed by the
+// passphrase and salted with the identifier and the right half.
+func roundFunction(i int, passphrase []byte, e int, salt, r []byte) []byte {
+	password := append([]byte{byte(i)}, passphrase...)
+	s := append(append([]byte(nil), salt...), r...)
+	return pbkdf2.Key(password, s, (baseIterationCount<<e)/roundCount, len(r), sha256.New)
+}
+
+// cipherSalt is empty for extendable backups, so that shares of a new
+// identifier still decrypt to the same master secret.
+func cipherSalt(identifier int, extendable bool) []byte {
+	if extendable {
+		return nil
+	}
+	return append([]byte("shamir"), byte(identifier>>8), byte(identifier))
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// encrypt encrypts the master secret with the passphrase in a four round
+// Feistel network.
+func encrypt(masterSecret, passphrase []byte, e, identifier int, extendable bool) []byte {
+	half := len(masterSecret) / 2
+	l, r := masterSecret[:half], masterSecret[half:]
+	salt := cipherSalt(identifier, extendable)
+	for i := 0; i < roundCount; i++ {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, e, salt, r))
+	}
+	return append(append([]byte(nil), r...), l...)
+}
+
+// decrypt runs the rounds of encrypt in reverse.
+func decrypt(encryptedSecret, passphrase []byte, e, identifier int, extendable bool) []byte {
+	half := len(encryptedSecret) / 2
+	l, r := encryptedSecret[:half], encryptedSecret[half:]
+	salt := cipherSalt(identifier, extendable)
+	for i := roundCount - 1; i >= 0; i-- {
+		l, r = r, xorBytes(l, roundFunction(i, passphrase, e, salt, r))
+	}
+	return append(append([]byte(nil), r...), l...)
+}
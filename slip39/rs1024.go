@@ -0,0 +1,56 @@
+package slip39
+
+// rs1024Generator is the generator of the Reed-Solomon code over GF(1024)
+// that protects every share with three checksum words.
+var rs1024Generator = [10]uint32{
+	0xE0E040, 0x1C1C080, 0x3838100, 0x7070200, 0xE0E0009,
+	0x1C0C2412, 0x38086C24, 0x3090FC48, 0x21B1F890, 0x3F3F120,
+}
+
+// customizationString keeps shares of the extendable and of the original
+// format from validating as each other.
+func customizationString(extendable bool) []byte {
+	if extendable {
+		return []byte("shamir_extendable")
+	}
+	return []byte("shamir")
+}
+
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xFFFFF)<<10 ^ uint32(v)
+		for i, gen := range rs1024Generator {
+			if (b>>i)&1 == 1 {
+				chk ^= gen
+			}
+		}
+	}
+	return chk
+}
+
+func withCustomization(data []int, extendable bool) []int {
+	cs := customizationString(extendable)
+	values := make([]int, 0, len(cs)+len(data))
+	for _, c := range cs {
+		values = append(values, int(c))
+	}
+	return append(values, data...)
+}
+
+// rs1024CreateChecksum returns the checksum words of data.
+func rs1024CreateChecksum(data []int, extendable bool) []int {
+	values := append(withCustomization(data, extendable), make([]int, checksumLengthWords)...)
+	polymod := rs1024Polymod(values) ^ 1
+	checksum := make([]int, checksumLengthWords)
+	for i := range checksum {
+		checksum[i] = int(polymod>>(radixBits*(checksumLengthWords-1-i))) & (1<<radixBits - 1)
+	}
+	return checksum
+}
+
+// rs1024VerifyChecksum reports whether data ends with valid checksum words.
+func rs1024VerifyChecksum(data []int, extendable bool) bool {
+	return rs1024Polymod(withCustomization(data, extendable)) == 1
+}
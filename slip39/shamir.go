@@ -0,0 +1,159 @@
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+const (
+	// digestIndex and secretIndex are the x coordinates of the digest
+	// share and of the shared secret, which are never handed out.
+	digestIndex = 254
+	secretIndex = 255
+
+	digestLengthBytes = 4
+)
+
+// expTable and logTable are the powers of 3 and their logarithms in
+// GF(256) with the Rijndael polynomial x^8 + x^4 + x^3 + x + 1.
+var expTable, logTable = func() ([255]byte, [256]byte) {
+	var exp [255]byte
+	var log [256]byte
+	poly := 1
+	for i := range exp {
+		exp[i] = byte(poly)
+		log[poly] = byte(i)
+		// 乘以 3，即 x + 1
+		poly = poly<<1 ^ poly
+		if poly&0x100 != 0 {
+			poly ^= 0x11B
+		}
+	}
+	return exp, log
+}()
+
+type rawShare struct {
+	x     int
+	value []byte
+}
+
+// interpolate evaluates at x the polynomial of least degree through
+// shares, byte by byte, with Lagrange interpolation.
+func interpolate(shares []rawShare, x int) ([]byte, error) {
+	seen := make(map[int]bool, len(shares))
+	for _, share := range shares {
+		if seen[share.x] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateShare, share.x)
+		}
+		seen[share.x] = true
+		if len(share.value) != len(shares[0].value) {
+			return nil, ErrMismatchedShares
+		}
+	}
+	for _, share := range shares {
+		if share.x == x {
+			return append([]byte(nil), share.value...), nil
+		}
+	}
+
+	// log(prod(x - x_j)), where subtraction is XOR
+	logProd := 0
+	for _, share := range shares {
+		logProd += int(logTable[share.x^x])
+	}
+	result := make([]byte, len(shares[0].value))
+	for _, share := range shares {
+		// log of the Lagrange basis polynomial of share at x
+		logBasis := logProd - int(logTable[share.x^x])
+		for _, other := range shares {
+			if other.x != share.x {
+				logBasis -= int(logTable[share.x^other.x])
+			}
+		}
+		logBasis = (logBasis%255 + 255) % 255
+		for i, v := range share.value {
+			if v != 0 {
+				result[i] ^= expTable[(int(logTable[v])+logBasis)%255]
+			}
+		}
+	}
+	return result, nil
+}
+
+// createDigest binds the shared secret to the random part of the digest
+// share, so that recovering from wrong shares is detected.
+func createDigest(randomData, sharedSecret []byte) []byte {
+	mac := hmac.New(sha256.New, randomData)
+	mac.Write(sharedSecret)
+	return mac.Sum(nil)[:digestLengthBytes]
+}
+
+// splitSecret splits sharedSecret into shareCount shares, any threshold of
+// which recover it.
+func splitSecret(threshold, shareCount int, sharedSecret []byte, random io.Reader) ([]rawShare, error) {
+	if threshold < 1 || threshold > shareCount {
+		return nil, fmt.Errorf("%w: %d of %d", ErrInvalidThreshold, threshold, shareCount)
+	}
+	if shareCount > maxShareCount {
+		return nil, fmt.Errorf("%w: %d shares, at most %d", ErrInvalidThreshold, shareCount, maxShareCount)
+	}
+
+	shares := make([]rawShare, 0, shareCount)
+	if threshold == 1 {
+		for i := 0; i < shareCount; i++ {
+			shares = append(shares, rawShare{i, append([]byte(nil), sharedSecret...)})
+		}
+		return shares, nil
+	}
+
+	// threshold-2 random shares, the digest share and the secret fix the
+	// polynomial, the remaining shares are evaluated from it
+	randomShareCount := threshold - 2
+	for i := 0; i < randomShareCount; i++ {
+		value := make([]byte, len(sharedSecret))
+		if _, err := io.ReadFull(random, value); err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{i, value})
+	}
+	randomPart := make([]byte, len(sharedSecret)-digestLengthBytes)
+	if _, err := io.ReadFull(random, randomPart); err != nil {
+		return nil, err
+	}
+	digest := createDigest(randomPart, sharedSecret)
+	baseShares := append(shares[:randomShareCount:randomShareCount],
+		rawShare{digestIndex, append(digest, randomPart...)},
+		rawShare{secretIndex, sharedSecret},
+	)
+	for i := randomShareCount; i < shareCount; i++ {
+		value, err := interpolate(baseShares, i)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, rawShare{i, value})
+	}
+	return shares, nil
+}
+
+// recoverSecret recovers the secret from threshold shares and checks it
+// against the digest.
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	if threshold == 1 {
+		return shares[0].value, nil
+	}
+	sharedSecret, err := interpolate(shares, secretIndex)
+	if err != nil {
+		return nil, err
+	}
+	digestShare, err := interpolate(shares, digestIndex)
+	if err != nil {
+		return nil, err
+	}
+	digest, randomPart := digestShare[:digestLengthBytes], digestShare[digestLengthBytes:]
+	if !hmac.Equal(digest, createDigest(randomPart, sharedSecret)) {
+		return nil, ErrInvalidDigest
+	}
+	return sharedSecret, nil
+}
@@ -0,0 +1,134 @@
+package slip39
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	radixBits                = 10
+	idLengthBits             = 15
+	extendableFlagLengthBits = 1
+	iterationExpLengthBits   = 4
+	idExpLengthWords         = 2
+	checksumLengthWords      = 3
+	// metadataLengthWords is the identifier and iteration exponent, the
+	// group and member parameters and the checksum.
+	metadataLengthWords = idExpLengthWords + 2 + checksumLengthWords
+	minStrengthBits     = 128
+	// minMnemonicLengthWords is the length of a share of a 128 bit secret.
+	minMnemonicLengthWords = metadataLengthWords + (minStrengthBits+radixBits-1)/radixBits
+	maxShareCount          = 16
+)
+
+// Share is a single SLIP-39 mnemonic: one member share of one group.
+type Share struct {
+	// Identifier is the random 15 bit identifier common to every share
+	// of a master secret.
+	Identifier int
+	// Extendable shares use an empty salt in the cipher, so that more
+	// groups may later be split off the same encrypted secret.
+	Extendable bool
+	// IterationExponent sets the PBKDF2 iteration count to 10000 << e.
+	IterationExponent int
+	GroupIndex        int
+	GroupThreshold    int
+	GroupCount        int
+	MemberIndex       int
+	MemberThreshold   int
+	// Value is the share of the encrypted master secret.
+	Value []byte
+}
+
+// Mnemonic returns the words of the share.
+func (s *Share) Mnemonic() string {
+	idExpExt := s.Identifier<<(extendableFlagLengthBits+iterationExpLengthBits) | s.IterationExponent
+	if s.Extendable {
+		idExpExt |= 1 << iterationExpLengthBits
+	}
+	params := s.GroupIndex<<16 | (s.GroupThreshold-1)<<12 | (s.GroupCount-1)<<8 | s.MemberIndex<<4 | (s.MemberThreshold - 1)
+
+	data := intToIndices(big.NewInt(int64(idExpExt)), idExpLengthWords)
+	data = append(data, intToIndices(big.NewInt(int64(params)), 2)...)
+	valueWordCount := (len(s.Value)*8 + radixBits - 1) / radixBits
+	data = append(data, intToIndices(new(big.Int).SetBytes(s.Value), valueWordCount)...)
+	data = append(data, rs1024CreateChecksum(data, s.Extendable)...)
+
+	words := make([]string, len(data))
+	for i, index := range data {
+		words[i] = wordList[index]
+	}
+	return strings.Join(words, " ")
+}
+
+// DecodeMnemonic parses a share and verifies its checksum and padding.
+func DecodeMnemonic(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	data := make([]int, len(words))
+	for i, word := range words {
+		index, ok := wordIndexes[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q at position %d", ErrUnknownWord, word, i)
+		}
+		data[i] = index
+	}
+	if len(data) < minMnemonicLengthWords {
+		return nil, fmt.Errorf("%w: got %d words, want at least %d", ErrInvalidMnemonicLength, len(data), minMnemonicLengthWords)
+	}
+	// The value is padded with at most 8 leading zero bits to a
+	// multiple of 10 bits, and holds an even number of bytes
+	paddingLen := radixBits * (len(data) - metadataLengthWords) % 16
+	if paddingLen > 8 {
+		return nil, fmt.Errorf("%w: %d words", ErrInvalidMnemonicLength, len(data))
+	}
+
+	idExpExt := indicesToInt(data[:idExpLengthWords]).Int64()
+	s := &Share{
+		Identifier:        int(idExpExt >> (extendableFlagLengthBits + iterationExpLengthBits)),
+		Extendable:        (idExpExt>>iterationExpLengthBits)&1 == 1,
+		IterationExponent: int(idExpExt & (1<<iterationExpLengthBits - 1)),
+	}
+	if !rs1024VerifyChecksum(data, s.Extendable) {
+		return nil, ErrInvalidChecksum
+	}
+
+	params := indicesToInt(data[idExpLengthWords : idExpLengthWords+2]).Int64()
+	s.GroupIndex = int(params >> 16 & 0xF)
+	s.GroupThreshold = int(params>>12&0xF) + 1
+	s.GroupCount = int(params>>8&0xF) + 1
+	s.MemberIndex = int(params >> 4 & 0xF)
+	s.MemberThreshold = int(params&0xF) + 1
+	if s.GroupThreshold > s.GroupCount {
+		return nil, fmt.Errorf("%w: group threshold %d exceeds the group count %d", ErrInvalidThreshold, s.GroupThreshold, s.GroupCount)
+	}
+
+	valueData := data[idExpLengthWords+2 : len(data)-checksumLengthWords]
+	value := indicesToInt(valueData)
+	valueByteCount := (radixBits*len(valueData) - paddingLen) / 8
+	if value.BitLen() > valueByteCount*8 {
+		return nil, ErrInvalidPadding
+	}
+	s.Value = value.FillBytes(make([]byte, valueByteCount))
+	return s, nil
+}
+
+func indicesToInt(indices []int) *big.Int {
+	n := new(big.Int)
+	for _, index := range indices {
+		n.Lsh(n, radixBits).Or(n, big.NewInt(int64(index)))
+	}
+	return n
+}
+
+// intToIndices splits n into count 10 bit words, most significant first.
+func intToIndices(n *big.Int, count int) []int {
+	indices := make([]int, count)
+	mask := big.NewInt(1<<radixBits - 1)
+	v := new(big.Int).Set(n)
+	for i := count - 1; i >= 0; i-- {
+		indices[i] = int(new(big.Int).And(v, mask).Int64())
+		v.Rsh(v, radixBits)
+	}
+	return indices
+}
@@ -0,0 +1,208 @@
+// Package slip39 implements SLIP-39, Shamir's secret-sharing for mnemonic
+// codes: a master secret is encrypted with a passphrase and split into
+// groups of member shares, each written down as a mnemonic.
+// 协议文档：https://github.com/satoshilabs/slips/blob/master/slip-0039.md
+package slip39
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	ErrUnknownWord              = errors.New("unknown word")
+	ErrInvalidMnemonicLength    = errors.New("invalid mnemonic length")
+	ErrInvalidChecksum          = errors.New("invalid mnemonic checksum")
+	ErrInvalidPadding           = errors.New("invalid mnemonic padding")
+	ErrInvalidThreshold         = errors.New("invalid threshold")
+	ErrInvalidSecretLength      = errors.New("master secret must be an even number of bytes, at least 16")
+	ErrInvalidPassphrase        = errors.New("passphrase must be printable ASCII")
+	ErrInvalidIterationExponent = errors.New("iteration exponent must be in [0, 15]")
+	ErrMismatchedShares         = errors.New("shares do not belong to the same secret")
+	ErrDuplicateShare           = errors.New("share indices must be unique")
+	ErrInsufficientShares       = errors.New("insufficient number of shares")
+	ErrTooManyShares            = errors.New("too many shares")
+	ErrInvalidDigest            = errors.New("invalid digest of the shared secret")
+)
+
+// Group is the member threshold and member count of a group.
+type Group struct {
+	MemberThreshold int
+	MemberCount     int
+}
+
+// Options tunes GenerateMnemonics. The zero value is valid.
+type Options struct {
+	// IterationExponent raises the PBKDF2 iteration count of the
+	// passphrase cipher to 10000 << IterationExponent.
+	IterationExponent int
+	// Extendable shares let more groups be split off later with the same
+	// passphrase and master secret.
+	Extendable bool
+	// Rand is the source of the identifier and of the random shares,
+	// crypto/rand if nil.
+	Rand io.Reader
+}
+
+func validatePassphrase(passphrase string) error {
+	for i := 0; i < len(passphrase); i++ {
+		if passphrase[i] < 32 || passphrase[i] > 126 {
+			return ErrInvalidPassphrase
+		}
+	}
+	return nil
+}
+
+// GenerateMnemonics encrypts masterSecret with passphrase and splits it into
+// groups, any groupThreshold of which recover it. It returns the mnemonics
+// of every member of every group, in the order of groups.
+func GenerateMnemonics(groupThreshold int, groups []Group, masterSecret []byte, passphrase string, opts *Options) ([][]string, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	random := opts.Rand
+	if random == nil {
+		random = rand.Reader
+	}
+	if len(masterSecret)*8 < minStrengthBits || len(masterSecret)%2 != 0 {
+		return nil, fmt.Errorf("%w: got %d bytes", ErrInvalidSecretLength, len(masterSecret))
+	}
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if opts.IterationExponent < 0 || opts.IterationExponent >= 1<<iterationExpLengthBits {
+		return nil, ErrInvalidIterationExponent
+	}
+	if groupThreshold < 1 || groupThreshold > len(groups) {
+		return nil, fmt.Errorf("%w: group threshold %d of %d groups", ErrInvalidThreshold, groupThreshold, len(groups))
+	}
+	for _, g := range groups {
+		// Copies of a 1-of-1 share are the same mnemonic
+		if g.MemberThreshold == 1 && g.MemberCount > 1 {
+			return nil, fmt.Errorf("%w: use 1-of-1 instead of 1-of-%d", ErrInvalidThreshold, g.MemberCount)
+		}
+	}
+
+	idBytes := make([]byte, 2)
+	if _, err := io.ReadFull(random, idBytes); err != nil {
+		return nil, err
+	}
+	identifier := int(idBytes[0])<<8 | int(idBytes[1])
+	identifier &= 1<<idLengthBits - 1
+
+	encryptedSecret := encrypt(masterSecret, []byte(passphrase), opts.IterationExponent, identifier, opts.Extendable)
+	groupShares, err := splitSecret(groupThreshold, len(groups), encryptedSecret, random)
+	if err != nil {
+		return nil, err
+	}
+	mnemonics := make([][]string, len(groups))
+	for i, g := range groups {
+		memberShares, err := splitSecret(g.MemberThreshold, g.MemberCount, groupShares[i].value, random)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range memberShares {
+			s := &Share{
+				Identifier:        identifier,
+				Extendable:        opts.Extendable,
+				IterationExponent: opts.IterationExponent,
+				GroupIndex:        groupShares[i].x,
+				GroupThreshold:    groupThreshold,
+				GroupCount:        len(groups),
+				MemberIndex:       member.x,
+				MemberThreshold:   g.MemberThreshold,
+				Value:             member.value,
+			}
+			mnemonics[i] = append(mnemonics[i], s.Mnemonic())
+		}
+	}
+	return mnemonics, nil
+}
+
+// CombineMnemonics recovers the master secret from the mnemonics of
+// exactly the threshold number of groups, each with exactly its member
+// threshold of shares. A wrong passphrase is not detected: it yields a
+// different master secret, which is what makes it deniable.
+func CombineMnemonics(mnemonics []string, passphrase string) ([]byte, error) {
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShares
+	}
+
+	var first *Share
+	groups := make(map[int][]*Share)
+	var groupOrder []int
+	for _, mnemonic := range mnemonics {
+		s, err := DecodeMnemonic(mnemonic)
+		if err != nil {
+			return nil, err
+		}
+		if first == nil {
+			first = s
+		}
+		if s.Identifier != first.Identifier || s.Extendable != first.Extendable || s.IterationExponent != first.IterationExponent {
+			return nil, fmt.Errorf("%w: the first %d words differ", ErrMismatchedShares, idExpLengthWords)
+		}
+		if s.GroupThreshold != first.GroupThreshold || s.GroupCount != first.GroupCount {
+			return nil, fmt.Errorf("%w: group thresholds or counts differ", ErrMismatchedShares)
+		}
+		group, ok := groups[s.GroupIndex]
+		if !ok {
+			groupOrder = append(groupOrder, s.GroupIndex)
+		}
+		if ok && s.MemberThreshold != group[0].MemberThreshold {
+			return nil, fmt.Errorf("%w: member thresholds of group %d differ", ErrMismatchedShares, s.GroupIndex)
+		}
+		if !containsShare(group, s) {
+			groups[s.GroupIndex] = append(group, s)
+		}
+	}
+
+	if len(groups) < first.GroupThreshold {
+		return nil, fmt.Errorf("%w: got %d groups, want %d", ErrInsufficientShares, len(groups), first.GroupThreshold)
+	}
+	if len(groups) > first.GroupThreshold {
+		return nil, fmt.Errorf("%w: got %d groups, want %d", ErrTooManyShares, len(groups), first.GroupThreshold)
+	}
+	groupShares := make([]rawShare, 0, len(groups))
+	for _, index := range groupOrder {
+		group := groups[index]
+		threshold := group[0].MemberThreshold
+		if len(group) < threshold {
+			return nil, fmt.Errorf("%w: got %d shares of group %d, want %d", ErrInsufficientShares, len(group), index, threshold)
+		}
+		if len(group) > threshold {
+			return nil, fmt.Errorf("%w: got %d shares of group %d, want %d", ErrTooManyShares, len(group), index, threshold)
+		}
+		memberShares := make([]rawShare, len(group))
+		for i, s := range group {
+			memberShares[i] = rawShare{s.MemberIndex, s.Value}
+		}
+		value, err := recoverSecret(threshold, memberShares)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, rawShare{index, value})
+	}
+	encryptedSecret, err := recoverSecret(first.GroupThreshold, groupShares)
+	if err != nil {
+		return nil, err
+	}
+	return decrypt(encryptedSecret, []byte(passphrase), first.IterationExponent, first.Identifier, first.Extendable), nil
+}
+
+// containsShare reports whether the same share was already given, which is
+// not an error.
+func containsShare(group []*Share, s *Share) bool {
+	for _, other := range group {
+		if other.MemberIndex == s.MemberIndex && bytes.Equal(other.Value, s.Value) {
+			return true
+		}
+	}
+	return false
+}
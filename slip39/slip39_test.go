@@ -0,0 +1,334 @@
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dubuqingfeng/signer/bip32"
+)
+
+// vector is [description, mnemonics, master secret, xprv] as in the official
+// vectors.json. An empty master secret marks a set that must be rejected.
+type vector struct {
+	Description  string
+	Mnemonics    []string
+	MasterSecret string
+	Xprv         string
+}
+
+func (v *vector) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &[]interface{}{&v.Description, &v.Mnemonics, &v.MasterSecret, &v.Xprv})
+}
+
+// loadVectors reads testdata/vectors.json, the SLIP-39 test vectors of
+// python-shamir-mnemonic, all encrypted with the passphrase "TREZOR".
+func loadVectors(t *testing.T) []vector {
+	t.Helper()
+	b, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []vector
+	if err := json.Unmarshal(b, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	return vectors
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range loadVectors(t) {
+		t.Run(v.Description, func(t *testing.T) {
+			secret, err := CombineMnemonics(v.Mnemonics, "TREZOR")
+			if v.MasterSecret == "" {
+				if err == nil {
+					t.Fatalf("CombineMnemonics() = %x, want an error", secret)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CombineMnemonics() error = %v", err)
+			}
+			if got := hex.EncodeToString(secret); got != v.MasterSecret {
+				t.Errorf("CombineMnemonics() = %v, want %v", got, v.MasterSecret)
+			}
+			key, err := bip32.NewMasterKey(secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := key.String(); got != v.Xprv {
+				t.Errorf("bip32.NewMasterKey() = %v, want %v", got, v.Xprv)
+			}
+			for _, mnemonic := range v.Mnemonics {
+				s, err := DecodeMnemonic(mnemonic)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := s.Mnemonic(); got != mnemonic {
+					t.Errorf("Mnemonic() = %v, want %v", got, mnemonic)
+				}
+			}
+		})
+	}
+}
+
+func TestCombineMnemonicsErrors(t *testing.T) {
+	wantErrs := map[string]error{
+		"Mnemonic with insufficient length":          ErrInvalidMnemonicLength,
+		"Mnemonic with invalid master secret length": ErrInvalidMnemonicLength,
+	}
+	// The invalid sets of the official vectors repeat for both secret sizes
+	for _, bits := range []string{"128 bits", "256 bits"} {
+		for description, err := range map[string]error{
+			"Mnemonic with invalid checksum (%s)":                                              ErrInvalidChecksum,
+			"Mnemonic with invalid padding (%s)":                                               ErrInvalidPadding,
+			"Basic sharing 2-of-3 (%s)":                                                        ErrInsufficientShares,
+			"Mnemonics with different identifiers (%s)":                                        ErrMismatchedShares,
+			"Mnemonics with different iteration exponents (%s)":                                ErrMismatchedShares,
+			"Mnemonics with mismatching group thresholds (%s)":                                 ErrMismatchedShares,
+			"Mnemonics with mismatching group counts (%s)":                                     ErrMismatchedShares,
+			"Mnemonics with greater group threshold than group counts (%s)":                    ErrInvalidThreshold,
+			"Mnemonics with duplicate member indices (%s)":                                     ErrDuplicateShare,
+			"Mnemonics with mismatching member thresholds (%s)":                                ErrMismatchedShares,
+			"Mnemonics giving an invalid digest (%s)":                                          ErrInvalidDigest,
+			"Insufficient number of groups (%s, case 1)":                                       ErrInsufficientShares,
+			"Insufficient number of groups (%s, case 2)":                                       ErrInsufficientShares,
+			"Threshold number of groups, but insufficient number of members in one group (%s)": ErrInsufficientShares,
+		} {
+			wantErrs[fmt.Sprintf(description, bits)] = err
+		}
+	}
+	for _, v := range loadVectors(t) {
+		if v.MasterSecret != "" {
+			continue
+		}
+		wantErr, ok := wantErrs[v.Description]
+		if !ok {
+			t.Errorf("%s: no expected error for the set", v.Description)
+			continue
+		}
+		if _, err := CombineMnemonics(v.Mnemonics, "TREZOR"); !errors.Is(err, wantErr) {
+			t.Errorf("%s: CombineMnemonics() error = %v, want %v", v.Description, err, wantErr)
+		}
+	}
+}
+
+// reencode returns the mnemonic with the share changed by edit, and a
+// valid checksum.
+func reencode(t *testing.T, mnemonic string, edit func(*Share)) string {
+	t.Helper()
+	s, err := DecodeMnemonic(mnemonic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit(s)
+	return s.Mnemonic()
+}
+
+// withPadding returns the mnemonic with the padding bits set, and a valid
+// checksum.
+func withPadding(t *testing.T, mnemonic string) string {
+	t.Helper()
+	words := strings.Fields(mnemonic)
+	data := make([]int, len(words)-checksumLengthWords)
+	for i := range data {
+		data[i] = wordIndexes[words[i]]
+	}
+	data[idExpLengthWords+2] |= 1 << (radixBits - 1)
+	data = append(data, rs1024CreateChecksum(data, false)...)
+	for i, index := range data {
+		words[i] = wordList[index]
+	}
+	return strings.Join(words, " ")
+}
+
+// TestCombineMnemonicsMismatched covers the share parameter checks, in
+// both secret sizes, with shares of the vectors edited and re-encoded.
+func TestCombineMnemonicsMismatched(t *testing.T) {
+	sets := map[string][2]string{
+		"128 bits": {
+			"shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue view short owner flip making coding armed",
+			"shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice unkind craft early superior advocate guest smoking",
+		},
+		"256 bits": {
+			"humidity disease academic always aluminum jewelry energy woman receiver strategy amuse duckling lying evidence network walnut tactics forget hairy rebound impulse brother survive clothes stadium mailman rival ocean reward venture always armed unwrap",
+			"humidity disease academic agency actress jacket gross physics cylinder solution fake mortgage benefit public busy prepare sharp friar change work slow purchase ruler again tricycle involve viral wireless mixture anatomy desert cargo upgrade",
+		},
+	}
+	tests := []struct {
+		name    string
+		edit    func(*Share)
+		wantErr error
+	}{
+		{name: "identifier", edit: func(s *Share) { s.Identifier ^= 1 }, wantErr: ErrMismatchedShares},
+		{name: "extendable", edit: func(s *Share) { s.Extendable = true }, wantErr: ErrMismatchedShares},
+		{name: "iteration exponent", edit: func(s *Share) { s.IterationExponent++ }, wantErr: ErrMismatchedShares},
+		{name: "group threshold", edit: func(s *Share) { s.GroupThreshold, s.GroupCount = 2, 2 }, wantErr: ErrMismatchedShares},
+		{name: "group count", edit: func(s *Share) { s.GroupCount = 2 }, wantErr: ErrMismatchedShares},
+		{name: "member threshold", edit: func(s *Share) { s.MemberThreshold = 3 }, wantErr: ErrMismatchedShares},
+		{name: "member index", edit: func(s *Share) { s.MemberIndex = 2 }, wantErr: ErrDuplicateShare},
+		{name: "value", edit: func(s *Share) { s.Value[0] ^= 1 }, wantErr: ErrInvalidDigest},
+	}
+	for size, set := range sets {
+		for _, tt := range tests {
+			t.Run(size+" "+tt.name, func(t *testing.T) {
+				mnemonics := []string{set[0], reencode(t, set[1], tt.edit)}
+				if _, err := CombineMnemonics(mnemonics, "TREZOR"); !errors.Is(err, tt.wantErr) {
+					t.Errorf("CombineMnemonics() error = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+		t.Run(size+" padding", func(t *testing.T) {
+			mnemonics := []string{set[0], withPadding(t, set[1])}
+			if _, err := CombineMnemonics(mnemonics, "TREZOR"); !errors.Is(err, ErrInvalidPadding) {
+				t.Errorf("CombineMnemonics() error = %v, want %v", err, ErrInvalidPadding)
+			}
+		})
+	}
+}
+
+func TestGenerateMnemonics(t *testing.T) {
+	secret := []byte("ABCDEFGHIJKLMNOP")
+	groups := []Group{{1, 1}, {2, 3}, {3, 5}}
+	tests := []struct {
+		name string
+		opts *Options
+	}{
+		{name: "default"},
+		{name: "extendable", opts: &Options{Extendable: true, IterationExponent: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mnemonics, err := GenerateMnemonics(2, groups, secret, "TREZOR", tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, g := range groups {
+				if len(mnemonics[i]) != g.MemberCount {
+					t.Fatalf("group %d has %d mnemonics, want %d", i, len(mnemonics[i]), g.MemberCount)
+				}
+			}
+
+			sets := [][]string{
+				{mnemonics[0][0], mnemonics[1][0], mnemonics[1][2]},
+				{mnemonics[2][4], mnemonics[1][1], mnemonics[2][0], mnemonics[1][2], mnemonics[2][3]},
+				{mnemonics[0][0], mnemonics[2][1], mnemonics[2][2], mnemonics[2][3]},
+			}
+			for _, set := range sets {
+				got, err := CombineMnemonics(set, "TREZOR")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, secret) {
+					t.Errorf("CombineMnemonics() = %x, want %x", got, secret)
+				}
+			}
+
+			// Any passphrase decrypts to some master secret
+			got, err := CombineMnemonics(sets[0], "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Equal(got, secret) {
+				t.Error("CombineMnemonics() with another passphrase recovered the master secret")
+			}
+			if _, err := CombineMnemonics(mnemonics[1][:2], "TREZOR"); !errors.Is(err, ErrInsufficientShares) {
+				t.Errorf("CombineMnemonics() of one group error = %v, want %v", err, ErrInsufficientShares)
+			}
+			if _, err := CombineMnemonics(append([]string{mnemonics[0][0]}, mnemonics[2][:4]...), "TREZOR"); !errors.Is(err, ErrTooManyShares) {
+				t.Errorf("CombineMnemonics() of four shares of a 3-of-5 group error = %v, want %v", err, ErrTooManyShares)
+			}
+		})
+	}
+}
+
+func TestGenerateMnemonicsErrors(t *testing.T) {
+	secret := bytes.Repeat([]byte{0x42}, 16)
+	tests := []struct {
+		name           string
+		groupThreshold int
+		groups         []Group
+		secret         []byte
+		passphrase     string
+		opts           *Options
+		wantErr        error
+	}{
+		{name: "short secret", groupThreshold: 1, groups: []Group{{1, 1}}, secret: secret[:14], wantErr: ErrInvalidSecretLength},
+		{name: "odd secret", groupThreshold: 1, groups: []Group{{1, 1}}, secret: append(secret, 0), wantErr: ErrInvalidSecretLength},
+		{name: "passphrase", groupThreshold: 1, groups: []Group{{1, 1}}, secret: secret, passphrase: "café", wantErr: ErrInvalidPassphrase},
+		{name: "iteration exponent", groupThreshold: 1, groups: []Group{{1, 1}}, secret: secret, opts: &Options{IterationExponent: 16}, wantErr: ErrInvalidIterationExponent},
+		{name: "group threshold", groupThreshold: 3, groups: []Group{{1, 1}, {1, 1}}, secret: secret, wantErr: ErrInvalidThreshold},
+		{name: "member threshold", groupThreshold: 1, groups: []Group{{3, 2}}, secret: secret, wantErr: ErrInvalidThreshold},
+		{name: "copies of a share", groupThreshold: 1, groups: []Group{{1, 3}}, secret: secret, wantErr: ErrInvalidThreshold},
+		{name: "too many members", groupThreshold: 1, groups: []Group{{2, 17}}, secret: secret, wantErr: ErrInvalidThreshold},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateMnemonics(tt.groupThreshold, tt.groups, tt.secret, tt.passphrase, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GenerateMnemonics() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDecodeMnemonicErrors(t *testing.T) {
+	mnemonic := "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard"
+	tests := []struct {
+		name     string
+		mnemonic string
+		wantErr  error
+	}{
+		{name: "unknown word", mnemonic: "ducklin" + mnemonic[8:], wantErr: ErrUnknownWord},
+		{name: "too short", mnemonic: "duckling enlarge academic academic agency result length solution fridge kidney", wantErr: ErrInvalidMnemonicLength},
+		// 21 words hold 140 bits of value, too many for 16 bytes and too few for 18
+		{name: "length", mnemonic: mnemonic + " keyboard", wantErr: ErrInvalidMnemonicLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeMnemonic(tt.mnemonic); !errors.Is(err, tt.wantErr) {
+				t.Errorf("DecodeMnemonic() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWordList(t *testing.T) {
+	if !sort.StringsAreSorted(wordList[:]) {
+		t.Error("the wordlist is not sorted")
+	}
+	prefixes := make(map[string]string, len(wordList))
+	for _, word := range wordList {
+		if len(word) < 4 || len(word) > 8 {
+			t.Errorf("%q is not 4 to 8 letters long", word)
+			continue
+		}
+		if other, ok := prefixes[word[:4]]; ok {
+			t.Errorf("%q and %q share their first four letters", other, word)
+		}
+		prefixes[word[:4]] = word
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	// Every share of a 3-of-5 split lies on the same polynomial
+	shares, err := splitSecret(3, 5, []byte("0123456789abcdef"), bytes.NewReader(bytes.Repeat([]byte{7, 99, 200}, 20)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for x := 0; x < 5; x++ {
+		got, err := interpolate(shares[2:], x)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, shares[x].value) {
+			t.Errorf("interpolate() at %d = %x, want %x", x, got, shares[x].value)
+		}
+	}
+}